@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/deploy"
+	"github.com/screa/erc2470-address-miner/internal/rpc"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// deployTimeout bounds the whole deployment, including waiting for the receipt
+const deployTimeout = 10 * time.Minute
+
+// factoryCheckTimeout bounds the startup check that each factory is deployed
+const factoryCheckTimeout = 15 * time.Second
+
+// checkFactories warns about each factory with no code on the --rpc-url chain. Mining goes
+// ahead either way: the factory may be deployed there later, or the salt used on another chain.
+func checkFactories() {
+	ctx, cancel := context.WithTimeout(context.Background(), factoryCheckTimeout)
+	defer cancel()
+	client := newRPCClient()
+	for _, f := range cfg.GetFactories() {
+		err := deploy.CheckFactory(ctx, client, f.Address)
+		if errors.Is(err, deploy.ErrNoFactoryCode) {
+			logger.Printf("Warning: factory %s has no code on the --rpc-url chain; a salt mined for it cannot be deployed there", f.Address)
+		} else if err != nil {
+			logger.Printf("Warning: could not check factory %s: %v", f.Address, err)
+		}
+	}
+}
+
+// deployMatch deploys a match through the factory it was mined for, after confirmation
+// unless --yes was given. It returns an error only if a deployment was attempted and failed.
+func deployMatch(r *types.Result) error {
+	key, err := deploy.ParsePrivateKey(cfg.GetPrivateKey())
+	if err != nil {
+		return err
+	}
+	initcodes, err := cfg.GetBytecodes()
+	if err != nil {
+		return err
+	}
+	var salt [32]byte
+	if _, err := hex.Decode(salt[:], []byte(r.Salt)); err != nil {
+		return fmt.Errorf("invalid salt %s: %w", r.Salt, err)
+	}
+
+	// Results name their factory only when several are mined
+	factories := cfg.GetFactories()
+	factory := factories[0]
+	for _, f := range factories {
+		if strings.EqualFold(f.Address, r.Factory) {
+			factory = f
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deployTimeout)
+	defer cancel()
+	d := deploy.New(cfg.RPCURL, key)
+	d.Client = newRPCClient()
+	plan, err := d.Prepare(ctx, factory.Address, factory.CreateX, salt, initcodes[0], r.Address)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Deployment: %s", plan)
+	if !cfg.Yes && !confirm("Broadcast this transaction? [y/N] ") {
+		logger.Printf("Deployment skipped")
+		return nil
+	}
+
+	res, err := d.Send(ctx, plan)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Deployed %s in tx %s (block %d, gas used %d)", res.Address, res.TxHash, res.Block, res.GasUsed)
+	return nil
+}
+
+// newRPCClient creates a client for --rpc-url with the --rpc-retries and --rpc-timeout settings
+func newRPCClient() *rpc.Client {
+	client := rpc.New(cfg.RPCURL)
+	client.Retries = cfg.RPCRetries
+	client.HTTP.Timeout = cfg.RPCTimeout
+	return client
+}
+
+// confirm asks a yes/no question on stdin; anything but y or yes, including EOF, is no
+func confirm(prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/spf13/cobra"
+)
+
+// newHashCmd creates the subcommand that prints the init code hash
+func newHashCmd() *cobra.Command {
+	hashCfg := config.NewConfig()
+
+	cmd := &cobra.Command{
+		Use:   "hash",
+		Short: "Print the keccak256 init code hash of the bytecode",
+		Long: `Compute keccak256(initCode) for the given bytecode and optional constructor arguments.
+The result can be plugged into external CREATE2 calculators.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if hashCfg.Bytecode == "" && len(hashCfg.BytecodeFiles) == 0 {
+				fmt.Printf("Error: %v\n", config.ErrNoBytecodeSpecified)
+				os.Exit(exitError)
+			}
+
+			initcodes, err := hashCfg.GetBytecodes()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(exitError)
+			}
+			for _, initcode := range initcodes {
+				fmt.Printf("0x%s\n", hex.EncodeToString(crypto.Keccak256(initcode)))
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&hashCfg.Bytecode, "bytecode", "B", "", "Contract bytecode (hex)")
+	cmd.Flags().StringArrayVarP(&hashCfg.BytecodeFiles, "bytecode-file", "F", nil, "File containing contract bytecode (hex); repeat to hash several")
+	cmd.Flags().StringVar(&hashCfg.ConstructorArgs, "constructor-args", "", "ABI-encoded constructor arguments (hex) appended to the bytecode")
+
+	return cmd
+}
+
+// printSaltFromBytecode prints the address each factory deploys the init code to when the salt
+// is the init code hash itself, for --salt-from-bytecode
+func printSaltFromBytecode(c *config.Config) error {
+	initCodeHash, err := c.GetInitCodeHash()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Salt (init code hash): 0x%s\n", hex.EncodeToString(initCodeHash))
+	for _, f := range c.GetFactories() {
+		factory, err := crypto.MustAddressBytes(f.Address)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Address: %s (factory %s)\n", crypto.CalculateCreate2AddressFor(factory, initCodeHash, initCodeHash), f.Address)
+	}
+	return nil
+}
+
+// writePreimage writes, for each factory, the CREATE2 preimage of the --dump-preimage salt
+// (0xff ++ factory ++ salt ++ init code hash, 85 bytes), its keccak256 and the address, so a
+// derivation can be checked against other tools. For CreateX the preimage holds the guarded salt.
+func writePreimage(w io.Writer, c *config.Config) error {
+	salt, err := c.ParseSaltInput(c.DumpPreimage)
+	if err != nil {
+		return err
+	}
+	initCodeHash, err := c.GetInitCodeHash()
+	if err != nil {
+		return err
+	}
+	newHasher, err := crypto.KeccakHasherFactory(crypto.DefaultKeccakBackend)
+	if err != nil {
+		return err
+	}
+	for _, f := range c.GetFactories() {
+		factory, err := crypto.MustAddressBytes(f.Address)
+		if err != nil {
+			return err
+		}
+		create2Salt := salt
+		if f.CreateX {
+			guard, err := crypto.ParseCreateXGuard(c.CreateXGuard)
+			if err != nil {
+				return err
+			}
+			var sender []byte
+			if guard == crypto.GuardMsgSender {
+				if sender, err = crypto.MustAddressBytes(c.CreateXSender); err != nil {
+					return err
+				}
+			}
+			crypto.CreateXGuardedSaltInto(newHasher(), guard, sender, c.ChainID, &salt, &create2Salt)
+		}
+		preimage := crypto.Create2Preimage(factory, create2Salt[:], initCodeHash)
+		hash := crypto.Keccak256(preimage[:])
+
+		fmt.Fprintf(w, "Factory:        %s\n", f.Address)
+		fmt.Fprintf(w, "Salt:           0x%x\n", salt)
+		if f.CreateX {
+			fmt.Fprintf(w, "Guarded salt:   0x%x (CreateX %s guard)\n", create2Salt, c.CreateXGuard)
+		}
+		fmt.Fprintf(w, "Init code hash: 0x%x\n", initCodeHash)
+		fmt.Fprintf(w, "Preimage:       0x%x\n", preimage)
+		fmt.Fprintf(w, "                ff | %x | %x | %x\n", factory, create2Salt, initCodeHash)
+		fmt.Fprintf(w, "Hash:           0x%x\n", hash)
+		if _, err := fmt.Fprintf(w, "Address:        %s\n", crypto.AddressBytesToChecksumString(hash[12:])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,73 +1,412 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/screa/erc2470-address-miner/internal/checkpoint"
+	"github.com/screa/erc2470-address-miner/internal/color"
 	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
 	logpkg "github.com/screa/erc2470-address-miner/internal/logger"
+	"github.com/screa/erc2470-address-miner/internal/matcher"
+	"github.com/screa/erc2470-address-miner/internal/webhook"
 	minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
 	"github.com/screa/erc2470-address-miner/pkg/types"
 	"github.com/spf13/cobra"
 )
 
+// Exit codes
+const (
+	exitMatch       = 0   // a match (or a best result in scoring modes) was found
+	exitError       = 1   // invalid configuration or runtime error
+	exitNoMatch     = 2   // limits were reached without a match
+	exitExhausted   = 3   // every salt in the --salt-end keyspace was tried without a match
+	exitInterrupted = 130 // stopped by SIGINT/SIGTERM
+)
+
 var (
-	cfg    = config.NewConfig()
-	logger *logpkg.Logger
+	cfg     = config.NewConfig()
+	logger  *logpkg.Logger
+	signKey ed25519.PrivateKey
+	palette color.Palette // plain unless --color and the output allow ANSI codes
+	workers string        // --workers: a count, or auto
 )
 
+// autoTuneBudget is how long --workers auto benchmarks each candidate worker count
+const autoTuneBudget = 500 * time.Millisecond
+
 func main() {
 	var rootCmd = &cobra.Command{
 		Use:   "erc2470-miner",
 		Short: "High-performance ERC-2470 address miner",
 		Long: `A performant command line utility for mining ERC-2470 addresses.
 This tool uses keccak256 hashing to find addresses with specific patterns.`,
-		Run: runMiner,
+		Run:     runMiner,
+		Version: Version,
 	}
 
-	rootCmd.Flags().IntVarP(&cfg.Workers, "workers", "w", runtime.NumCPU(), "Number of worker goroutines")
+	rootCmd.Flags().StringVarP(&workers, "workers", "w", strconv.Itoa(runtime.NumCPU()), "Number of worker goroutines, or auto to benchmark a few counts at startup")
+	rootCmd.Flags().IntVar(&cfg.Throttle, "throttle", 0, "Hold each worker to roughly this CPU percentage by sleeping between batches (trades throughput for responsiveness)")
+	rootCmd.Flags().BoolVar(&cfg.PinCPUs, "pin-cpus", false, "Pin each worker's thread to its own CPU (Linux only; ignored with a warning elsewhere)")
 	rootCmd.Flags().StringVarP(&cfg.Prefix, "prefix", "p", "", "Address prefix to match")
+	rootCmd.Flags().IntVar(&cfg.PrefixOffset, "prefix-offset", 0, "Skip this many leading hex characters before matching --prefix (e.g. 2 to ignore a forced first byte)")
+	rootCmd.Flags().StringVar(&cfg.PrefixBytes, "prefix-bytes", "", "Leading whole bytes to match, as even-length hex (rejects a half byte; --prefix compares hex characters)")
 	rootCmd.Flags().StringVarP(&cfg.Suffix, "suffix", "s", "", "Address suffix to match")
+	rootCmd.Flags().StringVar(&cfg.MatchTailOf, "match-tail-of", "", "Address whose tail becomes the suffix to match, so the new address ends like an existing one")
+	rootCmd.Flags().IntVar(&cfg.TailNibbles, "tail-nibbles", config.DefaultTailNibbles, "Hex characters of --match-tail-of to match")
+	rootCmd.Flags().StringVar(&cfg.Template, "template", "", "Hex template anchored at the start of the address; '.' or 'x' matches any character (may be shorter than 40 chars, e.g. dead....beef)")
+	rootCmd.Flags().BoolVar(&cfg.Palindrome, "palindrome", false, "Match addresses whose hex reads the same forwards and backwards (checksum casing ignored)")
+	rootCmd.Flags().BoolVar(&cfg.PalindromeChecksum, "palindrome-checksum", false, "Like --palindrome, but the EIP-55 checksummed casing must mirror too")
+	rootCmd.Flags().StringVar(&cfg.MatcherCmd, "matcher-cmd", "", "Program filtering matches: reads one address per line, answers accept or reject per line")
+	rootCmd.Flags().StringVar(&cfg.NumericProperty, "numeric-property", "", "Arithmetic predicate over the address as an integer, e.g. 'addr % 97 == 0', ANDed with the other criteria")
+	rootCmd.Flags().StringVar(&cfg.MatchExpr, "match-expr", "", "Boolean expression over prefix HEX, suffix HEX, contains HEX and zerobytes N with and/or/not and parentheses, ANDed with the other criteria")
+	rootCmd.Flags().StringVar(&cfg.NiceSet, "nice-set", "", "Match a built-in family of appealing addresses, keeping the best found: "+strings.Join(crypto.NiceSetNames(), ", "))
+	rootCmd.Flags().IntVar(&cfg.Repeating, "repeating", 0, "Match addresses containing a run of at least N identical hex characters")
+	rootCmd.Flags().BoolVar(&cfg.AllSame, "all-same", false, "Match addresses made of one repeated hex character (expected ~16^39 attempts; see --repeating for shorter runs)")
+	rootCmd.Flags().IntVar(&cfg.LowZeroBits, "low-zero-bits", 0, "Match addresses whose integer value has its low N bits zero (divisible by 2^N)")
+	rootCmd.Flags().IntVar(&cfg.PrefixBits, "prefix-bits", 0, "Match addresses whose top N bits equal those of --prefix-bits-pattern")
+	rootCmd.Flags().StringVar(&cfg.PrefixBitsPattern, "prefix-bits-pattern", "", "Hex whose leading bits --prefix-bits compares (default all zeros)")
+	rootCmd.Flags().StringVar(&cfg.ClosestTo, "closest-to", "", "Keep the address numerically closest to this address (reported on stop or timeout)")
+	rootCmd.Flags().StringVar(&cfg.Target, "target", "", "Exact address to match (40 hex chars, any casing)")
+	rootCmd.Flags().StringVar(&cfg.TargetFile, "target-file", "", "File of exact addresses, one per line; matching any of them is a hit")
+	rootCmd.Flags().BoolVar(&cfg.CaseSensitive, "case-sensitive", false, "Require --target in its EIP-55 checksummed casing instead of matching any casing")
 	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.Flags().BoolVar(&cfg.Analyze, "analyze", false, "Append a histogram of the result's hex characters, highlighting the rarest and absent ones")
 	rootCmd.Flags().StringVarP(&cfg.LogFile, "log-file", "l", "", "Log file for progress tracking (default: stdout)")
+	rootCmd.Flags().StringVar(&cfg.LogPrefix, "log-prefix", "", "Tag every log line with this string, e.g. an instance name")
+	rootCmd.Flags().StringVar(&cfg.RunID, "run-id", "", "Identify this run in every log line, result and the summary (default: 8 random hex characters)")
 	rootCmd.Flags().StringVarP(&cfg.Bytecode, "bytecode", "B", "", "Contract bytecode for CREATE2 address calculation (hex) (required)")
-	rootCmd.Flags().StringVarP(&cfg.BytecodeFile, "bytecode-file", "F", "", "File containing contract bytecode (hex) (required)")
+	rootCmd.Flags().StringArrayVarP(&cfg.BytecodeFiles, "bytecode-file", "F", nil, "File containing contract bytecode (hex) (required); repeat to require the pattern under every init code")
 	rootCmd.Flags().IntVarP(&cfg.LogInterval, "log-interval", "i", 5, "Logging interval in seconds (default: 5)")
+	rootCmd.Flags().IntVar(&cfg.ProgressEvery, "progress-every", 0, "Log verbose progress only every Nth interval; with --progress-on-improve, a heartbeat every Nth interval")
+	rootCmd.Flags().BoolVar(&cfg.ProgressOnImprove, "progress-on-improve", false, "Log verbose progress only when the best result improves, a longer prefix is reached or another multiple of the expected attempts passes")
+	rootCmd.Flags().Int64Var(&cfg.MaxAttempts, "max-attempts", 0, "Stop after this many attempts (0 = unlimited)")
+	rootCmd.Flags().DurationVar(&cfg.Timeout, "timeout", 0, "Stop after this long, e.g. 10m (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&cfg.BestEffort, "best-effort", false, "With --prefix and --timeout, report the longest partial prefix match when time runs out")
+	rootCmd.Flags().StringVar(&cfg.Summary, "summary", "", "Print a final summary of the run on exit: text or json")
+	rootCmd.Flags().BoolVar(&cfg.DeterministicWorkers, "deterministic-workers", false, "Report the matches at the lowest salts of a sequential or hd run, so every run finds the same salts")
+	rootCmd.Flags().BoolVar(&cfg.KeepSearching, "keep-searching", false, "Keep mining after a match until --timeout, --max-attempts or --salt-end, then report the best match")
+	rootCmd.Flags().IntVarP(&cfg.Count, "count", "n", 1, "Number of distinct matching addresses to find")
+	rootCmd.Flags().StringVar(&cfg.InitCodeHash, "initcode-hash", "", "keccak256 of the init code (32 bytes hex); use instead of --bytecode/--bytecode-file")
+	rootCmd.Flags().StringVar(&cfg.ConstructorArgs, "constructor-args", "", "ABI-encoded constructor arguments (hex) appended to the bytecode")
+	rootCmd.Flags().StringVar(&cfg.Mode, "mode", config.ModeFactory, "factory, or solidity-new: --factory is your contract running new Contract{salt: s}(args) and the init code is its creation code plus --constructor-args")
+	rootCmd.Flags().StringVar(&cfg.FactoryKind, "factory-kind", config.FactoryKindERC2470, "Factory to mine for: erc2470 or createx")
+	rootCmd.Flags().StringArrayVar(&cfg.Factories, "factory", nil, "Factory to mine for, overriding the --factory-kind default: erc2470, createx, arachnid or an address (EIP-55 checksum verified); repeat to match under any of several")
+	rootCmd.Flags().BoolVar(&cfg.NoChecksumCheck, "no-checksum-check", false, "Warn instead of failing when --factory does not match its EIP-55 checksum")
+	rootCmd.Flags().StringVar(&cfg.CreateXGuard, "createx-guard", "", "CreateX salt guard: none, msgsender or crosschain (requires --factory-kind createx)")
+	rootCmd.Flags().StringVar(&cfg.CreateXSender, "createx-sender", "", "Deployer (msg.sender) address for --createx-guard msgsender")
+	rootCmd.Flags().Uint64Var(&cfg.ChainID, "chain-id", 0, "Chain id for --createx-guard crosschain")
+	rootCmd.Flags().StringVar(&cfg.SaltMode, "salt-mode", config.SaltModeRandom, "Salt generation: random, sequential or hd")
+	rootCmd.Flags().StringVar(&cfg.Entropy, "entropy", config.EntropyCrypto, "Entropy for random salts: crypto, os-hybrid or file")
+	rootCmd.Flags().StringVar(&cfg.EntropyFile, "entropy-file", "", "File or device (e.g. a hardware RNG) read for seeds with --entropy file")
+	rootCmd.Flags().BoolVar(&cfg.DebugCollisions, "debug-collisions", false, "Sample generated salts and count duplicates, which indicate workers sharing random generator state")
+	rootCmd.Flags().StringVar(&cfg.MnemonicFile, "mnemonic-file", "", "File holding a BIP-39 mnemonic; with --salt-mode hd salts are the keys at m/i'")
+	rootCmd.Flags().Uint32Var(&cfg.DerivationIndex, "derivation-index", 0, "First child index i to derive in --salt-mode hd")
+	rootCmd.Flags().StringVar(&cfg.ResumeFrom, "resume-from", "", "Start a sequential search just after this salt (at most 32 bytes)")
+	rootCmd.Flags().BoolVar(&cfg.SmokeTest, "smoke-test", false, "Mine a one-byte prefix with the real bytecode and factory, verify the result and exit")
+	rootCmd.Flags().BoolVar(&cfg.SaltFromBytecode, "salt-from-bytecode", false, "Print the one address whose salt is keccak256 of the init code, without mining")
+	rootCmd.Flags().StringVar(&cfg.DumpPreimage, "dump-preimage", "", "Print the CREATE2 preimage (0xff ++ factory ++ salt ++ init code hash), its hash and the address for this salt, without mining")
+	rootCmd.Flags().StringVar(&cfg.SaltLabel, "salt-label", "", "ASCII label (up to 24 characters) spelled by the leading salt bytes; only the rest is mined")
+	rootCmd.Flags().IntVar(&cfg.SaltBytes, "salt-bytes", 0, "Vary only the last N salt bytes, zeroing the rest; sequential searches stop once all are tried (0 = all 32)")
+	rootCmd.Flags().StringVar(&cfg.SaltEnd, "salt-end", "", "Stop a sequential search after this salt, reporting when the whole range holds no match")
+	rootCmd.Flags().StringVar(&cfg.SaltFormat, "salt-input-format", "hex", "Format of salt inputs such as --resume-from: hex or decimal")
+	rootCmd.Flags().StringVar(&cfg.Color, "color", color.ModeAuto, "Color result output: auto (only on a terminal without NO_COLOR), always or never")
+	rootCmd.Flags().StringVar(&cfg.KeccakBackend, "keccak-backend", crypto.DefaultKeccakBackend, "Keccak implementation: x-crypto, generic or auto (benchmark at startup)")
+	rootCmd.Flags().IntVar(&cfg.TopK, "top-k", 0, "In scoring modes, also keep and report the N best results (bounded per worker)")
+	rootCmd.Flags().IntVar(&cfg.MaxMemory, "max-memory", 0, "Soft heap limit in MB: near it, trim the --top-k candidates kept and pause result streaming (0 = off)")
+	rootCmd.Flags().StringVar(&cfg.Best, "best", config.BestLowest, "Which address wins in zero-prefix mode and among multiple matches: lowest or highest")
+	rootCmd.Flags().BoolVar(&cfg.Words, "words", false, "Keep the address containing the most hex words (dead, beef, cafe, ...)")
+	rootCmd.Flags().BoolVar(&cfg.Ascending, "ascending", false, "Keep the address with the longest run of hex characters counting up (e.g. 3456789a)")
+	rootCmd.Flags().StringVar(&cfg.Score, "score", "", "Keep the address scoring best in this mode: gas (leading zero bytes, then total zero bytes)")
+	rootCmd.Flags().StringVar(&cfg.WordsFile, "words-file", "", "Word list for --words, one hex word per line (replaces the built-in list)")
+	rootCmd.Flags().StringVar(&cfg.BestLog, "best-log", "", "Append a JSON line to this file each time the best result improves")
+	rootCmd.Flags().StringVar(&cfg.SaveConfig, "save-config", "", "Write the effective configuration, with the resolved factories and init code hash, to this JSON file")
+	rootCmd.Flags().StringVar(&cfg.OutputDir, "output-dir", "", "Write each match to <address>.json in this directory, skipping addresses already written")
+	rootCmd.Flags().StringVar(&cfg.RateCSV, "rate-csv", "", "Append timestamp,attempts,rate to this CSV file at each progress tick (see --log-interval)")
+	rootCmd.Flags().BoolVar(&cfg.Deploy, "deploy", false, "Deploy the first match through its factory via --rpc-url, after confirmation")
+	rootCmd.Flags().IntVar(&cfg.RPCRetries, "rpc-retries", cfg.RPCRetries, "Retry an RPC call this many times after a network error, HTTP 5xx or 429, with exponential backoff")
+	rootCmd.Flags().DurationVar(&cfg.RPCTimeout, "rpc-timeout", cfg.RPCTimeout, "Time limit of each RPC request")
+	rootCmd.Flags().StringVar(&cfg.RPCURL, "rpc-url", "", "Ethereum JSON-RPC endpoint used by --deploy, and to warn at startup if a factory is not deployed")
+	rootCmd.Flags().StringVar(&cfg.PrivateKey, "private-key", "", "Hex private key signing the deployment (prefer the "+config.PrivateKeyEnv+" environment variable)")
+	rootCmd.Flags().BoolVarP(&cfg.Yes, "yes", "y", false, "Deploy without asking for confirmation")
+	rootCmd.Flags().StringVar(&cfg.ExpvarAddr, "expvar-addr", "", "Serve live attempts, rate and bestAddress as expvar JSON at /debug/vars on this address")
+	rootCmd.Flags().StringVar(&cfg.Webhook, "webhook", "", "POST each match as JSON to this URL (best-effort, retried once)")
+	rootCmd.Flags().StringVar(&cfg.Checkpoint, "checkpoint", "", "Save progress to this file each progress tick; resume from it if it exists")
+	rootCmd.Flags().StringVar(&cfg.StateFile, "state-file", "", "Random mode: keep the attempt total and best result in this file each progress tick, and continue from them if it exists")
+	rootCmd.Flags().StringVar(&cfg.AuditLog, "audit-log", "", "Append near-miss candidates (shorter prefix matches) to this file as JSON lines")
+	rootCmd.Flags().IntVar(&cfg.AuditThreshold, "audit-threshold", 0, "Prefix characters a near-miss must match (default: prefix length minus 2)")
+	rootCmd.Flags().StringVar(&cfg.Print, "print", "", "Write only the salt, address or both of each result to stdout, one line per result, with logs on stderr")
+	rootCmd.Flags().StringVar(&cfg.Format, "format", "", "Write each result to stdout as create2crunch does (0x<salt> => <address> => <leading zero bytes> => <total zero bytes>), with logs on stderr")
+	rootCmd.Flags().StringVar(&cfg.SignKey, "sign-key", "", "File containing an ed25519 key (hex) used to sign the found salt and address")
+
+	rootCmd.AddCommand(newHashCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newRecoverCmd())
+	rootCmd.AddCommand(newMergeCheckpointsCmd())
+	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newSelfTestCmd())
+	rootCmd.AddCommand(newConfigSchemaCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
 
 func runMiner(cmd *cobra.Command, args []string) {
 	// Validate configuration
+	if err := cfg.SetWorkers(workers); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(exitError)
+	}
 	if err := cfg.Validate(); err != nil {
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+
+	// A salt fixed to the init code hash leaves nothing to mine
+	if cfg.SaltFromBytecode {
+		if err := printSaltFromBytecode(cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	}
+	if cfg.DumpPreimage != "" {
+		if err := writePreimage(os.Stdout, cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
+	// Load signing key up front so a bad key fails before mining starts
+	if cfg.SignKey != "" {
+		key, err := crypto.LoadSigningKey(cfg.SignKey)
+		if err != nil {
+			fmt.Printf("Error: failed to load signing key: %v\n", err)
+			os.Exit(exitError)
+		}
+		signKey = key
+	}
+
+	// Pick up where an earlier run left off when its checkpoint exists
+	prior, err := loadCheckpoint()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(exitError)
+	}
+	state, err := loadState()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	// Tag the run so its log lines and results can be correlated across a fleet
+	if cfg.RunID == "" {
+		cfg.RunID = config.NewRunID()
 	}
 
 	// Setup logging
 	setupLogging()
-	logger.Printf("Starting ERC-2470 address miner with %d workers...", cfg.Workers)
-	logger.Printf("Target: %s", cfg.GetTargetDescription())
-	logger.Printf("Factory address: 0xce0042B868300000d44A59004Da54A005ffdcf9f")
-	if cfg.BytecodeFile != "" {
-		logger.Printf("Bytecode file: %s", cfg.BytecodeFile)
+	if cfg.SmokeTest {
+		runSmokeTest()
+		return
+	}
+	if cfg.AutoWorkers {
+		logger.Printf("Starting ERC-2470 address miner, tuning the worker count...")
+	} else {
+		logger.Printf("Starting ERC-2470 address miner with %d workers...", cfg.Workers)
+	}
+	if cfg.Throttle > 0 && cfg.Throttle < 100 {
+		logger.Printf("Throttling each worker to about %d%% CPU", cfg.Throttle)
+	}
+	if prior != nil {
+		logger.Printf("Resuming from checkpoint %s: %d prior attempts", cfg.Checkpoint, prior.Attempts)
+		if cfg.SaltMode == config.SaltModeSequential && cfg.ResumeFrom != "" {
+			logger.Printf("Sequential search continues after salt %s", cfg.ResumeFrom)
+		}
+	}
+	if state != nil {
+		logger.Printf("Continuing from state file %s: %d prior attempts", cfg.StateFile, state.Attempts)
+		if state.Best != nil && cfg.TracksBest() {
+			logger.Printf("Best so far: %s (salt 0x%s)", state.Best.Address, state.Best.Salt)
+		}
+	}
+	if difficulty := cfg.GetTargetDifficulty(); difficulty != "" {
+		logger.Printf("Target: %s (%s)", cfg.GetTargetDescription(), difficulty)
+	} else {
+		logger.Printf("Target: %s", cfg.GetTargetDescription())
+	}
+	for _, warning := range cfg.Warnings() {
+		logger.Printf("Warning: %s", warning)
+	}
+	if (cfg.Target != "" || cfg.TargetFile != "") && cfg.SaltMode != config.SaltModeSequential && cfg.MaxAttempts == 0 {
+		logger.Printf("Warning: matching a full address is only feasible in a small keyspace; use --salt-mode sequential with --resume-from and --max-attempts")
+	}
+	if cfg.Mode == config.ModeSolidityNew {
+		logger.Printf("Deploying contract (new{salt: ...}): %s", cfg.GetFactories()[0].Address)
+		logger.Printf("Init code: type(Contract).creationCode plus the ABI-encoded constructor arguments")
+	} else {
+		for _, f := range cfg.GetFactories() {
+			logger.Printf("Factory address: %s", f.Address)
+		}
+	}
+	if cfg.RPCURL != "" {
+		checkFactories()
+	}
+	if cfg.UsesCreateX() {
+		guard := cfg.CreateXGuard
+		if guard == "" {
+			guard = "none"
+		}
+		logger.Printf("CreateX guard: %s", guard)
+	}
+	if cfg.SaltMode == config.SaltModeSequential {
+		if cfg.ResumeFrom != "" {
+			logger.Printf("Salt mode: sequential, resuming after %s", cfg.ResumeFrom)
+		} else {
+			logger.Printf("Salt mode: sequential")
+		}
+		if cfg.SaltEnd != "" {
+			logger.Printf("Salt end: %s", cfg.SaltEnd)
+		}
+	}
+	if cfg.SaltBytes > 0 {
+		logger.Printf("Salt bytes: the last %d of 32 vary, a keyspace of 2^%d = %s salts", cfg.SaltBytes, 8*cfg.SaltBytes, cfg.SaltKeyspace())
+	}
+	if cfg.Entropy == config.EntropyOSHybrid {
+		logger.Printf("Salt entropy: os-hybrid (random bytes, run stamp, worker ID and counter)")
+	} else if cfg.Entropy == config.EntropyFile {
+		logger.Printf("Salt entropy: %s", cfg.EntropyFile)
+	}
+	if cfg.SaltMode == config.SaltModeHD {
+		logger.Printf("Salt mode: hd, deriving m/i' from index %d (%s)", cfg.DerivationIndex, cfg.MnemonicFile)
+	}
+	if len(cfg.BytecodeFiles) > 0 {
+		for _, file := range cfg.BytecodeFiles {
+			logger.Printf("Bytecode file: %s", file)
+		}
+		if len(cfg.BytecodeFiles) > 1 {
+			logger.Printf("Warning: the pattern must match under all %d init codes; expected attempts are the single init code difficulty raised to the power %d",
+				len(cfg.BytecodeFiles), len(cfg.BytecodeFiles))
+		}
 	} else if cfg.Bytecode != "" {
 		logger.Printf("Bytecode: %s...", cfg.Bytecode[:min(20, len(cfg.Bytecode))])
+	} else if cfg.InitCodeHash != "" {
+		logger.Printf("Init code hash: %s", cfg.InitCodeHash)
 	}
 
+	// Set up signal handling for Ctrl+C before anything slow, so auto-tuning can be interrupted too
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
 	// Create miner and start mining
 	miner := minerpkg.NewMiner(cfg, logger)
+	if cfg.Verbose {
+		logger.Printf("Keccak backend: %s", cfg.KeccakBackend)
+	}
+	if cfg.AutoWorkers {
+		if err := autoTuneWorkers(miner, sigChan); err != nil {
+			logger.Println(palette.Progress("Received interrupt signal while tuning the worker count. Exiting."))
+			os.Exit(exitInterrupted)
+		}
+	}
+	if cfg.SaveConfig != "" {
+		if err := cfg.Save(cfg.SaveConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save configuration: %v\n", err)
+			os.Exit(exitError)
+		}
+		logger.Printf("Configuration saved to %s", cfg.SaveConfig)
+	}
+	if cfg.BestLog != "" {
+		file, err := os.OpenFile(cfg.BestLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open best log: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer file.Close()
+		miner.SetBestLog(file)
+	}
+	if cfg.Checkpoint != "" {
+		miner.SetCheckpoint(cfg.Checkpoint, prior)
+	}
+	if cfg.StateFile != "" {
+		miner.SetStateFile(cfg.StateFile, state)
+	}
+	if cfg.OutputDir != "" {
+		if err := os.MkdirAll(cfg.OutputDir, 0777); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create output directory: %v\n", err)
+			os.Exit(exitError)
+		}
+		miner.SetOutputDir(cfg.OutputDir)
+	}
+	if cfg.RateCSV != "" {
+		file, err := os.OpenFile(cfg.RateCSV, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open rate CSV: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer file.Close()
+		// Write the header only when starting a new file
+		if info, err := file.Stat(); err == nil && info.Size() == 0 {
+			fmt.Fprintln(file, "timestamp,attempts,rate")
+		}
+		miner.SetRateCSV(file)
+	}
+	if cfg.AuditLog != "" {
+		file, err := os.OpenFile(cfg.AuditLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer file.Close()
+		miner.SetAuditLog(file)
+	}
+	if cfg.MatcherCmd != "" {
+		m, err := matcher.Start(cfg.MatcherCmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start matcher: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer m.Close()
+		miner.SetMatcher(m)
+	}
+	if cfg.ExpvarAddr != "" {
+		addr, err := miner.ServeExpvar(cfg.ExpvarAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start expvar server: %v\n", err)
+			os.Exit(exitError)
+		}
+		logger.Printf("Serving statistics at http://%s/debug/vars", addr)
+	}
 
-	// Set up signal handling for Ctrl+C
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// Dump the current status on SIGUSR1 (not available on Windows)
+	stopStatus := watchStatusSignal(miner)
+	defer stopStatus()
+
+	// Pause on SIGTSTP and resume on SIGCONT (not available on Windows)
+	stopPause := watchPauseSignals(miner)
+	defer stopPause()
+
+	// Snapshot allocation stats so verbose mode can report the cost of the run
+	var memBefore runtime.MemStats
+	if cfg.Verbose {
+		runtime.ReadMemStats(&memBefore)
+	}
 
 	// Start mining in a goroutine
 	resultChan := make(chan *types.Result, 1)
@@ -79,56 +418,352 @@ func runMiner(cmd *cobra.Command, args []string) {
 	// Wait for either completion or signal
 	select {
 	case result := <-resultChan:
-		// Mining completed normally
-		if result != nil {
-			logger.Printf("🎉 Found match!")
-			logger.Printf("Salt: 0x%s", result.Salt)
-			logger.Printf("Address: %s", result.Address)
-			logger.Printf("Attempts: %d", result.Attempts)
-			logger.Printf("Duration: %v", result.Duration)
-
-			// Calculate rate safely
-			rate := 0.0
-			if result.Duration.Seconds() > 0 {
-				rate = float64(result.Attempts) / result.Duration.Seconds()
+		// Mining completed normally or hit its limits
+		if cfg.Verbose {
+			logMemStats(&memBefore, miner.Attempts())
+		}
+		if err := miner.MatcherErr(); err != nil {
+			fmt.Fprintf(os.Stderr, "Matcher failed: %v\n", err)
+			os.Exit(exitError)
+		}
+		results := miner.Results()
+		if len(results) > 1 {
+			logger.Print(palette.Success(fmt.Sprintf("🎉 Found %d matches!", len(results))))
+			for i, r := range results {
+				logger.Printf("Match %d:", i+1)
+				logResult(miner, r)
 			}
-			logger.Printf("Rate: %.2f hashes/sec", rate)
+		} else if len(results) == 1 {
+			logger.Print(palette.Success("🎉 Found match!"))
+			logResult(miner, results[0])
+		}
+		if len(results) > 0 {
+			printFields(results)
+			printSummary(miner, outcomeMatch, results)
+		} else if result != nil {
+			// Scoring modes track a best result even without a match
+			if miner.Exhausted() {
+				keyspace, _ := miner.Keyspace()
+				logger.Print(palette.Progress(fmt.Sprintf("Keyspace exhausted: all %d salts tried.", keyspace)))
+			}
+			logger.Print(palette.Progress(fmt.Sprintf("Limit reached without a match. Best result (%s):", bestDescription())))
+			if cfg.BestEffort && !cfg.TracksBest() {
+				logger.Printf("Prefix chars matched: %d of %d", result.Score, len(strings.TrimPrefix(cfg.Prefix, "0x")))
+			}
+			logResult(miner, result)
+			logTopResults(miner)
+			printFields([]*types.Result{result})
+			printSummary(miner, outcomeBest, []*types.Result{result})
+		} else if miner.Exhausted() {
+			keyspace, _ := miner.Keyspace()
+			logger.Println(palette.Progress(fmt.Sprintf("Keyspace exhausted: all %d salts tried, no match exists in the range.", keyspace)))
+			printSummary(miner, outcomeExhausted, nil)
+			os.Exit(exitExhausted)
 		} else {
-			logger.Println("No match found.")
+			logger.Println(palette.Progress("No match found."))
+			printSummary(miner, outcomeNoMatch, nil)
+			os.Exit(exitNoMatch)
+		}
+		notifyWebhook(results)
+		if cfg.Deploy && len(results) > 0 {
+			if err := deployMatch(results[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "Deployment failed: %v\n", err)
+				os.Exit(exitError)
+			}
 		}
 	case <-sigChan:
 		// Interrupted by Ctrl+C
-		logger.Println("\nReceived interrupt signal (Ctrl+C). Stopping miners...")
+		logger.Println(palette.Progress("\nReceived interrupt signal (Ctrl+C). Stopping miners..."))
 
 		// Stop the miner
 		miner.Stop()
 
 		// Wait for mining to stop
 		<-resultChan
+		if cfg.Verbose {
+			logMemStats(&memBefore, miner.Attempts())
+		}
+		stats := miner.Stats()
+		logger.Printf("Stopped after %d attempts in %v (%.2f hashes/sec)",
+			stats.Attempts, stats.Elapsed.Round(time.Millisecond), stats.Rate)
 
-		// If prefix is zeros, output the current best result
-		if cfg.IsZeroPrefix() {
+		// In scoring modes, output the current best result
+		var reported []*types.Result
+		if cfg.TracksBest() {
 			bestResult := miner.GetBestResult()
 			if bestResult != nil {
-				logger.Printf("Current best result (lowest address found):")
-				logger.Printf("Salt: 0x%s", bestResult.Salt)
-				logger.Printf("Address: %s", bestResult.Address)
-				logger.Printf("Attempts: %d", bestResult.Attempts)
-				logger.Printf("Duration: %v", bestResult.Duration)
-
-				// Calculate rate safely
-				rate := 0.0
-				if bestResult.Duration.Seconds() > 0 {
-					rate = float64(bestResult.Attempts) / bestResult.Duration.Seconds()
-				}
-				logger.Printf("Rate: %.2f hashes/sec", rate)
+				logger.Printf("Current best result (%s):", bestDescription())
+				logResult(miner, bestResult)
+				logTopResults(miner)
+				reported = append(reported, bestResult)
 			} else {
-				logger.Println("No addresses found matching the zero prefix.")
+				logger.Println("No addresses scored before the interrupt.")
 			}
 		} else {
 			logger.Println("Mining stopped by user.")
+			reported = miner.Results()
+		}
+		printFields(reported)
+		printSummary(miner, outcomeInterrupted, reported)
+		notifyWebhook(miner.Results())
+		os.Exit(exitInterrupted)
+	}
+}
+
+// loadCheckpoint reads the --checkpoint file if it exists. In sequential mode its resume point
+// becomes --resume-from unless one was given explicitly.
+func loadCheckpoint() (*checkpoint.Checkpoint, error) {
+	if cfg.Checkpoint == "" {
+		return nil, nil
+	}
+	cp, err := checkpoint.Load(cfg.Checkpoint)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if cp.Target != cfg.GetTargetDescription() {
+		return nil, fmt.Errorf("checkpoint %s is for %q, not %q", cfg.Checkpoint, cp.Target, cfg.GetTargetDescription())
+	}
+
+	if cfg.SaltMode == config.SaltModeSequential && cfg.ResumeFrom == "" && cp.ResumeFrom != "" {
+		salt, err := crypto.ParseHexSalt(cp.ResumeFrom)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint resume point: %w", err)
 		}
+		format, _ := crypto.ParseSaltFormat(cfg.SaltFormat)
+		cfg.ResumeFrom = crypto.FormatSalt(salt, format)
 	}
+	return cp, nil
+}
+
+// loadState reads the --state-file of an earlier random-mode run, if there is one
+func loadState() (*checkpoint.State, error) {
+	if cfg.StateFile == "" {
+		return nil, nil
+	}
+	st, err := checkpoint.LoadState(cfg.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state file: %w", err)
+	}
+	if st.Target != cfg.GetTargetDescription() {
+		return nil, fmt.Errorf("state file %s is for %q, not %q", cfg.StateFile, st.Target, cfg.GetTargetDescription())
+	}
+	return st, nil
+}
+
+// notifyWebhook POSTs each match to --webhook. Failures are logged only: the results
+// were already printed locally.
+func notifyWebhook(results []*types.Result) {
+	if cfg.Webhook == "" {
+		return
+	}
+	client := webhook.New(cfg.Webhook)
+	for _, r := range results {
+		if err := client.Send(r); err != nil {
+			logger.Printf("Warning: webhook for %s failed: %v", r.Address, err)
+			continue
+		}
+		logger.Printf("Webhook notified for %s", r.Address)
+	}
+}
+
+// logTopResults prints the --top-k runners-up after the best result
+func logTopResults(m *minerpkg.Miner) {
+	if cfg.TopK <= 1 {
+		return
+	}
+	top := m.TopResults()
+	if len(top) <= 1 {
+		return
+	}
+	logger.Printf("Top %d results:", len(top))
+	for i, r := range top {
+		logger.Printf("#%d: %s (salt 0x%s)", i+1, r.Address, r.Salt)
+	}
+}
+
+// bestDescription names what the best result is in the active scoring mode
+func bestDescription() string {
+	if cfg.BestEffort && !cfg.TracksBest() {
+		return "longest prefix match found"
+	}
+	if cfg.Words {
+		return "most hex words found"
+	}
+	if cfg.Ascending {
+		return "longest ascending run found"
+	}
+	if cfg.Score == config.ScoreGas {
+		return "most gas-efficient address found"
+	}
+	if cfg.NiceSet != "" {
+		return "best " + cfg.NiceSet + " address found"
+	}
+	if cfg.ClosestTo != "" {
+		return "closest address found"
+	}
+	if cfg.Best == config.BestHighest {
+		return "highest address found"
+	}
+	return "lowest address found"
+}
+
+// logResult prints the details of a found result, signing it when a key is loaded
+func logResult(m *minerpkg.Miner, result *types.Result) {
+	logger.Printf("Salt: 0x%s", result.Salt)
+	if result.SaltLabel != "" {
+		logger.Printf("Salt label: %q", result.SaltLabel)
+	}
+	logger.Printf("Address: %s", result.Address)
+	logger.Printf("Address (lowercase): %s", result.AddressLower)
+	if b, err := crypto.MustAddressBytes(result.Address); err == nil {
+		if line, ok := m.TargetLine([20]byte(b)); ok {
+			logger.Printf("Matched target: line %d of %s", line, cfg.TargetFile)
+		}
+	}
+	if result.Factory != "" {
+		logger.Printf("Factory: %s", result.Factory)
+	}
+	if result.InitCodeHash != "" {
+		logger.Printf("Init code hash: 0x%s", result.InitCodeHash)
+	}
+	if cfg.Mode == config.ModeSolidityNew {
+		logger.Printf("Solidity: new Contract{salt: bytes32(0x%s)}(...)", result.Salt)
+	}
+	if result.Create2Salt != "" {
+		logger.Printf("CREATE2 salt (CreateX guarded): 0x%s", result.Create2Salt)
+	}
+	if result.DerivationIndex != nil {
+		logger.Printf("Derivation path: m/%d'", *result.DerivationIndex)
+	}
+	for _, addr := range result.ExtraAddresses {
+		logger.Printf("Address (additional init code): %s", addr)
+	}
+	if cfg.Words {
+		logger.Printf("Words: %d", result.Score)
+	}
+	if cfg.Ascending {
+		logger.Printf("Ascending run: %d", result.Score)
+	}
+	if cfg.Score == config.ScoreGas {
+		if b, err := crypto.MustAddressBytes(result.Address); err == nil {
+			leading, total := crypto.ZeroBytes(b)
+			logger.Printf("Zero bytes: %d leading, %d total", leading, total)
+		}
+	}
+	if cfg.ClosestTo != "" {
+		logger.Printf("Distance: 0x%s", crypto.DistanceTo(result.Address, cfg.ClosestTo).Text(16))
+	}
+	if cfg.Analyze {
+		logAnalysis(result.Address)
+	}
+	logger.Printf("Attempts: %d", result.Attempts)
+	logger.Printf("Duration: %v", result.Duration)
+
+	// Calculate rate safely
+	rate := 0.0
+	if result.Duration.Seconds() > 0 {
+		rate = float64(result.Attempts) / result.Duration.Seconds()
+	}
+	logger.Printf("Rate: %.2f hashes/sec", rate)
+
+	if signKey != nil {
+		sig, err := signResult(result)
+		if err != nil {
+			logger.Printf("Failed to sign result: %v", err)
+			return
+		}
+		logger.Printf("Signature: 0x%s", hex.EncodeToString(sig))
+		logger.Printf("Public key: 0x%s", hex.EncodeToString(signKey.Public().(ed25519.PublicKey)))
+	}
+}
+
+// analyzeRarest is how many of the least frequent present characters --analyze names
+const analyzeRarest = 3
+
+// logAnalysis prints the character frequencies of an address, then the characters it lacks
+// and its rarest ones
+func logAnalysis(address string) {
+	counts := crypto.NibbleHistogram(address)
+	var hist, absent []string
+	var present []int
+	for d, n := range counts {
+		hist = append(hist, fmt.Sprintf("%x:%d", d, n))
+		if n == 0 {
+			absent = append(absent, fmt.Sprintf("%x", d))
+		} else {
+			present = append(present, d)
+		}
+	}
+	logger.Printf("Nibble histogram: %s", strings.Join(hist, " "))
+	if len(absent) > 0 {
+		logger.Printf("Absent: %s", strings.Join(absent, " "))
+	}
+
+	sort.SliceStable(present, func(i, j int) bool { return counts[present[i]] < counts[present[j]] })
+	var rarest []string
+	for _, d := range present[:min(analyzeRarest, len(present))] {
+		rarest = append(rarest, fmt.Sprintf("%x (%d)", d, counts[d]))
+	}
+	logger.Printf("Rarest: %s", strings.Join(rarest, ", "))
+}
+
+// autoTuneWorkers benchmarks the --workers auto candidates and logs their rates. A signal
+// arriving meanwhile cancels the benchmark and is returned as an error.
+func autoTuneWorkers(miner *minerpkg.Miner, sigChan <-chan os.Signal) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	rates, err := miner.AutoTuneWorkers(ctx, runtime.NumCPU(), autoTuneBudget)
+	if err != nil {
+		return err
+	}
+	for _, r := range rates {
+		logger.Printf("Workers %d: %.0f attempts/sec", r.Workers, r.Rate)
+	}
+	logger.Printf("Using %d workers", cfg.Workers)
+	return nil
+}
+
+// signResult signs the canonical salt||address bytes of a result with the loaded key
+func signResult(result *types.Result) ([]byte, error) {
+	saltBytes, err := hex.DecodeString(result.Salt)
+	if err != nil || len(saltBytes) != 32 {
+		return nil, fmt.Errorf("invalid salt %q", result.Salt)
+	}
+	addrBytes, err := crypto.MustAddressBytes(result.Address)
+	if err != nil {
+		return nil, err
+	}
+	var salt [32]byte
+	copy(salt[:], saltBytes)
+	return crypto.SignResult(signKey, salt, addrBytes), nil
+}
+
+// logMemStats reports allocations and GC activity since the before snapshot
+func logMemStats(before *runtime.MemStats, attempts int64) {
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	allocs := after.Mallocs - before.Mallocs
+	allocsPerHash := 0.0
+	if attempts > 0 {
+		allocsPerHash = float64(allocs) / float64(attempts)
+	}
+	logger.Printf("Memory: %d allocations (%d bytes), %.2f allocs/hash", allocs, after.TotalAlloc-before.TotalAlloc, allocsPerHash)
+	logger.Printf("GC: %d cycles, %v total pause", after.NumGC-before.NumGC, time.Duration(after.PauseTotalNs-before.PauseTotalNs))
 }
 
 func setupLogging() {
@@ -137,16 +772,24 @@ func setupLogging() {
 		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to open log file: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitError)
 		}
 		// Set global log output
 		logger = logpkg.NewWriter(file)
 		logger.SetFlags(log.LstdFlags | log.Lmicroseconds)
+		palette = color.New(cfg.Color, file)
+	} else if cfg.ResultsOnStdout() {
+		// Keep stdout for the --print fields or --format lines alone
+		logger = logpkg.NewWriter(os.Stderr)
+		logger.SetFlags(log.LstdFlags)
+		palette = color.New(cfg.Color, os.Stderr)
 	} else {
 		// Log to stdout
 		logger = logpkg.New()
 		logger.SetFlags(log.LstdFlags)
+		palette = color.New(cfg.Color, os.Stdout)
 	}
+	logger.SetPrefix(strings.TrimSpace(cfg.LogPrefix + " run=" + cfg.RunID))
 }
 
 func min(a, b int) int {
@@ -155,3 +798,22 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// runSmokeTest mines a trivial pattern through the configured pipeline and exits non-zero
+// unless the reference path reproduces the match
+func runSmokeTest() {
+	logger.Printf("Smoke test: mining prefix %q with the configured init code and factory...", minerpkg.SmokeTestPrefix)
+	result, err := minerpkg.SmokeTest(cfg, logger)
+	if err != nil {
+		logger.Printf("Error: %v", err)
+		os.Exit(exitError)
+	}
+	logger.Printf("Smoke test passed after %d attempts, reproduced from the factory, salt and init code hash", result.Attempts)
+	logger.Printf("Salt: 0x%s", result.Salt)
+	logger.Printf("Address: %s", result.Address)
+	logger.Printf("Factory: %s", result.Factory)
+	logger.Printf("Init code hash: 0x%s", result.InitCodeHash)
+	if result.Create2Salt != "" {
+		logger.Printf("CREATE2 salt (CreateX guarded): 0x%s", result.Create2Salt)
+	}
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// buildBinary compiles the CLI into a temporary directory
+func buildBinary(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "erc2470-miner")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build failed: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// exitCode runs the binary and returns its exit code
+func exitCode(t *testing.T, bin string, args ...string) int {
+	t.Helper()
+	err := exec.Command(bin, args...).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("failed to run binary: %v", err)
+	}
+	return 0
+}
+
+func TestExitCodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildBinary(t)
+	bytecode := "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"match", []string{"--prefix", "00", "--bytecode", bytecode, "--workers", "1"}, exitMatch},
+		{"no match within attempts", []string{"--prefix", "1234567890", "--bytecode", bytecode, "--workers", "1", "--max-attempts", "1"}, exitNoMatch},
+		{"no match within timeout", []string{"--prefix", "1234567890", "--bytecode", bytecode, "--workers", "1", "--timeout", "50ms"}, exitNoMatch},
+		{"keyspace exhausted", []string{"--target", "0x0000000000000000000000000000000000000000", "--bytecode", bytecode, "--workers", "1", "--salt-mode", "sequential", "--salt-end", "0xff"}, exitExhausted},
+		{"invalid config", []string{"--bytecode", bytecode}, exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(t, bin, tt.args...); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterruptReportsStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent on Windows")
+	}
+	bin := buildBinary(t)
+
+	var out bytes.Buffer
+	cmd := exec.Command(bin, "--prefix", "1234567890abcdef", "--workers", "1", "--summary", "text",
+		"--bytecode", "608060405234801561001057600080fd5b50600436106100365760003560e01c8063")
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != exitInterrupted {
+		t.Fatalf("exit = %v, want code %d\n%s", err, exitInterrupted, &out)
+	}
+	if !strings.Contains(out.String(), "Stopped after ") || !strings.Contains(out.String(), "hashes/sec") {
+		t.Errorf("interrupt output has no attempt count and rate:\n%s", &out)
+	}
+	if !strings.Contains(out.String(), "Outcome:        interrupted\n") {
+		t.Errorf("interrupt output has no summary block:\n%s", &out)
+	}
+}
+
+func TestInterruptNotifiesWebhook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent on Windows")
+	}
+	bin := buildBinary(t)
+
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	defer srv.Close()
+
+	// Matches every 65536 salts or so, far short of --count before the interrupt
+	var out bytes.Buffer
+	cmd := exec.Command(bin, "--prefix", "abcd", "--count", "1000000", "--workers", "1", "--salt-mode", "sequential",
+		"--bytecode", "0x6080", "--webhook", srv.URL)
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != exitInterrupted {
+		t.Fatalf("exit = %v, want code %d\n%s", err, exitInterrupted, &out)
+	}
+	notified := int32(strings.Count(out.String(), "Webhook notified for "))
+	if notified == 0 || posts.Load() != notified {
+		t.Errorf("webhook received %d posts and %d were logged, want the same non-zero count\n%s", posts.Load(), notified, &out)
+	}
+}
+
+func TestSummaryFormat(t *testing.T) {
+	c := config.NewConfig()
+	c.Prefix = "dead"
+	c.Workers = 4
+	c.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	stats := minerpkg.Stats{Attempts: 123456, Elapsed: 2500 * time.Millisecond, Rate: 49382.4}
+	results := []*types.Result{{
+		Salt:    "00000000000000000000000000000000000000000000000000000000000012ab",
+		Address: "0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A",
+	}}
+	s := buildSummary(c, outcomeMatch, stats, results)
+
+	var text bytes.Buffer
+	if err := writeSummary(&text, s, config.SummaryText); err != nil {
+		t.Fatal(err)
+	}
+	want := `=== Run summary ===
+Outcome:        match
+Target:         prefix: dead
+Factory:        0xce0042B868300000d44A59004Da54A005ffdcf9f
+Init code hash: 0x52723b625ab5ace695390da3db4adcac6120fe090bf78dcaa012387ffc939db5
+Workers:        4
+Salt mode:      random
+Attempts:       123456
+Elapsed:        2.5s
+Rate:           49382.40 hashes/sec
+Result 1:       0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A (salt 0x00000000000000000000000000000000000000000000000000000000000012ab)
+===================
+`
+	if text.String() != want {
+		t.Errorf("text summary:\n%s\nwant:\n%s", &text, want)
+	}
+
+	var js bytes.Buffer
+	if err := writeSummary(&js, s, config.SummaryJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON summary does not decode: %v\n%s", err, &js)
+	}
+	for _, key := range []string{"run_id", "outcome", "target", "factories", "initcode_hash", "workers", "salt_mode", "attempts", "elapsed", "rate", "results"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("JSON summary has no %q: %s", key, &js)
+		}
+	}
+	if results, _ := decoded["results"].([]any); len(results) != 1 {
+		t.Errorf("JSON summary results = %v, want the one match", decoded["results"])
+	}
+}
+
+func TestInterruptDuringAutoTune(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent on Windows")
+	}
+	bin := buildBinary(t)
+
+	// Auto-tuning benchmarks each candidate count for half a second, so it is still running
+	var out bytes.Buffer
+	cmd := exec.Command(bin, "--prefix", "1234567890abcdef", "--workers", "auto",
+		"--bytecode", "608060405234801561001057600080fd5b50600436106100365760003560e01c8063")
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != exitInterrupted {
+		t.Fatalf("exit = %v, want code %d\n%s", err, exitInterrupted, &out)
+	}
+	if !strings.Contains(out.String(), "while tuning the worker count") || strings.Contains(out.String(), "Using ") {
+		t.Errorf("interrupt during tuning was not reported before mining:\n%s", &out)
+	}
+}
+
+func TestSaltFromBytecode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildBinary(t)
+	out, err := exec.Command(bin, "--salt-from-bytecode", "--bytecode-file", "../../bytecode.txt").Output()
+	if err != nil {
+		t.Fatalf("run failed: %v\n%s", err, out)
+	}
+
+	// The salt is the init code hash, and the address is the plain ERC-2470 CREATE2 address under it
+	hash, _ := hex.DecodeString("453b9684db78ed19be9b289f18e18b83dda389b1fbea527aba3ab03918de91d8")
+	want := "Salt (init code hash): 0x453b9684db78ed19be9b289f18e18b83dda389b1fbea527aba3ab03918de91d8\n" +
+		"Address: " + crypto.CalculateCreate2Address(hash, hash) + " (factory " + crypto.FactoryAddress + ")\n"
+	if string(out) != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+
+	if got := exitCode(t, bin, "--salt-from-bytecode", "--bytecode-file", "../../bytecode.txt", "--factory-kind", "createx"); got != exitError {
+		t.Errorf("CreateX exit code = %d, want %d", got, exitError)
+	}
+}
+
+func TestDumpPreimage(t *testing.T) {
+	c := config.NewConfig()
+	c.Bytecode = "0x6080"
+	c.DumpPreimage = "0x2a"
+	var out bytes.Buffer
+	if err := writePreimage(&out, c); err != nil {
+		t.Fatalf("writePreimage() error = %v", err)
+	}
+
+	// 0xff ++ factory (20 bytes) ++ salt (32 bytes) ++ init code hash (32 bytes)
+	salt := strings.Repeat("0", 62) + "2a"
+	initCodeHash := crypto.Keccak256([]byte{0x60, 0x80})
+	wantPreimage := "ff" + strings.ToLower(crypto.FactoryAddress[2:]) + salt + hex.EncodeToString(initCodeHash)
+	if len(wantPreimage) != 2*crypto.Create2InputLen {
+		t.Fatalf("spec preimage is %d hex characters, want %d", len(wantPreimage), 2*crypto.Create2InputLen)
+	}
+	preimage, _ := hex.DecodeString(wantPreimage)
+	saltBytes, _ := hex.DecodeString(salt)
+
+	lines := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			lines[key] = strings.TrimSpace(value)
+		}
+	}
+	for key, want := range map[string]string{
+		"Preimage": "0x" + wantPreimage,
+		"Hash":     "0x" + hex.EncodeToString(crypto.Keccak256(preimage)),
+		"Address":  crypto.CalculateCreate2Address(initCodeHash, saltBytes),
+	} {
+		if lines[key] != want {
+			t.Errorf("%s = %q, want %q", key, lines[key], want)
+		}
+	}
+}
+
+func TestPrintFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildBinary(t)
+
+	// A single sequential worker finds the same match every run: salt 0x2a
+	salt := "0x" + strings.Repeat("0", 62) + "2a"
+	hash := crypto.Keccak256([]byte{0x60, 0x80})
+	saltBytes, _ := hex.DecodeString(salt[2:])
+	address := crypto.CalculateCreate2Address(hash, saltBytes)
+
+	for _, tt := range []struct {
+		print string
+		want  string
+	}{
+		{config.PrintSalt, salt + "\n"},
+		{config.PrintAddress, address + "\n"},
+		{config.PrintBoth, salt + " " + address + "\n"},
+	} {
+		cmd := exec.Command(bin, "--prefix", "ab", "--salt-mode", "sequential", "--workers", "1",
+			"--bytecode", "0x6080", "--summary", "text", "--print", tt.print)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("--print %s: %v\n%s", tt.print, err, &stderr)
+		}
+		if stdout.String() != tt.want {
+			t.Errorf("--print %s stdout = %q, want %q", tt.print, &stdout, tt.want)
+		}
+		if !strings.Contains(stderr.String(), "Found match") || !strings.Contains(stderr.String(), "Run summary") {
+			t.Errorf("--print %s: logs and summary missing from stderr:\n%s", tt.print, &stderr)
+		}
+	}
+
+	if got := exitCode(t, bin, "--prefix", "ab", "--bytecode", "0x6080", "--print", "json"); got != exitError {
+		t.Errorf("--print json exit code = %d, want %d", got, exitError)
+	}
+}
+
+func TestCreate2CrunchFormat(t *testing.T) {
+	results := []*types.Result{
+		{Salt: strings.Repeat("0", 62) + "2a", Address: "0x0000000000001234000056780000000000009aBc"},
+		{Salt: strings.Repeat("f", 64), Address: "0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A"},
+	}
+	var out bytes.Buffer
+	if err := writeCreate2Crunch(&out, results); err != nil {
+		t.Fatalf("writeCreate2Crunch() error = %v", err)
+	}
+	want := "0x000000000000000000000000000000000000000000000000000000000000002a => 0x0000000000001234000056780000000000009aBc => 6 => 14\n" +
+		"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff => 0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A => 0 => 0\n"
+	if out.String() != want {
+		t.Errorf("writeCreate2Crunch() =\n%s\nwant\n%s", &out, want)
+	}
+}
+
+func TestRunIDConsistent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	bin := buildBinary(t)
+
+	for _, runID := range []string{"", "box1-night2"} {
+		dir := t.TempDir()
+		args := []string{"--prefix", "ab", "--bytecode", "0x6080", "--summary", "json", "--output-dir", dir}
+		if runID != "" {
+			args = append(args, "--run-id", runID)
+		}
+		out, err := exec.Command(bin, args...).Output()
+		if err != nil {
+			t.Fatalf("run-id %q: %v", runID, err)
+		}
+
+		// The JSON summary is the last line; every line before it is a log line
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		var summary struct {
+			RunID   string `json:"run_id"`
+			Results []struct {
+				Address string `json:"address"`
+				RunID   string `json:"run_id"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+			t.Fatalf("run-id %q: summary does not decode: %v\n%s", runID, err, out)
+		}
+		if runID != "" && summary.RunID != runID {
+			t.Errorf("summary run_id = %q, want %q", summary.RunID, runID)
+		}
+		if runID == "" && len(summary.RunID) != 8 {
+			t.Errorf("generated run_id = %q, want 8 hex characters", summary.RunID)
+		}
+		for _, line := range lines[:len(lines)-1] {
+			if !strings.HasPrefix(line, "run="+summary.RunID+" ") {
+				t.Errorf("log line %q is not tagged with run=%s", line, summary.RunID)
+			}
+		}
+		if len(summary.Results) != 1 || summary.Results[0].RunID != summary.RunID {
+			t.Fatalf("summary results = %+v, want one result with run_id %s", summary.Results, summary.RunID)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, summary.Results[0].Address+".json"))
+		if err != nil {
+			t.Fatalf("match file: %v", err)
+		}
+		var match struct {
+			RunID string `json:"run_id"`
+		}
+		if err := json.Unmarshal(data, &match); err != nil || match.RunID != summary.RunID {
+			t.Errorf("match file run_id = %q (%v), want %s", match.RunID, err, summary.RunID)
+		}
+	}
+
+	if got := exitCode(t, bin, "--prefix", "ab", "--bytecode", "0x6080", "--run-id", "two words"); got != exitError {
+		t.Errorf("--run-id with a space exit code = %d, want %d", got, exitError)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	root := &cobra.Command{Use: "erc2470-miner", Run: func(*cobra.Command, []string) {}}
+	root.Flags().String("prefix", "", "")
+	root.Flags().Bool("palindrome", false, "")
+	root.AddCommand(newVersionCmd())
+
+	Version, Commit = "v1.2.3", "abc1234"
+	defer func() { Version, Commit = "dev", "" }()
+
+	info := buildVersionInfo(root)
+	if info.Version != "v1.2.3" || info.Commit != "abc1234" || info.GoVersion == "" {
+		t.Errorf("build info = %+v, want the ldflags values and the Go version", info)
+	}
+	if got := strings.Join(info.Capabilities.Flags, ","); got != "palindrome,prefix" {
+		t.Errorf("flags = %s, want the root command's flags sorted", got)
+	}
+	if got := strings.Join(info.Capabilities.Commands, ","); got != "version" {
+		t.Errorf("commands = %s, want version", got)
+	}
+
+	// The JSON keys are what wrappers depend on
+	out, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"version"`, `"commit"`, `"go_version"`, `"match_modes"`, `"factory_kinds"`, `"flags"`} {
+		if !strings.Contains(string(out), key) {
+			t.Errorf("version JSON is missing %s: %s", key, out)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/screa/erc2470-address-miner/internal/checkpoint"
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
+	"github.com/spf13/cobra"
+)
+
+// newMergeCheckpointsCmd creates the subcommand that combines checkpoints from sharded runs
+func newMergeCheckpointsCmd() *cobra.Command {
+	mergeCfg := config.NewConfig()
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "merge-checkpoints <checkpoint>...",
+		Short: "Combine checkpoints from several machines into one progress view",
+		Long: `Combine checkpoints written with --checkpoint by runs searching disjoint slices of the
+salt space. Attempts are summed and the global best is chosen with the same comparison
+as the run: pass --words, --closest-to or --best to match how the shards were scored.
+
+Resume points belong to individual shards and are not included in the merged checkpoint.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if mergeCfg.Best != config.BestLowest && mergeCfg.Best != config.BestHighest {
+				fmt.Printf("Error: %v\n", config.ErrInvalidBest)
+				os.Exit(exitError)
+			}
+			if mergeCfg.ClosestTo != "" {
+				if _, err := crypto.MustAddressBytes(mergeCfg.ClosestTo); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(exitError)
+				}
+			}
+
+			cps := make([]*checkpoint.Checkpoint, 0, len(args))
+			for _, path := range args {
+				cp, err := checkpoint.Load(path)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(exitError)
+				}
+				cps = append(cps, cp)
+			}
+
+			merged, err := checkpoint.Merge(cps, minerpkg.ResultComparator(mergeCfg))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(exitError)
+			}
+
+			if output != "" {
+				if err := checkpoint.Save(output, merged); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(exitError)
+				}
+				return
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(merged)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the merged checkpoint to this file instead of stdout")
+	cmd.Flags().StringVar(&mergeCfg.Best, "best", config.BestLowest, "Which address wins: lowest or highest")
+	cmd.Flags().BoolVar(&mergeCfg.Words, "words", false, "The shards scored hex words; the highest score wins")
+	cmd.Flags().BoolVar(&mergeCfg.Ascending, "ascending", false, "The shards scored ascending runs; the longest wins")
+	cmd.Flags().StringVar(&mergeCfg.Score, "score", "", "The shards scored in this --score mode; the highest score wins")
+	cmd.Flags().StringVar(&mergeCfg.ClosestTo, "closest-to", "", "The shards searched for the address closest to this one")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// writeFields writes the --print field of each result on its own line, undecorated, so a
+// shell can capture it: the 0x-prefixed salt, the checksummed address, or both separated by
+// a space
+func writeFields(w io.Writer, results []*types.Result, field string) error {
+	for _, r := range results {
+		var err error
+		switch field {
+		case config.PrintSalt:
+			_, err = fmt.Fprintf(w, "0x%s\n", r.Salt)
+		case config.PrintAddress:
+			_, err = fmt.Fprintln(w, r.Address)
+		case config.PrintBoth:
+			_, err = fmt.Fprintf(w, "0x%s %s\n", r.Salt, r.Address)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCreate2Crunch writes each result as a create2crunch output line, so scripts reading
+// its efficient_addresses.txt keep working: the 0x-prefixed salt, the checksummed address,
+// then the leading and total zero bytes that tool ranks by, separated by " => "
+func writeCreate2Crunch(w io.Writer, results []*types.Result) error {
+	for _, r := range results {
+		addr, err := crypto.MustAddressBytes(r.Address)
+		if err != nil {
+			return err
+		}
+		leading, total := crypto.ZeroBytes(addr)
+		if _, err := fmt.Fprintf(w, "0x%s => %s => %d => %d\n", r.Salt, r.Address, leading, total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printFields writes the --print fields or --format lines of the reported results to stdout
+func printFields(results []*types.Result) {
+	var err error
+	switch {
+	case cfg.Format == config.FormatCreate2Crunch:
+		err = writeCreate2Crunch(os.Stdout, results)
+	case cfg.Print != "":
+		err = writeFields(os.Stdout, results, cfg.Print)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print results: %v\n", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"runtime"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newRecoverCmd creates the subcommand that searches for the salt of a known address
+func newRecoverCmd() *cobra.Command {
+	// Bound separately so the sequential default doesn't leak into the root command's config
+	saltMode := config.SaltModeSequential
+
+	cmd := &cobra.Command{
+		Use:   "recover <address>",
+		Short: "Search for the salt that produced a known address",
+		Long: `Search for the salt that produced a known address from the given bytecode.
+
+This runs the miner against the full 40-char address. Recovering an arbitrary 32-byte salt
+is infeasible; this is only practical when the salt is known to lie in a small keyspace,
+e.g. with --salt-mode sequential, a --resume-from point near the salt and --salt-end or
+--max-attempts.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg.Target = args[0]
+			cfg.SaltMode = saltMode
+			runMiner(cmd, args)
+		},
+	}
+
+	cmd.Flags().IntVarP(&cfg.Workers, "workers", "w", runtime.NumCPU(), "Number of worker goroutines")
+	cmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Verbose output")
+	cmd.Flags().StringVarP(&cfg.Bytecode, "bytecode", "B", "", "Contract bytecode (hex)")
+	cmd.Flags().StringArrayVarP(&cfg.BytecodeFiles, "bytecode-file", "F", nil, "File containing contract bytecode (hex)")
+	cmd.Flags().StringVar(&cfg.InitCodeHash, "initcode-hash", "", "keccak256 of the init code (32 bytes hex)")
+	cmd.Flags().StringVar(&cfg.ConstructorArgs, "constructor-args", "", "ABI-encoded constructor arguments (hex) appended to the bytecode")
+	cmd.Flags().StringVar(&cfg.FactoryKind, "factory-kind", config.FactoryKindERC2470, "Factory that deployed the address: erc2470 or createx")
+	cmd.Flags().StringVar(&saltMode, "salt-mode", saltMode, "Salt generation: random or sequential")
+	cmd.Flags().StringVar(&cfg.ResumeFrom, "resume-from", "", "Start the sequential search just after this salt")
+	cmd.Flags().StringVar(&cfg.SaltEnd, "salt-end", "", "Stop the sequential search after this salt; exits 3 if the address is not in the range")
+	cmd.Flags().IntVar(&cfg.SaltBytes, "salt-bytes", 0, "The salt varies only in its last N bytes; the search exits 3 once all are tried (0 = all 32)")
+	cmd.Flags().StringVar(&cfg.SaltFormat, "salt-input-format", "hex", "Format of --resume-from and --salt-end: hex or decimal")
+	cmd.Flags().Int64Var(&cfg.MaxAttempts, "max-attempts", 0, "Give up after this many attempts (0 = unlimited)")
+	cmd.Flags().DurationVar(&cfg.Timeout, "timeout", 0, "Give up after this long (0 = unlimited)")
+
+	return cmd
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// newConfigSchemaCmd creates the subcommand printing the JSON Schema of a job file
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config-schema",
+		Short: "Print the JSON Schema of a job file",
+		Long: `Print a JSON Schema describing the JSON form of the miner's configuration: every field
+a job file can set, its type, accepted values and default. Point an editor at it to
+autocomplete and validate job files. The deployment private key is never part of a job file.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(config.Schema()); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(exitError)
+			}
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/spf13/cobra"
+)
+
+// newSelfTestCmd creates the subcommand that checks the optimized address path against the reference
+func newSelfTestCmd() *cobra.Command {
+	testCfg := config.NewConfig()
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Check that the optimized CREATE2 path agrees with the reference on random salts",
+		Long: `Generate random salts and compute each address with the reference CalculateCreate2Address
+and with the hot-path Create2AddressInto under every keccak backend. Any divergence is reported
+with the offending salt and exits non-zero, so the check can run in CI or after an optimization.
+
+Without bytecode a random init code hash is used.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if count <= 0 {
+				fmt.Printf("Error: --count must be positive\n")
+				os.Exit(exitError)
+			}
+
+			initCodeHash := make([]byte, 32)
+			if testCfg.Bytecode != "" || len(testCfg.BytecodeFiles) > 0 {
+				initcodes, err := testCfg.GetBytecodes()
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(exitError)
+				}
+				initCodeHash = crypto.Keccak256(initcodes[0])
+			} else {
+				rand.Read(initCodeHash)
+			}
+
+			salts := make([][32]byte, count)
+			for i := range salts {
+				rand.Read(salts[i][:])
+			}
+			if d := crypto.CompareCreate2Paths(initCodeHash, salts); d != nil {
+				fmt.Printf("Divergence on %s\n", d)
+				os.Exit(exitError)
+			}
+			fmt.Printf("OK: %d salts agree across the reference and %s\n", count, strings.Join(crypto.KeccakBackends(), ", "))
+		},
+	}
+
+	cmd.Flags().IntVarP(&count, "count", "n", 100000, "Number of random salts to check")
+	cmd.Flags().StringVarP(&testCfg.Bytecode, "bytecode", "B", "", "Contract bytecode (hex)")
+	cmd.Flags().StringArrayVarP(&testCfg.BytecodeFiles, "bytecode-file", "F", nil, "File containing contract bytecode (hex)")
+	cmd.Flags().StringVar(&testCfg.ConstructorArgs, "constructor-args", "", "ABI-encoded constructor arguments (hex) appended to the bytecode")
+
+	return cmd
+}
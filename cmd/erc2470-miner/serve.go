@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	logpkg "github.com/screa/erc2470-address-miner/internal/logger"
+	"github.com/screa/erc2470-address-miner/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// newServeCmd creates the subcommand that runs the HTTP job server
+func newServeCmd() *cobra.Command {
+	var addr string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an HTTP server that accepts mining jobs",
+		Long: `Run a long-lived HTTP server for submitting mining jobs.
+
+  POST /jobs       Submit a job (JSON: bytecode, prefix, suffix, workers, count, timeout_seconds)
+  GET  /jobs/{id}  Get the job status and results`,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logpkg.New()
+			srv := server.NewServer(log)
+			defer srv.Close()
+
+			log.Printf("Listening on %s", addr)
+			if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitError)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", ":8080", "Address to listen on")
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
+)
+
+// watchStatusSignal logs an immediate status line whenever the process receives SIGUSR1.
+// The returned function stops watching.
+func watchStatusSignal(m *minerpkg.Miner) func() {
+	usr1 := make(chan os.Signal, 1)
+	signal.Notify(usr1, syscall.SIGUSR1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-usr1:
+				m.LogStatus()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(usr1)
+		close(done)
+	}
+}
+
+// watchPauseSignals pauses mining on SIGTSTP (Ctrl+Z) and resumes it on SIGCONT. The
+// process keeps running while paused, so it resumes where it left off.
+// The returned function stops watching.
+func watchPauseSignals(m *minerpkg.Miner) func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTSTP, syscall.SIGCONT)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if sig == syscall.SIGTSTP {
+					m.Pause()
+				} else {
+					m.Resume()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
+
+// watchStatusSignal is a no-op on Windows, which has no SIGUSR1.
+func watchStatusSignal(m *minerpkg.Miner) func() {
+	return func() {}
+}
+
+// watchPauseSignals is a no-op on Windows, which has no SIGTSTP or SIGCONT.
+func watchPauseSignals(m *minerpkg.Miner) func() {
+	return func() {}
+}
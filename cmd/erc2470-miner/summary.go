@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	minerpkg "github.com/screa/erc2470-address-miner/pkg/miner"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// Run outcomes reported in the summary
+const (
+	outcomeMatch       = "match"
+	outcomeBest        = "best"        // a limit was reached; Results holds the best candidate
+	outcomeNoMatch     = "no-match"    // a limit was reached with nothing to report
+	outcomeExhausted   = "exhausted"   // every salt of a bounded keyspace was tried
+	outcomeInterrupted = "interrupted" // stopped by a signal
+)
+
+// runSummary consolidates what a run searched for, how and what it found, for archival
+type runSummary struct {
+	RunID        string          `json:"run_id"`
+	Outcome      string          `json:"outcome"`
+	Target       string          `json:"target"`
+	Factories    []string        `json:"factories"`
+	InitCodeHash string          `json:"initcode_hash,omitempty"`
+	Workers      int             `json:"workers"`
+	SaltMode     string          `json:"salt_mode"`
+	Attempts     int64           `json:"attempts"`
+	Elapsed      time.Duration   `json:"elapsed"`
+	Rate         float64         `json:"rate"`
+	Results      []*types.Result `json:"results"`
+}
+
+// buildSummary collects the run metadata from c and the final statistics
+func buildSummary(c *config.Config, outcome string, stats minerpkg.Stats, results []*types.Result) runSummary {
+	s := runSummary{
+		RunID:    c.RunID,
+		Outcome:  outcome,
+		Target:   c.GetTargetDescription(),
+		Workers:  c.Workers,
+		SaltMode: c.SaltMode,
+		Attempts: stats.Attempts,
+		Elapsed:  stats.Elapsed,
+		Rate:     stats.Rate,
+		Results:  results,
+	}
+	for _, f := range c.GetFactories() {
+		s.Factories = append(s.Factories, f.Address)
+	}
+	if hash, err := c.GetInitCodeHash(); err == nil {
+		s.InitCodeHash = hex.EncodeToString(hash)
+	}
+	if s.Results == nil {
+		s.Results = []*types.Result{}
+	}
+	return s
+}
+
+// writeSummary renders the summary as an aligned text block or as one JSON object
+func writeSummary(w io.Writer, s runSummary, format string) error {
+	if format == config.SummaryJSON {
+		return json.NewEncoder(w).Encode(s)
+	}
+	fmt.Fprintln(w, "=== Run summary ===")
+	if s.RunID != "" {
+		fmt.Fprintf(w, "Run ID:         %s\n", s.RunID)
+	}
+	fmt.Fprintf(w, "Outcome:        %s\n", s.Outcome)
+	fmt.Fprintf(w, "Target:         %s\n", s.Target)
+	for _, f := range s.Factories {
+		fmt.Fprintf(w, "Factory:        %s\n", f)
+	}
+	if s.InitCodeHash != "" {
+		fmt.Fprintf(w, "Init code hash: 0x%s\n", s.InitCodeHash)
+	}
+	fmt.Fprintf(w, "Workers:        %d\n", s.Workers)
+	fmt.Fprintf(w, "Salt mode:      %s\n", s.SaltMode)
+	fmt.Fprintf(w, "Attempts:       %d\n", s.Attempts)
+	fmt.Fprintf(w, "Elapsed:        %v\n", s.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Rate:           %.2f hashes/sec\n", s.Rate)
+	for i, r := range s.Results {
+		fmt.Fprintf(w, "Result %d:       %s (salt 0x%s)\n", i+1, r.Address, r.Salt)
+	}
+	_, err := fmt.Fprintln(w, "===================")
+	return err
+}
+
+// printSummary writes the --summary block to stdout, whatever way the run ended. With
+// --print, stdout carries only the printed fields and the summary goes to stderr.
+func printSummary(m *minerpkg.Miner, outcome string, results []*types.Result) {
+	if cfg.Summary == "" {
+		return
+	}
+	out := os.Stdout
+	if cfg.ResultsOnStdout() {
+		out = os.Stderr
+	}
+	if err := writeSummary(out, buildSummary(cfg, outcome, m.Stats(), results), cfg.Summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write summary: %v\n", err)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"github.com/screa/erc2470-address-miner/internal/color"
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Build information, set with -ldflags "-X main.Version=... -X main.Commit=... -X main.BuildTime=..."
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// versionInfo is the JSON printed by version --json
+type versionInfo struct {
+	Version      string       `json:"version"`
+	Commit       string       `json:"commit,omitempty"`
+	BuildTime    string       `json:"build_time,omitempty"`
+	GoVersion    string       `json:"go_version"`
+	Capabilities capabilities `json:"capabilities"`
+}
+
+// capabilities lists what this build supports, so wrappers can adapt to the deployed version
+type capabilities struct {
+	MatchModes     []string `json:"match_modes"`
+	ScoringModes   []string `json:"scoring_modes"`
+	FactoryKinds   []string `json:"factory_kinds"`
+	SaltModes      []string `json:"salt_modes"`
+	SaltFormats    []string `json:"salt_formats"`
+	KeccakBackends []string `json:"keccak_backends"`
+	ColorModes     []string `json:"color_modes"`
+	Outputs        []string `json:"outputs"`
+	Commands       []string `json:"commands"`
+	Flags          []string `json:"flags"`
+}
+
+// newVersionCmd creates the subcommand that prints the build version and capabilities
+func newVersionCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the build version and supported features",
+		Long: `Print the version, git commit, build time and Go version of this binary.
+With --json, also list the supported match modes, factory kinds, salt modes, outputs and flags.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			info := buildVersionInfo(cmd.Root())
+			if !asJSON {
+				fmt.Printf("erc2470-miner %s\n", versionString(info))
+				return
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(info); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(exitError)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print version and capabilities as JSON")
+
+	return cmd
+}
+
+// buildVersionInfo collects the build information and the capabilities of root's command tree
+func buildVersionInfo(root *cobra.Command) versionInfo {
+	info := versionInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Capabilities: capabilities{
+			MatchModes:     []string{"prefix", "prefix-bytes", "suffix", "match-tail-of", "template", "target", "target-file", "palindrome", "repeating", "low-zero-bits", "prefix-bits", "all-same", "match-expr", "numeric-property", "nice-set"},
+			ScoringModes:   []string{"zero-prefix", "words", "ascending", "closest-to", "gas", "nice-set"},
+			FactoryKinds:   []string{config.FactoryKindERC2470, config.FactoryKindCreateX},
+			SaltModes:      []string{config.SaltModeRandom, config.SaltModeSequential, config.SaltModeHD},
+			SaltFormats:    []string{"hex", "decimal"},
+			KeccakBackends: append(crypto.KeccakBackends(), crypto.KeccakAuto),
+			ColorModes:     []string{color.ModeAuto, color.ModeAlways, color.ModeNever},
+			Outputs:        []string{"log", "best-log", "rate-csv", "checkpoint", "audit-log", "webhook", "output-dir", "create2crunch"},
+		},
+	}
+	// go build records the VCS revision; -ldflags takes precedence
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range bi.Settings {
+				if s.Key == "vcs.revision" {
+					info.Commit = s.Value
+				}
+			}
+		}
+	}
+
+	for _, c := range root.Commands() {
+		if c.IsAvailableCommand() {
+			info.Capabilities.Commands = append(info.Capabilities.Commands, c.Name())
+		}
+	}
+	root.Flags().VisitAll(func(f *pflag.Flag) {
+		info.Capabilities.Flags = append(info.Capabilities.Flags, f.Name)
+	})
+	sort.Strings(info.Capabilities.Flags)
+	return info
+}
+
+// versionString renders the version with whichever build details are known
+func versionString(info versionInfo) string {
+	s := info.Version
+	if info.Commit != "" {
+		s += " (commit " + info.Commit + ")"
+	}
+	if info.BuildTime != "" {
+		s += " built " + info.BuildTime
+	}
+	return s + " " + info.GoVersion
+}
@@ -3,12 +3,11 @@ module github.com/screa/erc2470-address-miner
 go 1.25
 
 require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
 	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
 	golang.org/x/crypto v0.36.0
+	golang.org/x/sys v0.31.0
 )
 
-require (
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.31.0 // indirect
-)
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
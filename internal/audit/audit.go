@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Entry is a single audit record for a near-miss candidate
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Salt      string    `json:"salt"`
+	Address   string    `json:"address"`
+	Matched   int       `json:"matched"` // number of prefix nibbles matched
+}
+
+// Log is a buffered, concurrency-safe JSON-lines audit writer shared by workers
+type Log struct {
+	mu  sync.Mutex
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+// New creates an audit log writing to w
+func New(w io.Writer) *Log {
+	buf := bufio.NewWriterSize(w, 64*1024)
+	return &Log{
+		buf: buf,
+		enc: json.NewEncoder(buf),
+	}
+}
+
+// Record appends an entry to the buffer
+func (l *Log) Record(salt, address string, matched int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(Entry{
+		Timestamp: time.Now(),
+		Salt:      salt,
+		Address:   address,
+		Matched:   matched,
+	})
+}
+
+// Flush writes any buffered entries to the underlying writer
+func (l *Log) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Flush()
+}
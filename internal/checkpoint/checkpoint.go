@@ -0,0 +1,108 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// Version is the checkpoint format version written by Save
+const Version = 1
+
+// Errors
+var (
+	ErrNoCheckpoints  = errors.New("no checkpoints to merge")
+	ErrTargetMismatch = errors.New("checkpoints were written for different targets")
+	ErrUnknownVersion = errors.New("unsupported checkpoint version")
+)
+
+// Checkpoint is a snapshot of a run's progress, written periodically with --checkpoint
+type Checkpoint struct {
+	Version  int    `json:"version"`
+	Target   string `json:"target"` // target description, e.g. "prefix: 0000"
+	SaltMode string `json:"salt_mode"`
+
+	// ResumeFrom is the highest sequential salt below which every salt has been tried.
+	// Passing it to --resume-from continues the search without gaps. Always hex; empty in random mode.
+	ResumeFrom string `json:"resume_from,omitempty"`
+
+	Attempts  int64         `json:"attempts"`       // total attempts, including earlier resumed runs
+	Best      *types.Result `json:"best,omitempty"` // best result in the active scoring mode, or first match
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Save writes the checkpoint to path atomically, so a crash mid-write keeps the previous one
+func Save(path string, cp *Checkpoint) error {
+	return writeJSON(path, cp)
+}
+
+// writeJSON writes v to path as indented JSON through a temporary file and a rename
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads a checkpoint written by Save
+func Load(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if cp.Version != Version {
+		return nil, fmt.Errorf("%s: %w %d", path, ErrUnknownVersion, cp.Version)
+	}
+	return &cp, nil
+}
+
+// Merge combines checkpoints from disjoint shards of one search: attempts are summed and the
+// best result is chosen with better, which reports whether a beats b. Resume points belong
+// to individual shards and are not carried over.
+func Merge(cps []*Checkpoint, better func(a, b *types.Result) bool) (*Checkpoint, error) {
+	if len(cps) == 0 {
+		return nil, ErrNoCheckpoints
+	}
+
+	merged := &Checkpoint{
+		Version:  Version,
+		Target:   cps[0].Target,
+		SaltMode: cps[0].SaltMode,
+	}
+	for _, cp := range cps {
+		if cp.Target != merged.Target {
+			return nil, fmt.Errorf("%w: %q and %q", ErrTargetMismatch, merged.Target, cp.Target)
+		}
+		merged.Attempts += cp.Attempts
+		if cp.Best != nil && (merged.Best == nil || better(cp.Best, merged.Best)) {
+			merged.Best = cp.Best
+		}
+		if cp.UpdatedAt.After(merged.UpdatedAt) {
+			merged.UpdatedAt = cp.UpdatedAt
+		}
+	}
+	return merged, nil
+}
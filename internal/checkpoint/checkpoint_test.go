@@ -0,0 +1,108 @@
+package checkpoint
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// lowerAddress prefers the lexicographically lower address, the default scoring comparison
+func lowerAddress(a, b *types.Result) bool {
+	return a.Address < b.Address
+}
+
+func TestMergeConflictingBests(t *testing.T) {
+	a := &Checkpoint{
+		Version:   Version,
+		Target:    "prefix: 0000",
+		SaltMode:  "sequential",
+		Attempts:  1000,
+		Best:      &types.Result{Salt: "01", Address: "0x0000a1"},
+		UpdatedAt: time.Unix(100, 0),
+	}
+	b := &Checkpoint{
+		Version:   Version,
+		Target:    "prefix: 0000",
+		SaltMode:  "sequential",
+		Attempts:  2500,
+		Best:      &types.Result{Salt: "02", Address: "0x00001f"},
+		UpdatedAt: time.Unix(200, 0),
+	}
+
+	for _, order := range [][]*Checkpoint{{a, b}, {b, a}} {
+		merged, err := Merge(order, lowerAddress)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if merged.Attempts != 3500 {
+			t.Errorf("merged attempts = %d, want 3500", merged.Attempts)
+		}
+		if merged.Best != b.Best {
+			t.Errorf("merged best = %v, want %v", merged.Best, b.Best)
+		}
+		if !merged.UpdatedAt.Equal(b.UpdatedAt) {
+			t.Errorf("merged updated_at = %v, want the latest %v", merged.UpdatedAt, b.UpdatedAt)
+		}
+		if merged.ResumeFrom != "" {
+			t.Errorf("merged resume point = %q, want none", merged.ResumeFrom)
+		}
+	}
+
+	b.Target = "prefix: 1111"
+	if _, err := Merge([]*Checkpoint{a, b}, lowerAddress); !errors.Is(err, ErrTargetMismatch) {
+		t.Errorf("Merge() with different targets error = %v, want %v", err, ErrTargetMismatch)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	cp := &Checkpoint{
+		Version:    Version,
+		Target:     "prefix: 0000",
+		SaltMode:   "sequential",
+		ResumeFrom: "0x00000000000000000000000000000000000000000000000000000000000003e7",
+		Attempts:   1000,
+		Best:       &types.Result{Salt: "01", Address: "0x0000a1", Attempts: 10},
+	}
+	if err := Save(path, cp); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.ResumeFrom != cp.ResumeFrom || got.Attempts != cp.Attempts || got.Best.Address != cp.Best.Address {
+		t.Errorf("Load() = %+v, want %+v", got, cp)
+	}
+}
+
+func TestSaveLoadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	st := &State{
+		Version:  StateVersion,
+		Target:   "gas score (leading zero bytes, then zero bytes)",
+		Attempts: 123456789,
+		Best:     &types.Result{Salt: "01", Address: "0x0000a1", Score: 42},
+	}
+	if err := SaveState(path, st); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	got, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if got.Target != st.Target || got.Attempts != st.Attempts || got.Best == nil ||
+		got.Best.Salt != st.Best.Salt || got.Best.Address != st.Best.Address || got.Best.Score != st.Best.Score {
+		t.Errorf("LoadState() = %+v, want %+v", got, st)
+	}
+
+	if err := SaveState(path, &State{Version: StateVersion + 1}); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if _, err := LoadState(path); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("LoadState() of a newer file error = %v, want %v", err, ErrUnknownVersion)
+	}
+}
@@ -0,0 +1,45 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// StateVersion is the state file format version written by SaveState
+const StateVersion = 1
+
+// State is what --state-file keeps across restarts of a random-mode run: the attempt total and
+// the best result. Random salts leave no cursor to resume, so unlike a Checkpoint it has no
+// resume point; a restarted run draws fresh salts and keeps improving on Best.
+type State struct {
+	Version   int           `json:"version"`
+	Target    string        `json:"target"`         // target description, e.g. "gas score (leading zero bytes, then zero bytes)"
+	Attempts  int64         `json:"attempts"`       // total attempts, including earlier runs
+	Best      *types.Result `json:"best,omitempty"` // best result so far in the active scoring mode
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SaveState writes the state to path atomically, like Save
+func SaveState(path string, st *State) error {
+	return writeJSON(path, st)
+}
+
+// LoadState reads a state file written by SaveState
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if st.Version != StateVersion {
+		return nil, fmt.Errorf("%s: %w %d", path, ErrUnknownVersion, st.Version)
+	}
+	return &st, nil
+}
@@ -0,0 +1,82 @@
+package color
+
+import (
+	"fmt"
+	"os"
+)
+
+// Color modes selectable with --color
+const (
+	ModeAuto   = "auto"
+	ModeAlways = "always"
+	ModeNever  = "never"
+)
+
+// ANSI escape sequences
+const (
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	reset  = "\x1b[0m"
+)
+
+// ValidateMode reports an error for anything other than auto, always or never
+func ValidateMode(mode string) error {
+	switch mode {
+	case "", ModeAuto, ModeAlways, ModeNever:
+		return nil
+	}
+	return fmt.Errorf("--color must be auto, always or never, got %q", mode)
+}
+
+// Palette wraps text in ANSI colors, or returns it unchanged when disabled
+type Palette struct {
+	enabled bool
+}
+
+// New returns a palette for output written to f. In auto mode colors are used only when f
+// is a terminal and NO_COLOR is unset, so piped output and log files stay plain.
+func New(mode string, f *os.File) Palette {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return Palette{enabled: Enabled(mode, noColor, isTerminal(f))}
+}
+
+// Enabled decides whether to color output. always and never are absolute; auto requires
+// a terminal and no NO_COLOR variable (https://no-color.org).
+func Enabled(mode string, noColor, terminal bool) bool {
+	switch mode {
+	case ModeAlways:
+		return true
+	case ModeNever:
+		return false
+	}
+	return terminal && !noColor
+}
+
+// Success colors text green
+func (p Palette) Success(s string) string {
+	return p.wrap(green, s)
+}
+
+// Progress colors text yellow
+func (p Palette) Progress(s string) string {
+	return p.wrap(yellow, s)
+}
+
+func (p Palette) wrap(code, s string) string {
+	if !p.enabled {
+		return s
+	}
+	return code + s + reset
+}
+
+// isTerminal reports whether f is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
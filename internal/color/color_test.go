@@ -0,0 +1,57 @@
+package color
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		mode     string
+		noColor  bool
+		terminal bool
+		expected bool
+	}{
+		{ModeAuto, false, true, true},
+		{ModeAuto, true, true, false},
+		{ModeAuto, false, false, false},
+		{"", false, true, true},
+		{ModeAlways, true, false, true},
+		{ModeNever, false, true, false},
+	}
+
+	for _, tt := range tests {
+		if got := Enabled(tt.mode, tt.noColor, tt.terminal); got != tt.expected {
+			t.Errorf("Enabled(%q, noColor=%v, terminal=%v) = %v, want %v",
+				tt.mode, tt.noColor, tt.terminal, got, tt.expected)
+		}
+	}
+}
+
+func TestAutoPlainForFiles(t *testing.T) {
+	// A regular file is never a terminal, so auto mode must leave text untouched
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if got := New(ModeAuto, f).Success("done"); got != "done" {
+		t.Errorf("auto palette on a file = %q, want plain text", got)
+	}
+	if got := New(ModeAlways, f).Success("done"); got != green+"done"+reset {
+		t.Errorf("always palette = %q, want green text", got)
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	for _, mode := range []string{ModeAuto, ModeAlways, ModeNever} {
+		if err := ValidateMode(mode); err != nil {
+			t.Errorf("ValidateMode(%s) error = %v", mode, err)
+		}
+	}
+	if err := ValidateMode("sometimes"); err == nil {
+		t.Error("ValidateMode(sometimes) expected error")
+	}
+}
@@ -1,29 +1,325 @@
 package config
 
 import (
+	"bytes"
+	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/screa/erc2470-address-miner/internal/color"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/internal/deploy"
+	"github.com/screa/erc2470-address-miner/internal/rpc"
 )
 
 // Errors
 var (
-	ErrNoPatternSpecified  = errors.New("must specify either --prefix or --suffix")
-	ErrNoBytecodeSpecified = errors.New("must specify either --bytecode or --bytecode-file")
+	ErrNoPatternSpecified  = errors.New("must specify a pattern or scoring mode: --prefix, --prefix-bytes, --suffix, --match-tail-of, --template, --target, --target-file, --palindrome, --palindrome-checksum, --repeating, --all-same, --low-zero-bits, --prefix-bits, --match-expr, --numeric-property, --nice-set, --ascending, --words, --score or --closest-to")
+	ErrNoBytecodeSpecified = errors.New("must specify either --bytecode, --bytecode-file or --initcode-hash")
+	ErrOddBytecode         = errors.New("--bytecode must have an even number of hex characters")
+	ErrInvalidBytecode     = errors.New("bytecode is not valid hex")
+	ErrInvalidArgs         = errors.New("--constructor-args is not valid hex")
+	ErrInvalidWorkers      = errors.New("--workers must be a positive number or auto")
+	ErrInvalidCount        = errors.New("--count must be at least 1")
+	ErrInvalidLimits       = errors.New("--max-attempts and --timeout must not be negative")
+	ErrInvalidFactoryKind  = errors.New("--factory-kind must be erc2470 or createx")
+	ErrInvalidMode         = errors.New("--mode must be factory or solidity-new")
+	ErrSolidityNew         = errors.New("--mode solidity-new needs exactly one --factory address, the contract running new{salt: ...}, and its creation code in --bytecode or --bytecode-file; it cannot be used with CreateX or --deploy")
+	ErrInvalidFactory      = errors.New("--factory must be erc2470, createx, arachnid or a 20-byte address: exactly 40 hex characters, optionally prefixed with 0x")
+	ErrMultiFactoryScoring = errors.New("multiple --factory entries support match modes only, not a zero --prefix, --words, --ascending, --closest-to, --score or --nice-set")
+	ErrFactoryChecksum     = errors.New("--factory does not match its EIP-55 checksum; check for a typo or pass --no-checksum-check")
+	ErrGuardWithoutCreateX = errors.New("--createx-guard requires --factory-kind createx or a createx --factory")
+	ErrNoCreateXSender     = errors.New("--createx-guard msgsender requires --createx-sender")
+	ErrNoChainID           = errors.New("--createx-guard crosschain requires --chain-id")
+	ErrInvalidSaltMode     = errors.New("--salt-mode must be random, sequential or hd")
+	ErrNoMnemonic          = errors.New("--salt-mode hd requires --mnemonic-file")
+	ErrMnemonicWithoutHD   = errors.New("--mnemonic-file and --derivation-index require --salt-mode hd")
+	ErrInvalidEntropy      = errors.New("--entropy must be crypto, os-hybrid or file")
+	ErrEntropyFile         = errors.New("--entropy file requires --entropy-file, which is only used with it")
+	ErrEntropyNotRandom    = errors.New("--entropy applies only to --salt-mode random")
+	ErrResumeNotSequential = errors.New("--resume-from requires --salt-mode sequential")
+	ErrInvalidSaltEnd      = errors.New("--salt-end requires --salt-mode sequential and must come after --resume-from")
+	ErrInvalidSaltBytes    = errors.New("--salt-bytes must be between 1 and 32, with --resume-from and --salt-end fitting in that many bytes")
+	ErrSaltBytesConflict   = errors.New("--salt-bytes cannot be combined with --salt-mode hd, --entropy os-hybrid or --salt-label, and must be at most 11 with a CreateX factory")
+	ErrInvalidSaltLabel    = errors.New("--salt-label must be 1 to 24 printable ASCII characters, at most 12 with --entropy os-hybrid")
+	ErrSaltLabelConflict   = errors.New("--salt-label cannot be used with --salt-mode hd or a CreateX factory, which set the leading salt bytes themselves")
+	ErrInvalidWord         = errors.New("words must be non-empty hex strings")
+	ErrInvalidInitCodeHash = errors.New("--initcode-hash must be exactly 32 bytes of hex")
+	ErrBytecodeSaltCreateX = errors.New("--salt-from-bytecode does not support CreateX, which derives its own CREATE2 salt")
+	ErrHashWithBytecode    = errors.New("--initcode-hash cannot be combined with --bytecode or --bytecode-file")
+	ErrBytecodeAndFile     = errors.New("--bytecode and --bytecode-file are both set; pass only one so it is clear which init code is mined")
+	ErrAuditWithoutPrefix  = errors.New("--audit-log requires --prefix")
+	ErrInvalidAuditLevel   = errors.New("--audit-threshold must be between 1 and the prefix length minus 1")
+	ErrInvalidBest         = errors.New("--best must be lowest or highest")
+	ErrInvalidTopK         = errors.New("--top-k must not be negative")
+	ErrTopKWithoutScoring  = errors.New("--top-k requires a scoring mode: a zero --prefix, --words, --ascending, --closest-to, --score or --nice-set")
+	ErrInvalidRepeating    = errors.New("--repeating must be between 2 and 40")
+	ErrInvalidLowZeroBits  = errors.New("--low-zero-bits must be between 1 and 160")
+	ErrInvalidPrefixBits   = errors.New("--prefix-bits must be between 1 and 160, with a --prefix-bits-pattern of hex holding at least that many bits")
+	ErrInvalidProgress     = errors.New("--progress-every must not be negative")
+	ErrInvalidThrottle     = errors.New("--throttle must be a CPU percentage from 1 to 100")
+	ErrInvalidMaxMemory    = errors.New("--max-memory must not be negative")
+	ErrInvalidRunID        = errors.New("--run-id must be 1 to 64 letters, digits, '.', '_' or '-'")
+	ErrInvalidStateFile    = errors.New("--state-file requires --salt-mode random and cannot be combined with --checkpoint; use --checkpoint for sequential runs")
+	ErrInvalidBestEffort   = errors.New("--best-effort requires --prefix and --timeout")
+	ErrDeterministic       = errors.New("--deterministic-workers requires --salt-mode sequential or hd, and cannot be combined with --keep-searching, --matcher-cmd, --words, --ascending or --closest-to")
+	ErrInvalidKeepSearch   = errors.New("--keep-searching requires --timeout, --max-attempts or --salt-end, and a --count of 1")
+	ErrInvalidWebhook      = errors.New("--webhook must be an http or https URL")
+	ErrInvalidSummary      = errors.New("--summary must be text or json")
+	ErrInvalidPrint        = errors.New("--print must be salt, address or both")
+	ErrInvalidFormat       = errors.New("--format must be create2crunch, and cannot be combined with --print")
+	ErrInvalidScore        = errors.New("--score must be gas, and cannot be combined with --words, --ascending or --closest-to")
+	ErrInvalidNiceSet      = errors.New("--nice-set must be eights, counting, bookends or mirror, and cannot be combined with --words, --ascending, --score or --closest-to")
+	ErrInvalidPrefix       = errors.New("--prefix must be an even number of hex characters, at most 40")
+	ErrInvalidSuffix       = errors.New("--suffix must be hex characters, at most 40")
+	ErrInvalidMatchTail    = errors.New("--match-tail-of must be a 40-character address, with --tail-nibbles from 1 to 40 and no --suffix")
+	ErrInvalidPrefixBytes  = errors.New("--prefix-bytes must be whole bytes: an even number of hex characters, at most 40, and cannot be combined with --prefix")
+	ErrInvalidPrefixOffset = errors.New("--prefix-offset requires --prefix and must leave room for it within the 40-character address")
+	ErrInvalidTarget       = errors.New("--target must be a 20-byte address: exactly 40 hex characters, optionally prefixed with 0x")
+	ErrTargetCase          = errors.New("--case-sensitive requires --target in its EIP-55 checksummed form")
+	ErrInvalidTargetFile   = errors.New("--target-file must list at least one 20-byte address, one per line, and cannot be combined with --target")
+	ErrInvalidRPCURL       = errors.New("--rpc-url must be an http or https URL")
+	ErrInvalidRPCRetry     = errors.New("--rpc-retries must not be negative and --rpc-timeout must be positive")
+	ErrDeployWithoutRPC    = errors.New("--deploy requires --rpc-url")
+	ErrDeployWithoutKey    = errors.New("--deploy requires --private-key or " + PrivateKeyEnv)
+	ErrDeployWithoutCode   = errors.New("--deploy needs the init code; it cannot be used with --initcode-hash")
+	ErrInvalidPrivateKey   = errors.New("invalid private key")
+)
+
+// PrivateKeyEnv is the environment variable read when --private-key is not given
+const PrivateKeyEnv = "ERC2470_PRIVATE_KEY"
+
+// DefaultTailNibbles is how much of a --match-tail-of address is matched: its last 4 bytes
+const DefaultTailNibbles = 8
+
+// MaxSaltLabel is the longest --salt-label, leaving 8 salt bytes to mine
+const MaxSaltLabel = 24
+
+// Summary formats
+const (
+	SummaryText = "text"
+	SummaryJSON = "json"
+)
+
+// Fields --print writes to stdout for each reported result
+const (
+	PrintSalt    = "salt"
+	PrintAddress = "address"
+	PrintBoth    = "both"
 )
 
-// Config holds the application configuration
+// FormatCreate2Crunch is the --format writing each result as a create2crunch output line
+const FormatCreate2Crunch = "create2crunch"
+
+// ScoreGas is the --score mode ranking addresses by leading zero bytes, then total zero bytes
+const ScoreGas = "gas"
+
+// WorkersAuto is the --workers value that benchmarks worker counts at startup
+const WorkersAuto = "auto"
+
+// Salt modes
+const (
+	SaltModeRandom     = "random"
+	SaltModeSequential = "sequential"
+	SaltModeHD         = "hd" // hardened BIP-32 children of a mnemonic's master key
+)
+
+// Entropy sources for random salts
+const (
+	EntropyCrypto   = "crypto"    // crypto/rand seeds each worker's PRNG
+	EntropyOSHybrid = "os-hybrid" // crypto/rand plus a run stamp, worker ID and counter in every salt
+	EntropyFile     = "file"      // seeds are read from --entropy-file, e.g. a hardware RNG device
+)
+
+// Best result directions
+const (
+	BestLowest  = "lowest"
+	BestHighest = "highest"
+)
+
+// Modes
+const (
+	ModeFactory     = "factory"      // deploy through a CREATE2 factory contract
+	ModeSolidityNew = "solidity-new" // a contract deploys with Solidity's new Contract{salt: s}(args)
+)
+
+// Factory kinds
+const (
+	FactoryKindERC2470 = "erc2470"
+	FactoryKindCreateX = "createx"
+)
+
+// FactoryArachnid names Arachnid's deterministic deployment proxy in --factory
+const FactoryArachnid = "arachnid"
+
+// Factory is one CREATE2 deployer a run mines against
+type Factory struct {
+	Address string // EIP-55 checksummed
+	CreateX bool   // salts pass through the CreateX guard
+}
+
+// Config holds the application configuration. Its JSON form, described by Schema, is the
+// layout of a job file; the private key is never part of it.
 type Config struct {
-	Workers      int
-	Prefix       string
-	Suffix       string
-	Verbose      bool
-	LogFile      string
-	Bytecode     string
-	BytecodeFile string
-	LogInterval  int // Logging interval in seconds
+	Workers       int      `json:"workers"`
+	AutoWorkers   bool     `json:"auto_workers"` // pick Workers by benchmarking a few counts at startup
+	PinCPUs       bool     `json:"pin_cpus"`     // pin each worker's thread to its own CPU (Linux only)
+	Prefix        string   `json:"prefix"`
+	PrefixOffset  int      `json:"prefix_offset"` // hex characters skipped before the prefix is compared
+	PrefixBytes   string   `json:"prefix_bytes"`  // whole leading bytes to match, as even-length hex; never shifted by a nibble
+	Suffix        string   `json:"suffix"`
+	MatchTailOf   string   `json:"match_tail_of"`  // address whose last TailNibbles hex characters become the suffix
+	TailNibbles   int      `json:"tail_nibbles"`   // how much of MatchTailOf to match (0 = DefaultTailNibbles)
+	Target        string   `json:"target"`         // exact 40-char address to match
+	TargetFile    string   `json:"target_file"`    // file of exact addresses, one per line; matching any one is a hit
+	CaseSensitive bool     `json:"case_sensitive"` // require Target in its EIP-55 checksummed form instead of matching any casing
+	Template      string   `json:"template"`       // anchored hex template where '.' or 'x' matches any character
+	ClosestTo     string   `json:"closest_to"`     // keep the address numerically closest to this one
+	Verbose       bool     `json:"verbose"`
+	Analyze       bool     `json:"analyze"` // log a nibble histogram of the result, with its rarest characters
+	LogFile       string   `json:"log_file"`
+	LogPrefix     string   `json:"log_prefix"` // tag at the start of every log line, e.g. an instance name
+	RunID         string   `json:"run_id"`     // identifies this run in logs, results and the summary; random when empty
+	Bytecode      string   `json:"bytecode"`
+	BytecodeFiles []string `json:"bytecode_files"` // Multiple files require the pattern to hold under every init code
+	LogInterval   int      `json:"log_interval"`   // Logging interval in seconds
+	Count         int      `json:"count"`          // Number of distinct matches to find before stopping
+
+	ProgressEvery     int  `json:"progress_every"`      // log verbose progress only every Nth interval (0 or 1 = every one)
+	ProgressOnImprove bool `json:"progress_on_improve"` // log verbose progress only when the best result or a milestone improves
+
+	MaxAttempts int64         `json:"max_attempts"` // Stop after this many attempts (0 = unlimited)
+	Timeout     time.Duration `json:"timeout"`      // Stop after this long (0 = unlimited)
+	BestEffort  bool          `json:"best_effort"`  // on timeout, return the longest partial prefix match instead of nothing
+
+	KeepSearching bool `json:"keep_searching"` // keep mining after a match until the budget runs out, reporting the best match
+
+	Throttle int `json:"throttle"` // target CPU percentage per worker, met by sleeping between batches (0 or 100 = full speed)
+
+	MaxMemory int `json:"max_memory"` // soft heap limit in MB: --top-k heaps are trimmed and the stream paused near it (0 = off)
+
+	// Report the matches at the lowest salts of a sequential or hd run, whichever worker finds
+	// them first, so a multi-worker run reports the same salts every time
+	DeterministicWorkers bool `json:"deterministic_workers"`
+
+	Summary string `json:"summary"` // print a final run summary on exit: text or json (empty = off)
+	Print   string `json:"print"`   // write only this field of each result to stdout: salt, address or both; logs go to stderr
+	Format  string `json:"format"`  // write each result to stdout in another tool's format: create2crunch; logs go to stderr
+
+	SignKey string `json:"sign_key"` // Optional ed25519 key file used to sign results
+	BestLog string `json:"best_log"` // Optional JSON-lines file recording each best result improvement
+	RateCSV string `json:"rate_csv"` // Optional CSV file receiving timestamp,attempts,rate at each progress tick
+
+	OutputDir string `json:"output_dir"` // Optional directory receiving <address>.json for each match
+
+	SaveConfig string `json:"save_config"` // Optional file receiving the effective configuration as a job file, see Save
+
+	Checkpoint string `json:"checkpoint"`  // Optional checkpoint file, rewritten each progress tick and resumed from if present
+	StateFile  string `json:"state_file"`  // Optional random-mode file keeping the attempt total and best result across restarts
+	Webhook    string `json:"webhook"`     // Optional URL receiving a JSON POST for each match
+	ExpvarAddr string `json:"expvar_addr"` // Optional address serving live statistics at /debug/vars
+
+	Deploy     bool   `json:"deploy"`  // deploy the first match through its factory once found
+	RPCURL     string `json:"rpc_url"` // JSON-RPC endpoint used by --deploy and to check the factory is deployed
+	PrivateKey string `json:"-"`       // hex secp256k1 key signing the deployment; falls back to PrivateKeyEnv
+	Yes        bool   `json:"yes"`     // skip the deployment confirmation prompt
+
+	RPCRetries int           `json:"rpc_retries"` // retries of an RPC call after a network error, HTTP 5xx or 429
+	RPCTimeout time.Duration `json:"rpc_timeout"` // bounds each RPC request
+
+	AuditLog       string `json:"audit_log"`       // Optional JSON-lines file recording near-miss candidates
+	AuditThreshold int    `json:"audit_threshold"` // Prefix nibbles a near-miss must match (0 = prefix length minus 2)
+
+	ConstructorArgs string `json:"constructor_args"` // ABI-encoded constructor arguments (hex) appended to the bytecode
+	InitCodeHash    string `json:"initcode_hash"`    // keccak256 of the init code (hex); replaces the bytecode when set
+
+	FactoryKind   string `json:"factory_kind"`   // erc2470 (default) or createx
+	CreateXGuard  string `json:"createx_guard"`  // none, msgsender or crosschain (createx only)
+	CreateXSender string `json:"createx_sender"` // msg.sender address for the msgsender guard
+	ChainID       uint64 `json:"chain_id"`       // chain id for the crosschain guard
+
+	Mode            string   `json:"mode"`              // factory (default) or solidity-new, where the factory is the deploying contract
+	Factories       []string `json:"factories"`         // erc2470, createx, arachnid or addresses; a match under any one wins
+	NoChecksumCheck bool     `json:"no_checksum_check"` // warn instead of failing when --factory has a bad EIP-55 checksum
+
+	SaltMode   string `json:"salt_mode"`   // random (default) or sequential
+	ResumeFrom string `json:"resume_from"` // sequential mode starts just after this salt
+	SaltEnd    string `json:"salt_end"`    // sequential mode stops after this salt, making the keyspace finite
+
+	SaltFromBytecode bool   `json:"salt_from_bytecode"` // compute the one address whose salt is the init code hash instead of mining
+	DumpPreimage     string `json:"dump_preimage"`      // print the CREATE2 preimage, hash and address for this salt instead of mining
+	SaltLabel        string `json:"salt_label"`         // ASCII label fixed in the leading salt bytes; the rest is mined
+	SaltFormat       string `json:"salt_format"`        // how salt inputs such as ResumeFrom are written: hex (default) or decimal
+	SaltBytes        int    `json:"salt_bytes"`         // vary only this many trailing salt bytes, the rest zero (0 = all 32)
+
+	Entropy     string `json:"entropy"`      // random mode: crypto (default), os-hybrid or file
+	EntropyFile string `json:"entropy_file"` // file or device read for seeds with --entropy file
+
+	DebugCollisions bool `json:"debug_collisions"` // sample generated salts and count repeats, to catch shared generator state
+
+	SmokeTest bool `json:"smoke_test"` // mine a one-byte prefix with the real init code and factory, verify it and exit
+
+	MnemonicFile    string `json:"mnemonic_file"`    // hd mode: file holding the BIP-39 mnemonic
+	DerivationIndex uint32 `json:"derivation_index"` // hd mode: first child index m/i' to derive
+
+	Palindrome         bool `json:"palindrome"`          // match addresses whose hex reads the same both ways, ignoring the checksum
+	PalindromeChecksum bool `json:"palindrome_checksum"` // like Palindrome, but the EIP-55 checksummed casing must mirror too
+	Repeating          int  `json:"repeating"`           // match addresses with a run of at least this many identical hex characters
+	LowZeroBits        int  `json:"low_zero_bits"`       // match addresses whose integer value has this many low bits clear
+	PrefixBits         int  `json:"prefix_bits"`         // match addresses whose top bits equal those of PrefixBitsPattern
+	AllSame            bool `json:"all_same"`            // match addresses made of a single repeated hex character
+
+	PrefixBitsPattern string `json:"prefix_bits_pattern"` // hex holding the bits PrefixBits compares, left-aligned; zeros when empty
+
+	MatchExpr  string `json:"match_expr"`  // boolean expression over prefix, suffix, contains and zerobytes predicates
+	MatcherCmd string `json:"matcher_cmd"` // external program filtering matches over a line protocol, see internal/matcher
+
+	NumericProperty string `json:"numeric_property"` // arithmetic predicate over the address as an integer, e.g. addr % 97 == 0
+
+	Best string `json:"best"`  // which address wins when comparing candidates: lowest (default) or highest
+	TopK int    `json:"top_k"` // in scoring modes, keep this many best results instead of only the best
+
+	KeccakBackend string `json:"keccak_backend"` // hashing implementation: x-crypto (default), generic or auto
+	Color         string `json:"color"`          // ANSI color for result output: auto (default), always or never
+
+	Words     bool   `json:"words"`      // Score candidates by the number of hex words they contain
+	WordsFile string `json:"words_file"` // Optional word list (one per line) replacing the built-in list
+	Ascending bool   `json:"ascending"`  // Score candidates by their longest run of hex characters counting up
+	Score     string `json:"score"`      // Named scoring mode: gas ranks by leading zero bytes, then total zero bytes
+
+	NiceSet string `json:"nice_set"` // built-in family of appealing addresses to match and score by, see crypto.NiceSets
+}
+
+// NewRunID returns a short random identifier for a run: 8 hex characters, enough to tell
+// apart the runs of a fleet in merged logs
+func NewRunID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano()&0xffffffff, 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validRunID reports whether id can tag log lines and file names as is
+func validRunID(id string) bool {
+	if len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
 }
 
 // NewConfig creates a new configuration with default values
@@ -31,34 +327,972 @@ func NewConfig() *Config {
 	return &Config{
 		Workers:     runtime.NumCPU(),
 		LogInterval: 5, // Default 5 seconds
+		Count:       1,
+		FactoryKind: FactoryKindERC2470,
+		Mode:        ModeFactory,
+		SaltMode:    SaltModeRandom,
+		SaltFormat:  "hex",
+		Entropy:     EntropyCrypto,
+		Best:        BestLowest,
+
+		KeccakBackend: crypto.DefaultKeccakBackend,
+		Color:         color.ModeAuto,
+
+		RPCRetries: rpc.DefaultRetries,
+		RPCTimeout: rpc.DefaultTimeout,
 	}
 }
 
+// SetWorkers parses a --workers value: a positive count, or auto to benchmark at startup
+func (c *Config) SetWorkers(s string) error {
+	if s == WorkersAuto {
+		c.AutoWorkers = true
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return ErrInvalidWorkers
+	}
+	c.Workers = n
+	c.AutoWorkers = false
+	return nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.Prefix == "" && c.Suffix == "" {
+	if c.SaltFromBytecode {
+		return c.validateSaltFromBytecode()
+	}
+	if c.DumpPreimage != "" {
+		return c.validateDumpPreimage()
+	}
+	if c.Prefix == "" && c.PrefixBytes == "" && c.Suffix == "" && c.MatchTailOf == "" && c.NiceSet == "" && c.Template == "" && c.Target == "" && c.TargetFile == "" && c.ClosestTo == "" && !c.Words &&
+		!c.Ascending && c.Score == "" && !c.IsPalindrome() && c.Repeating == 0 && c.LowZeroBits == 0 && c.PrefixBits == 0 && !c.AllSame &&
+		c.MatchExpr == "" && c.NumericProperty == "" && !c.SmokeTest {
 		return ErrNoPatternSpecified
 	}
-	if c.Bytecode == "" && c.BytecodeFile == "" {
+	if c.Repeating != 0 && (c.Repeating < 2 || c.Repeating > 40) {
+		return ErrInvalidRepeating
+	}
+	if c.LowZeroBits < 0 || c.LowZeroBits > 160 {
+		return ErrInvalidLowZeroBits
+	}
+	if c.PrefixBits != 0 || c.PrefixBitsPattern != "" {
+		if _, err := c.GetPrefixBitsPattern(); err != nil {
+			return err
+		}
+	}
+	if c.Prefix != "" {
+		if b, err := crypto.HexToAddressBytes(c.Prefix); err != nil || len(b) == 0 || len(b) > 20 {
+			return ErrInvalidPrefix
+		}
+	}
+	if c.PrefixBytes != "" {
+		if c.Prefix != "" {
+			return ErrInvalidPrefixBytes
+		}
+		if _, err := c.GetPrefixBytes(); err != nil {
+			return err
+		}
+	}
+	if c.MatchTailOf != "" {
+		if _, err := crypto.MustAddressBytes(c.MatchTailOf); err != nil || c.Suffix != "" || c.TailNibbles < 0 || c.TailNibbles > 40 {
+			return ErrInvalidMatchTail
+		}
+	}
+	if c.Suffix != "" {
+		if b, err := crypto.HexToAddressBytes(padOddHex(c.Suffix)); err != nil || len(b) == 0 || len(b) > 20 {
+			return ErrInvalidSuffix
+		}
+	}
+	if c.PrefixOffset != 0 {
+		if c.Prefix == "" || c.PrefixOffset < 0 || c.PrefixOffset+len(strings.TrimPrefix(c.Prefix, "0x")) > 40 {
+			return ErrInvalidPrefixOffset
+		}
+	}
+	if c.Template != "" {
+		if _, _, err := crypto.CompileTemplate(c.Template); err != nil {
+			return err
+		}
+	}
+	if c.MatchExpr != "" {
+		if _, err := crypto.ParseMatchExpr(c.MatchExpr); err != nil {
+			return err
+		}
+	}
+	if c.NumericProperty != "" {
+		if _, err := crypto.ParseNumericProperty(c.NumericProperty); err != nil {
+			return err
+		}
+	}
+	if c.Target != "" {
+		if _, err := crypto.MustAddressBytes(c.Target); err != nil {
+			return fmt.Errorf("%w (%v)", ErrInvalidTarget, err)
+		}
+		// Candidates are compared as bytes, so any casing matches unless asked otherwise
+		if c.CaseSensitive && "0x"+strings.TrimPrefix(c.Target, "0x") != checksumOf(c.Target) {
+			return fmt.Errorf("%w (expected %s)", ErrTargetCase, checksumOf(c.Target))
+		}
+	}
+	if c.TargetFile != "" {
+		if c.Target != "" {
+			return ErrInvalidTargetFile
+		}
+		if _, err := c.GetTargetSet(); err != nil {
+			return err
+		}
+	}
+	if c.ClosestTo != "" {
+		if _, err := crypto.MustAddressBytes(c.ClosestTo); err != nil {
+			return err
+		}
+	}
+	if err := c.validateBytecode(); err != nil {
+		return err
+	}
+	if c.Count < 1 {
+		return ErrInvalidCount
+	}
+	if c.MaxAttempts < 0 || c.Timeout < 0 {
+		return ErrInvalidLimits
+	}
+	if c.ProgressEvery < 0 {
+		return ErrInvalidProgress
+	}
+	if c.Throttle < 0 || c.Throttle > 100 {
+		return ErrInvalidThrottle
+	}
+	if c.MaxMemory < 0 {
+		return ErrInvalidMaxMemory
+	}
+	if c.RunID != "" && !validRunID(c.RunID) {
+		return ErrInvalidRunID
+	}
+	if c.StateFile != "" && ((c.SaltMode != "" && c.SaltMode != SaltModeRandom) || c.Checkpoint != "") {
+		return ErrInvalidStateFile
+	}
+	if c.Summary != "" && c.Summary != SummaryText && c.Summary != SummaryJSON {
+		return ErrInvalidSummary
+	}
+	if c.Print != "" && c.Print != PrintSalt && c.Print != PrintAddress && c.Print != PrintBoth {
+		return ErrInvalidPrint
+	}
+	if c.Format != "" && (c.Format != FormatCreate2Crunch || c.Print != "") {
+		return ErrInvalidFormat
+	}
+	if c.Score != "" && (c.Score != ScoreGas || c.Words || c.Ascending || c.ClosestTo != "") {
+		return ErrInvalidScore
+	}
+	if c.NiceSet != "" {
+		if _, ok := crypto.LookupNiceSet(c.NiceSet); !ok || c.Words || c.Ascending || c.Score != "" || c.ClosestTo != "" {
+			return ErrInvalidNiceSet
+		}
+	}
+	if c.BestEffort && (c.Prefix == "" || c.Timeout == 0) {
+		return ErrInvalidBestEffort
+	}
+	if c.KeepSearching && (c.Count != 1 || c.Timeout == 0 && c.MaxAttempts == 0 && c.SaltEnd == "") {
+		return ErrInvalidKeepSearch
+	}
+	if c.DeterministicWorkers {
+		if c.SaltMode != SaltModeSequential && c.SaltMode != SaltModeHD {
+			return ErrDeterministic
+		}
+		if c.KeepSearching || c.MatcherCmd != "" || c.Words || c.Ascending || c.ClosestTo != "" || c.Score != "" {
+			return ErrDeterministic
+		}
+	}
+	if err := c.validateSalt(); err != nil {
+		return err
+	}
+	if c.Best != "" && c.Best != BestLowest && c.Best != BestHighest {
+		return ErrInvalidBest
+	}
+	if c.TopK < 0 {
+		return ErrInvalidTopK
+	}
+	if c.TopK > 0 && !c.TracksBest() {
+		return ErrTopKWithoutScoring
+	}
+	if err := c.validateDeploy(); err != nil {
+		return err
+	}
+	if c.Webhook != "" {
+		if u, err := url.Parse(c.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidWebhook
+		}
+	}
+	if err := color.ValidateMode(c.Color); err != nil {
+		return err
+	}
+	if c.KeccakBackend != crypto.KeccakAuto {
+		if _, err := crypto.KeccakHasherFactory(c.KeccakBackend); err != nil {
+			return err
+		}
+	}
+	if c.WordsFile != "" {
+		if _, err := c.GetWords(); err != nil {
+			return err
+		}
+	}
+	if c.AuditLog != "" {
+		if c.Prefix == "" {
+			return ErrAuditWithoutPrefix
+		}
+		if t := c.GetAuditThreshold(); t < 1 || t >= len(strings.TrimPrefix(c.Prefix, "0x")) {
+			return ErrInvalidAuditLevel
+		}
+	}
+	if err := c.validateFactory(); err != nil {
+		return err
+	}
+	if err := c.validateMode(); err != nil {
+		return err
+	}
+	return c.validateSaltLabel()
+}
+
+// validateMode checks the inputs of --mode solidity-new: the CREATE2 math is the same, but
+// the deployer is a contract of the user's own and the init code must be its creation code
+func (c *Config) validateMode() error {
+	switch c.Mode {
+	case "", ModeFactory:
+		return nil
+	case ModeSolidityNew:
+	default:
+		return ErrInvalidMode
+	}
+	if len(c.Factories) != 1 || isNamedFactory(c.Factories[0]) || c.UsesCreateX() {
+		return ErrSolidityNew
+	}
+	if c.InitCodeHash != "" || c.Deploy {
+		return ErrSolidityNew
+	}
+	return nil
+}
+
+// validateSaltLabel checks that --salt-label fits in the leading salt bytes and leaves the
+// rest free to mine. os-hybrid salts keep their run stamp, worker ID and counter in the
+// last 20 bytes.
+func (c *Config) validateSaltLabel() error {
+	if c.SaltLabel == "" {
+		return nil
+	}
+	limit := MaxSaltLabel
+	if c.SaltMode != SaltModeSequential && c.Entropy == EntropyOSHybrid {
+		limit = 12
+	}
+	if len(c.SaltLabel) > limit {
+		return ErrInvalidSaltLabel
+	}
+	for i := 0; i < len(c.SaltLabel); i++ {
+		if c.SaltLabel[i] < 0x20 || c.SaltLabel[i] > 0x7e {
+			return ErrInvalidSaltLabel
+		}
+	}
+	if c.SaltMode == SaltModeHD || c.UsesCreateX() {
+		return ErrSaltLabelConflict
+	}
+	return nil
+}
+
+// validateSalt validates the salt generation options
+func (c *Config) validateSalt() error {
+	if _, err := crypto.ParseSaltFormat(c.SaltFormat); err != nil {
+		return err
+	}
+	if c.SaltMode != SaltModeHD && (c.MnemonicFile != "" || c.DerivationIndex != 0) {
+		return ErrMnemonicWithoutHD
+	}
+	if err := c.validateEntropy(); err != nil {
+		return err
+	}
+	if err := c.validateSaltBytes(); err != nil {
+		return err
+	}
+	if c.SaltEnd != "" && c.SaltMode != SaltModeSequential {
+		return ErrInvalidSaltEnd
+	}
+	switch c.SaltMode {
+	case "", SaltModeRandom:
+		if c.ResumeFrom != "" {
+			return ErrResumeNotSequential
+		}
+	case SaltModeHD:
+		if c.ResumeFrom != "" {
+			return ErrResumeNotSequential
+		}
+		if c.MnemonicFile == "" {
+			return ErrNoMnemonic
+		}
+		if _, err := c.GetHDSaltDeriver(); err != nil {
+			return err
+		}
+	case SaltModeSequential:
+		var start, end [32]byte
+		var err error
+		if c.ResumeFrom != "" {
+			if start, err = c.ParseSaltInput(c.ResumeFrom); err != nil {
+				return err
+			}
+		}
+		if c.SaltEnd != "" {
+			if end, err = c.ParseSaltInput(c.SaltEnd); err != nil {
+				return err
+			}
+			if c.ResumeFrom != "" && bytes.Compare(end[:], start[:]) <= 0 {
+				return ErrInvalidSaltEnd
+			}
+		}
+	default:
+		return ErrInvalidSaltMode
+	}
+	return nil
+}
+
+// validateSaltBytes checks that --salt-bytes leaves the other salt options room to work.
+// CreateX keeps the sender and guard flag in the leading 21 bytes.
+func (c *Config) validateSaltBytes() error {
+	if c.SaltBytes == 0 {
+		return nil
+	}
+	if c.SaltBytes < 0 || c.SaltBytes > 32 {
+		return ErrInvalidSaltBytes
+	}
+	if c.SaltMode == SaltModeHD || c.Entropy == EntropyOSHybrid || c.SaltLabel != "" || c.UsesCreateX() && c.SaltBytes > 11 {
+		return ErrSaltBytesConflict
+	}
+	for _, s := range []string{c.ResumeFrom, c.SaltEnd} {
+		if s == "" {
+			continue
+		}
+		salt, err := c.ParseSaltInput(s)
+		if err != nil {
+			return err
+		}
+		if new(big.Int).SetBytes(salt[:]).BitLen() > 8*c.SaltBytes {
+			return ErrInvalidSaltBytes
+		}
+	}
+	return nil
+}
+
+// SaltKeyspace returns how many distinct salts the miner can generate: 256 to the power of
+// --salt-bytes, or of the bytes --salt-label leaves free
+func (c *Config) SaltKeyspace() *big.Int {
+	n := 32 - len(c.SaltLabel)
+	if c.SaltBytes > 0 {
+		n = c.SaltBytes
+	}
+	return new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+}
+
+// LastSalt returns the highest salt --salt-bytes allows, which ends a sequential search
+// that has no --salt-end. ok is false without --salt-bytes.
+func (c *Config) LastSalt() (salt [32]byte, ok bool) {
+	if c.SaltBytes == 0 {
+		return salt, false
+	}
+	for i := 32 - c.SaltBytes; i < 32; i++ {
+		salt[i] = 0xff
+	}
+	return salt, true
+}
+
+// validateEntropy checks the entropy source, which only random salts use
+func (c *Config) validateEntropy() error {
+	switch c.Entropy {
+	case "", EntropyCrypto, EntropyOSHybrid:
+		if c.EntropyFile != "" {
+			return ErrEntropyFile
+		}
+	case EntropyFile:
+		if c.EntropyFile == "" {
+			return ErrEntropyFile
+		}
+		f, err := os.Open(c.EntropyFile)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	default:
+		return ErrInvalidEntropy
+	}
+	if c.Entropy != "" && c.Entropy != EntropyCrypto && c.SaltMode != "" && c.SaltMode != SaltModeRandom {
+		return ErrEntropyNotRandom
+	}
+	return nil
+}
+
+// GetHDSaltDeriver derives the BIP-32 master key from the mnemonic file (no passphrase)
+func (c *Config) GetHDSaltDeriver() (*crypto.HDSaltDeriver, error) {
+	content, err := os.ReadFile(c.MnemonicFile)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic := strings.TrimSpace(string(content))
+	if mnemonic == "" {
+		return nil, fmt.Errorf("mnemonic file %s is empty", c.MnemonicFile)
+	}
+	return crypto.NewHDSaltDeriver(crypto.MnemonicToSeed(mnemonic, ""))
+}
+
+// validateDeploy validates the --deploy options
+func (c *Config) validateDeploy() error {
+	if c.RPCURL != "" {
+		if u, err := url.Parse(c.RPCURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidRPCURL
+		}
+	}
+	if c.RPCRetries < 0 || c.RPCTimeout <= 0 {
+		return ErrInvalidRPCRetry
+	}
+	if !c.Deploy {
+		return nil
+	}
+	if c.RPCURL == "" {
+		return ErrDeployWithoutRPC
+	}
+	if c.InitCodeHash != "" {
+		return ErrDeployWithoutCode
+	}
+	key := c.GetPrivateKey()
+	if key == "" {
+		return ErrDeployWithoutKey
+	}
+	if _, err := deploy.ParsePrivateKey(key); err != nil {
+		return fmt.Errorf("%w (%v)", ErrInvalidPrivateKey, err)
+	}
+	return nil
+}
+
+// GetPrivateKey returns the deployment key from --private-key or the PrivateKeyEnv variable
+func (c *Config) GetPrivateKey() string {
+	if c.PrivateKey != "" {
+		return c.PrivateKey
+	}
+	return os.Getenv(PrivateKeyEnv)
+}
+
+// validateBytecode checks that exactly one source of the init code hash is given
+func (c *Config) validateBytecode() error {
+	if c.InitCodeHash != "" {
+		if c.Bytecode != "" || len(c.BytecodeFiles) > 0 {
+			return ErrHashWithBytecode
+		}
+		_, err := c.parseInitCodeHash()
+		return err
+	}
+	if c.Bytecode == "" && len(c.BytecodeFiles) == 0 {
 		return ErrNoBytecodeSpecified
 	}
+	if c.Bytecode != "" && len(c.BytecodeFiles) > 0 {
+		return ErrBytecodeAndFile
+	}
 	return nil
 }
 
+// validateSaltFromBytecode validates --salt-from-bytecode, which computes one address instead of
+// mining, so needs no pattern
+func (c *Config) validateSaltFromBytecode() error {
+	if err := c.validateBytecode(); err != nil {
+		return err
+	}
+	if err := c.validateFactory(); err != nil {
+		return err
+	}
+	if c.UsesCreateX() {
+		return ErrBytecodeSaltCreateX
+	}
+	return nil
+}
+
+// validateDumpPreimage validates --dump-preimage, which computes one address instead of mining,
+// so needs no pattern
+func (c *Config) validateDumpPreimage() error {
+	if err := c.validateBytecode(); err != nil {
+		return err
+	}
+	if err := c.validateFactory(); err != nil {
+		return err
+	}
+	_, err := c.ParseSaltInput(c.DumpPreimage)
+	return err
+}
+
+// validateFactory validates the factory entries, factory kind and CreateX guard options
+func (c *Config) validateFactory() error {
+	for _, f := range c.Factories {
+		if isNamedFactory(f) {
+			continue
+		}
+		if _, err := crypto.MustAddressBytes(f); err != nil {
+			return fmt.Errorf("%w (%v)", ErrInvalidFactory, err)
+		}
+		if !c.NoChecksumCheck && !checksumConsistent(f) {
+			return fmt.Errorf("%w (expected %s)", ErrFactoryChecksum, checksumOf(f))
+		}
+	}
+	if len(c.Factories) > 1 && c.TracksBest() {
+		return ErrMultiFactoryScoring
+	}
+	switch c.FactoryKind {
+	case "", FactoryKindERC2470, FactoryKindCreateX:
+	default:
+		return ErrInvalidFactoryKind
+	}
+	if !c.UsesCreateX() {
+		if c.CreateXGuard != "" {
+			return ErrGuardWithoutCreateX
+		}
+		return nil
+	}
+
+	guard, err := crypto.ParseCreateXGuard(c.CreateXGuard)
+	if err != nil {
+		return err
+	}
+	switch guard {
+	case crypto.GuardMsgSender:
+		if c.CreateXSender == "" {
+			return ErrNoCreateXSender
+		}
+		if _, err := crypto.MustAddressBytes(c.CreateXSender); err != nil {
+			return err
+		}
+	case crypto.GuardCrossChain:
+		if c.ChainID == 0 {
+			return ErrNoChainID
+		}
+	}
+	return nil
+}
+
+// ParseSaltInput parses a salt supplied on the command line using the configured salt format
+func (c *Config) ParseSaltInput(salt string) ([32]byte, error) {
+	format, err := crypto.ParseSaltFormat(c.SaltFormat)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return crypto.ParseSalt(salt, format)
+}
+
+// GetPrefixBitsPattern decodes --prefix-bits-pattern into bytes holding the PrefixBits bits to
+// match, left-aligned: an odd trailing hex character fills the high nibble of the last byte.
+// An empty pattern matches zero bits.
+func (c *Config) GetPrefixBitsPattern() ([]byte, error) {
+	if c.PrefixBits < 1 || c.PrefixBits > 160 {
+		return nil, ErrInvalidPrefixBits
+	}
+	h := strings.TrimPrefix(c.PrefixBitsPattern, "0x")
+	if h == "" {
+		return make([]byte, (c.PrefixBits+7)/8), nil
+	}
+	if len(h)%2 != 0 {
+		h += "0"
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil || len(b) > 20 || len(b)*8 < c.PrefixBits {
+		return nil, ErrInvalidPrefixBits
+	}
+	return b, nil
+}
+
+// GetFactoryAddress returns the address of the (first) factory performing the CREATE2 deployment
+func (c *Config) GetFactoryAddress() string {
+	return c.GetFactories()[0].Address
+}
+
+// GetFactories resolves the --factory entries in order, defaulting to the --factory-kind
+// deployment. Addresses are checksummed and use the CreateX guard when the kind is createx.
+func (c *Config) GetFactories() []Factory {
+	if len(c.Factories) == 0 {
+		if c.FactoryKind == FactoryKindCreateX {
+			return []Factory{{Address: crypto.CreateXAddress, CreateX: true}}
+		}
+		return []Factory{{Address: crypto.FactoryAddress}}
+	}
+	factories := make([]Factory, 0, len(c.Factories))
+	for _, f := range c.Factories {
+		switch strings.ToLower(f) {
+		case FactoryKindERC2470:
+			factories = append(factories, Factory{Address: crypto.FactoryAddress})
+		case FactoryKindCreateX:
+			factories = append(factories, Factory{Address: crypto.CreateXAddress, CreateX: true})
+		case FactoryArachnid:
+			factories = append(factories, Factory{Address: crypto.ArachnidFactoryAddress})
+		default:
+			factories = append(factories, Factory{Address: checksumOf(f), CreateX: c.FactoryKind == FactoryKindCreateX})
+		}
+	}
+	return factories
+}
+
+// UsesCreateX returns true if any factory derives its CREATE2 salt through the CreateX guard
+func (c *Config) UsesCreateX() bool {
+	for _, f := range c.GetFactories() {
+		if f.CreateX {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamedFactory reports whether a --factory entry names a well-known deployer
+func isNamedFactory(f string) bool {
+	switch strings.ToLower(f) {
+	case FactoryKindERC2470, FactoryKindCreateX, FactoryArachnid:
+		return true
+	}
+	return false
+}
+
+// Warnings returns non-fatal problems with a valid configuration, for logging at startup
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if c.Target != "" && !checksumConsistent(c.Target) {
+		warnings = append(warnings, fmt.Sprintf(
+			"--target %s has mixed-case letters that do not match its EIP-55 checksum (%s); it may have been copied incorrectly",
+			c.Target, checksumOf(c.Target)))
+	}
+	for _, f := range c.Factories {
+		if !isNamedFactory(f) && !checksumConsistent(f) {
+			warnings = append(warnings, fmt.Sprintf(
+				"--factory %s has mixed-case letters that do not match its EIP-55 checksum (%s); mining against it anyway",
+				f, checksumOf(f)))
+		}
+	}
+	if c.MatcherCmd != "" {
+		warnings = append(warnings, "--matcher-cmd filters matches further; expected attempts do not account for it")
+	}
+	if c.SaltBytes > 0 && c.SaltBytes <= 8 && c.SaltMode != SaltModeSequential {
+		warnings = append(warnings, fmt.Sprintf(
+			"--salt-bytes %d leaves %s salts, which random salts repeat; use --salt-mode sequential to try each once and stop when exhausted",
+			c.SaltBytes, c.SaltKeyspace()))
+	}
+	if c.PrivateKey != "" {
+		warnings = append(warnings, "--private-key is visible to other users in the process list; prefer "+PrivateKeyEnv)
+	}
+	return warnings
+}
+
+// checksumConsistent reports whether an address is all lowercase, all uppercase or correctly EIP-55 checksummed
+func checksumConsistent(addr string) bool {
+	h := strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	if h == strings.ToLower(h) || h == strings.ToUpper(h) {
+		return true
+	}
+	return "0x"+h == checksumOf(addr)
+}
+
+// checksumOf returns the EIP-55 form of a valid address
+func checksumOf(addr string) string {
+	b, err := crypto.MustAddressBytes(addr)
+	if err != nil {
+		return addr
+	}
+	return crypto.AddressBytesToChecksumString(b)
+}
+
+// ExpectedAttempts returns the expected number of attempts to find a match: 16 to the power of
+// the fixed nibbles across prefix, suffix, template and target, counting overlaps once, for
+// every init code. A palindrome pins one nibble of each mirrored pair and --all-same all but
+// one nibble; --low-zero-bits and --prefix-bits add the bits not already fixed by a suffix or
+// prefix, and --repeating is not counted. Each salt is tried under every factory and matches
+// any --target-file address, which divides the count. Matching ignores EIP-55 casing, so
+// letters add no difficulty. Returns nil in pure scoring modes, which never finish on a match.
+func (c *Config) ExpectedAttempts() *big.Int {
+	var fixed [40]bool
+	mark := func(from, n int) {
+		for i := from; i < from+n && i < 40; i++ {
+			fixed[i] = true
+		}
+	}
+	if c.Prefix != "" {
+		mark(c.PrefixOffset, len(strings.TrimPrefix(c.Prefix, "0x")))
+	}
+	if c.PrefixBytes != "" {
+		mark(0, len(strings.TrimPrefix(c.PrefixBytes, "0x")))
+	}
+	if suffix := c.GetSuffix(); suffix != "" {
+		n := min(len(suffix), 40)
+		mark(40-n, n)
+	}
+	if c.Template != "" {
+		for i, ch := range strings.TrimPrefix(c.Template, "0x") {
+			if ch != '.' && ch != 'x' && ch != 'X' {
+				mark(i, 1)
+			}
+		}
+	}
+	if c.Target != "" || c.TargetFile != "" {
+		mark(0, 40)
+	}
+
+	nibbles := 0
+	for _, f := range fixed {
+		if f {
+			nibbles++
+		}
+	}
+	if c.AllSame {
+		// One free character fixes the rest, unless another criterion already pinned one
+		if nibbles > 0 {
+			nibbles = 40
+		} else {
+			nibbles = 39
+		}
+	} else if c.IsPalindrome() {
+		// A mirrored pair not already fixed at both ends has one nibble pinned by its partner
+		for i := 0; i < 20; i++ {
+			if !fixed[i] || !fixed[39-i] {
+				nibbles++
+			}
+		}
+	}
+	bits := 4 * nibbles
+	for i := 0; i < c.LowZeroBits && i < 160; i++ {
+		if !fixed[39-i/4] {
+			bits++
+		}
+	}
+	for i := 0; i < c.PrefixBits && i < 160; i++ {
+		if !fixed[i/4] {
+			bits++
+		}
+	}
+	if bits == 0 {
+		return nil
+	}
+	// Every init code must match under the same salt
+	if len(c.BytecodeFiles) > 1 {
+		bits *= len(c.BytecodeFiles)
+	}
+	n := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	// Any one of the listed targets, under any one of the factories, is a hit
+	if targets, err := c.GetTargetSet(); err == nil && len(targets) > 1 {
+		n.Quo(n, big.NewInt(int64(len(targets))))
+	}
+	if factories := len(c.GetFactories()); factories > 1 {
+		n.Quo(n, big.NewInt(int64(factories)))
+	}
+	return n
+}
+
+// GetTargetDifficulty describes the expected attempts, e.g. "expected ~65,536 attempts",
+// or returns "" in pure scoring modes
+func (c *Config) GetTargetDifficulty() string {
+	n := c.ExpectedAttempts()
+	if n == nil {
+		return ""
+	}
+	return "expected ~" + formatCount(n) + " attempts"
+}
+
+// formatCount renders n with thousands separators, switching to scientific notation past 10^15
+func formatCount(n *big.Int) string {
+	if n.BitLen() > 50 {
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return fmt.Sprintf("%.2g", f)
+	}
+	digits := n.String()
+	var out strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out.WriteByte(',')
+		}
+		out.WriteRune(d)
+	}
+	return out.String()
+}
+
 // GetTargetDescription returns a human-readable description of the target
 func (c *Config) GetTargetDescription() string {
+	if c.Prefix != "" && c.PrefixOffset > 0 {
+		return fmt.Sprintf("prefix: %s after %d characters", c.Prefix, c.PrefixOffset)
+	}
 	if c.Prefix != "" {
 		return "prefix: " + c.Prefix
 	}
+	if c.PrefixBytes != "" {
+		return "prefix bytes: " + c.PrefixBytes
+	}
 	if c.Suffix != "" {
 		return "suffix: " + c.Suffix
 	}
+	if c.MatchTailOf != "" {
+		return fmt.Sprintf("suffix: %s (last %d characters of %s)", c.GetSuffix(), len(c.GetSuffix()), c.MatchTailOf)
+	}
+	if c.Template != "" {
+		return "template: " + c.Template
+	}
+	if c.Target != "" {
+		return "target: " + c.Target
+	}
+	if c.TargetFile != "" {
+		return "targets: " + c.TargetFile
+	}
+	if c.IsPalindrome() {
+		return "palindrome"
+	}
+	if c.Repeating > 0 {
+		return fmt.Sprintf("run of %d repeating characters", c.Repeating)
+	}
+	if c.LowZeroBits > 0 {
+		return fmt.Sprintf("low %d bits zero", c.LowZeroBits)
+	}
+	if c.PrefixBits > 0 {
+		if c.PrefixBitsPattern == "" {
+			return fmt.Sprintf("top %d bits zero", c.PrefixBits)
+		}
+		return fmt.Sprintf("top %d bits of %s", c.PrefixBits, c.PrefixBitsPattern)
+	}
+	if c.AllSame {
+		return "all-same characters"
+	}
+	if c.MatchExpr != "" {
+		return "expression: " + c.MatchExpr
+	}
+	if c.NumericProperty != "" {
+		return "numeric property: " + c.NumericProperty
+	}
+	if set, ok := crypto.LookupNiceSet(c.NiceSet); ok {
+		return fmt.Sprintf("nice set %s (%s)", set.Name, set.Description)
+	}
+	if c.ClosestTo != "" {
+		return "closest to: " + c.ClosestTo
+	}
+	if c.Words {
+		return "most hex words"
+	}
+	if c.Ascending {
+		return "longest ascending run"
+	}
+	if c.Score == ScoreGas {
+		return "gas score (leading zero bytes, then zero bytes)"
+	}
 	return "unknown"
 }
 
-// IsZeroPrefix returns true if the prefix is a series of 0's
+// GetAuditThreshold returns the number of prefix nibbles a near-miss must match
+func (c *Config) GetAuditThreshold() int {
+	if c.AuditThreshold > 0 {
+		return c.AuditThreshold
+	}
+	return max(1, len(strings.TrimPrefix(c.Prefix, "0x"))-2)
+}
+
+// IsPalindrome returns true if matches must be palindromes, with or without checksum casing
+func (c *Config) IsPalindrome() bool {
+	return c.Palindrome || c.PalindromeChecksum
+}
+
+// TracksBest returns true if the run scores every candidate and keeps the best, not just matches
+func (c *Config) TracksBest() bool {
+	return c.IsZeroPrefix() || c.Words || c.Ascending || c.ClosestTo != "" || c.Score != "" || c.NiceSet != ""
+}
+
+// HigherScoreWins returns true in the scoring modes that rank candidates by an integer
+// score, higher being better: --words, --ascending, --score and --nice-set
+func (c *Config) HigherScoreWins() bool {
+	return c.Words || c.Ascending || c.Score != "" || c.NiceSet != ""
+}
+
+// GetWords returns the word list for --words mode
+func (c *Config) GetWords() ([]string, error) {
+	if c.WordsFile == "" {
+		return crypto.DefaultWords, nil
+	}
+
+	content, err := os.ReadFile(c.WordsFile)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, line := range strings.Split(string(content), "\n") {
+		word := strings.ToLower(strings.TrimSpace(line))
+		if word == "" {
+			continue
+		}
+		if strings.Trim(word, "0123456789abcdef") != "" {
+			return nil, ErrInvalidWord
+		}
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		return nil, ErrInvalidWord
+	}
+	return words, nil
+}
+
+// ResultsOnStdout reports whether stdout is reserved for --print or --format result lines,
+// with logs and the summary moved to stderr
+func (c *Config) ResultsOnStdout() bool {
+	return c.Print != "" || c.Format != ""
+}
+
+// GetSuffix returns the hex suffix to match, without 0x: --suffix, or the last TailNibbles
+// characters of --match-tail-of
+func (c *Config) GetSuffix() string {
+	if c.MatchTailOf == "" {
+		return strings.TrimPrefix(c.Suffix, "0x")
+	}
+	n := c.TailNibbles
+	if n == 0 {
+		n = DefaultTailNibbles
+	}
+	tail := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(c.MatchTailOf), "0x"), "0X"))
+	return tail[max(0, len(tail)-n):]
+}
+
+// GetPrefixBytes decodes --prefix-bytes, rejecting a half byte rather than matching a nibble
+func (c *Config) GetPrefixBytes() ([]byte, error) {
+	b, err := crypto.HexToAddressBytes(c.PrefixBytes)
+	if errors.Is(err, crypto.ErrOddHex) {
+		n := len(strings.TrimPrefix(strings.TrimSpace(c.PrefixBytes), "0x"))
+		return nil, fmt.Errorf("%w (got %d hex characters, ending on a half byte)", ErrInvalidPrefixBytes, n)
+	}
+	if err != nil || len(b) == 0 || len(b) > 20 {
+		return nil, ErrInvalidPrefixBytes
+	}
+	return b, nil
+}
+
+// GetTargetSet reads --target-file: one address per line, 0x optional and in any casing,
+// with blank lines and lines starting with # skipped. Each address maps to its line number.
+// Returns nil without a target file.
+func (c *Config) GetTargetSet() (map[[20]byte]int, error) {
+	if c.TargetFile == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(c.TargetFile)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[[20]byte]int)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		b, err := crypto.MustAddressBytes(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w (line %d: %v)", ErrInvalidTargetFile, i+1, err)
+		}
+		if _, dup := targets[[20]byte(b)]; !dup {
+			targets[[20]byte(b)] = i + 1
+		}
+	}
+	if len(targets) == 0 {
+		return nil, ErrInvalidTargetFile
+	}
+	return targets, nil
+}
+
+// IsZeroPrefix returns true if the prefix is a series of 0's. An offset prefix is always
+// matched exactly, never scored.
 func (c *Config) IsZeroPrefix() bool {
-	if c.Prefix == "" {
+	if c.Prefix == "" || c.PrefixOffset > 0 {
 		return false
 	}
 
@@ -78,27 +1312,99 @@ func (c *Config) IsZeroPrefix() bool {
 	return true
 }
 
-// GetBytecode returns the bytecode to use for address calculation
+// GetBytecode returns the primary init code (bytecode plus constructor args) to use for address calculation
 func (c *Config) GetBytecode() ([]byte, error) {
-	// Check if bytecode file is specified
-	if c.BytecodeFile != "" {
-		return readBytecodeFromFile(c.BytecodeFile)
+	initcodes, err := c.GetBytecodes()
+	if err != nil {
+		return nil, err
+	}
+	return initcodes[0], nil
+}
+
+// GetBytecodes returns every init code to match against; a salt must satisfy the pattern for all of them.
+// Constructor args, when set, are appended to each bytecode.
+func (c *Config) GetBytecodes() ([][]byte, error) {
+	codes, err := c.getCreationCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Append constructor arguments to form the full init code
+	if c.ConstructorArgs != "" {
+		args := c.ConstructorArgs
+		if len(args) >= 2 && args[:2] == "0x" {
+			args = args[2:]
+		}
+		argBytes, err := hex.DecodeString(args)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%v)", ErrInvalidArgs, err)
+		}
+		for i := range codes {
+			codes[i] = append(codes[i], argBytes...)
+		}
+	}
+
+	return codes, nil
+}
+
+// GetInitCodeHash returns keccak256 of the primary init code used for CREATE2,
+// or the hash supplied directly with --initcode-hash
+func (c *Config) GetInitCodeHash() ([]byte, error) {
+	if c.InitCodeHash != "" {
+		return c.parseInitCodeHash()
+	}
+	initcode, err := c.GetBytecode()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(initcode), nil
+}
+
+// parseInitCodeHash decodes the --initcode-hash value
+func (c *Config) parseInitCodeHash() ([]byte, error) {
+	h := c.InitCodeHash
+	if len(h) >= 2 && h[:2] == "0x" {
+		h = h[2:]
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil || len(b) != 32 {
+		return nil, ErrInvalidInitCodeHash
+	}
+	return b, nil
+}
+
+// getCreationCodes returns the contract creation code from the flag or each file
+func (c *Config) getCreationCodes() ([][]byte, error) {
+	// Check if bytecode files are specified
+	if len(c.BytecodeFiles) > 0 {
+		codes := make([][]byte, 0, len(c.BytecodeFiles))
+		for _, file := range c.BytecodeFiles {
+			code, err := readBytecodeFromFile(file)
+			if err != nil {
+				return nil, err
+			}
+			codes = append(codes, code)
+		}
+		return codes, nil
 	}
 
 	// Check if bytecode is provided directly
 	if c.Bytecode != "" {
-		// Remove 0x prefix if present
-		code := c.Bytecode
+		// Remove whitespace and the 0x prefix if present
+		code := stripWhitespace(c.Bytecode)
 		if len(code) > 2 && code[:2] == "0x" {
 			code = code[2:]
 		}
 
 		// Decode hex string to bytes
+		if len(code)%2 != 0 {
+			return nil, fmt.Errorf("%w (got %d)", ErrOddBytecode, len(code))
+		}
 		bytes, err := hex.DecodeString(code)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w (%v)", ErrInvalidBytecode, err)
 		}
-		return bytes, nil
+		return [][]byte{bytes}, nil
 	}
 
 	// This should not happen if validation passes
@@ -114,8 +1420,8 @@ func readBytecodeFromFile(filename string) ([]byte, error) {
 	}
 
 	// Convert to string and clean up
-	code := string(content)
-	code = strings.TrimSpace(code)
+	// Wrapped hex files may contain line breaks or spaces anywhere
+	code := stripWhitespace(string(content))
 
 	// Remove 0x prefix if present
 	if len(code) > 2 && code[:2] == "0x" {
@@ -130,8 +1436,27 @@ func readBytecodeFromFile(filename string) ([]byte, error) {
 	// Decode hex string to bytes
 	bytes, err := hex.DecodeString(code)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w (%s: %v)", ErrInvalidBytecode, filename, err)
 	}
 
 	return bytes, nil
 }
+
+// stripWhitespace removes all spaces, tabs and line breaks from a hex string
+func stripWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// padOddHex left-pads an odd-length hex string (after any 0x) with a zero nibble
+func padOddHex(s string) string {
+	h := strings.TrimPrefix(s, "0x")
+	if len(h)%2 != 0 {
+		return "0" + h
+	}
+	return h
+}
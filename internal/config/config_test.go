@@ -0,0 +1,1171 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/internal/rpc"
+)
+
+func TestGetInitCodeHash(t *testing.T) {
+	cfg := NewConfig()
+	cfg.BytecodeFiles = []string{"../../bytecode.txt"}
+
+	// keccak256 of bytecode.txt, the init code behind the known address in the crypto tests
+	expected := "453b9684db78ed19be9b289f18e18b83dda389b1fbea527aba3ab03918de91d8"
+
+	initcodeHash, err := cfg.GetInitCodeHash()
+	if err != nil {
+		t.Fatalf("GetInitCodeHash() error = %v", err)
+	}
+	if got := hex.EncodeToString(initcodeHash); got != expected {
+		t.Errorf("GetInitCodeHash() = %s, want %s", got, expected)
+	}
+}
+
+func TestGetBytecodeConstructorArgs(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Bytecode = "0x6080"
+	cfg.ConstructorArgs = "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+	initcode, err := cfg.GetBytecode()
+	if err != nil {
+		t.Fatalf("GetBytecode() error = %v", err)
+	}
+	if len(initcode) != 2+32 {
+		t.Fatalf("GetBytecode() length = %d, want %d", len(initcode), 2+32)
+	}
+	if initcode[0] != 0x60 || initcode[1] != 0x80 || initcode[33] != 0x01 {
+		t.Errorf("GetBytecode() = %x, want bytecode followed by constructor args", initcode)
+	}
+}
+
+func TestGetBytecodeErrors(t *testing.T) {
+	badFile := filepath.Join(t.TempDir(), "bad.txt")
+	if err := os.WriteFile(badFile, []byte("0x6080zz"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func(c *Config)
+		err   error
+	}{
+		{"odd bytecode", func(c *Config) { c.Bytecode = "0x608" }, ErrOddBytecode},
+		{"non-hex bytecode", func(c *Config) { c.Bytecode = "0x60zz" }, ErrInvalidBytecode},
+		{"non-hex bytecode file", func(c *Config) { c.BytecodeFiles = []string{badFile} }, ErrInvalidBytecode},
+		{"non-hex constructor args", func(c *Config) { c.Bytecode = "6080"; c.ConstructorArgs = "0xzz" }, ErrInvalidArgs},
+		{"no bytecode", func(c *Config) {}, ErrNoBytecodeSpecified},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		tt.setup(cfg)
+		if _, err := cfg.GetBytecode(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: GetBytecode() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestValidateBytecodeAndFile(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Prefix = "dead"
+	cfg.Bytecode = "0x6080"
+	cfg.BytecodeFiles = []string{"../../bytecode.txt"}
+	if err := cfg.Validate(); !errors.Is(err, ErrBytecodeAndFile) {
+		t.Errorf("Validate() with both error = %v, want %v", err, ErrBytecodeAndFile)
+	}
+	for _, only := range []func(c *Config){
+		func(c *Config) { c.BytecodeFiles = nil },
+		func(c *Config) { c.Bytecode = "" },
+	} {
+		c := *cfg
+		only(&c)
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate() with one source error = %v", err)
+		}
+	}
+}
+
+func TestGetBytecodeWhitespace(t *testing.T) {
+	compact := "0x608060405234801561001057600080fd5b50"
+	inputs := map[string]string{
+		"multi-line":      "0x6080604052\n34801561\r\n001057600080fd\n5b50\n",
+		"space-separated": "  0x60 80 60 40 52 34 80 15 61 00 10 57 60 00 80 fd 5b 50  ",
+		"tabs":            "0x60806040\t5234801561\t001057600080fd5b50",
+	}
+
+	cfg := NewConfig()
+	cfg.Bytecode = compact
+	want, err := cfg.GetBytecode()
+	if err != nil {
+		t.Fatalf("GetBytecode() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	for name, input := range inputs {
+		t.Run(name+" flag", func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Bytecode = input
+			got, err := cfg.GetBytecode()
+			if err != nil {
+				t.Fatalf("GetBytecode() error = %v", err)
+			}
+			if hex.EncodeToString(got) != hex.EncodeToString(want) {
+				t.Errorf("GetBytecode() = %x, want %x", got, want)
+			}
+		})
+		t.Run(name+" file", func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".txt")
+			if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg := NewConfig()
+			cfg.BytecodeFiles = []string{path}
+			got, err := cfg.GetBytecode()
+			if err != nil {
+				t.Fatalf("GetBytecode() error = %v", err)
+			}
+			if hex.EncodeToString(got) != hex.EncodeToString(want) {
+				t.Errorf("GetBytecode() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestSchemaCoversConfig(t *testing.T) {
+	schema := Schema()
+	typ := reflect.TypeOf(Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			t.Errorf("Config.%s has no json tag", f.Name)
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if schema.Properties[name] == nil {
+			t.Errorf("schema is missing %s (Config.%s)", name, f.Name)
+		}
+	}
+	if len(schema.Properties) != typ.NumField()-1 {
+		t.Errorf("schema has %d properties, want every Config field but the private key", len(schema.Properties))
+	}
+	if _, ok := schema.Properties["private_key"]; ok {
+		t.Error("schema exposes the private key")
+	}
+
+	tests := []struct {
+		name string
+		typ  string
+		def  any
+	}{
+		{"prefix", "string", nil},
+		{"count", "integer", 1},
+		{"timeout", "integer", nil},
+		{"verbose", "boolean", nil},
+		{"salt_mode", "string", SaltModeRandom},
+		{"bytecode_files", "array", nil},
+	}
+	for _, tt := range tests {
+		p := schema.Properties[tt.name]
+		if p == nil || p.Type != tt.typ || p.Default != tt.def {
+			t.Errorf("schema %s = %+v, want type %s and default %v", tt.name, p, tt.typ, tt.def)
+		}
+	}
+	if p := schema.Properties["salt_mode"]; len(p.Enum) != 3 {
+		t.Errorf("salt_mode enum = %v, want random, sequential and hd", p.Enum)
+	}
+}
+
+func TestValidateFactory(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		err    error
+	}{
+		{"erc2470 default", func(c *Config) {}, nil},
+		{"createx none", func(c *Config) { c.FactoryKind = FactoryKindCreateX }, nil},
+		{"unknown kind", func(c *Config) { c.FactoryKind = "bogus" }, ErrInvalidFactoryKind},
+		{"guard without createx", func(c *Config) { c.CreateXGuard = "crosschain" }, ErrGuardWithoutCreateX},
+		{"msgsender without sender", func(c *Config) {
+			c.FactoryKind = FactoryKindCreateX
+			c.CreateXGuard = "msgsender"
+		}, ErrNoCreateXSender},
+		{"crosschain without chain id", func(c *Config) {
+			c.FactoryKind = FactoryKindCreateX
+			c.CreateXGuard = "crosschain"
+		}, ErrNoChainID},
+		{"several named factories", func(c *Config) {
+			c.Prefix = "ab"
+			c.Factories = []string{"erc2470", "arachnid", "createx"}
+		}, nil},
+		{"guard with a createx factory", func(c *Config) {
+			c.Prefix = "ab"
+			c.Factories = []string{"erc2470", "createx"}
+			c.CreateXGuard = "crosschain"
+		}, ErrNoChainID},
+		{"several factories with scoring", func(c *Config) {
+			c.Factories = []string{"erc2470", "arachnid"}
+			c.Words = true
+		}, ErrMultiFactoryScoring},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			tt.modify(cfg)
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateDeploy(t *testing.T) {
+	const key = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	tests := []struct {
+		name   string
+		envKey string
+		modify func(c *Config)
+		err    error
+	}{
+		{"flag key", "", func(c *Config) { c.PrivateKey = key }, nil},
+		{"env key", key, func(c *Config) {}, nil},
+		{"no key", "", func(c *Config) {}, ErrDeployWithoutKey},
+		{"bad key", "0x1234", func(c *Config) {}, ErrInvalidPrivateKey},
+		{"no rpc", key, func(c *Config) { c.RPCURL = "" }, ErrDeployWithoutRPC},
+		{"bad rpc", key, func(c *Config) { c.RPCURL = "localhost:8545" }, ErrInvalidRPCURL},
+		{"hash only", key, func(c *Config) {
+			c.Bytecode = ""
+			c.InitCodeHash = "0x" + strings.Repeat("ab", 32)
+		}, ErrDeployWithoutCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PrivateKeyEnv, tt.envKey)
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			cfg.Deploy = true
+			cfg.RPCURL = "http://localhost:8545"
+			tt.modify(cfg)
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFactoryChecksum(t *testing.T) {
+	const checksummed = "0xce0042B868300000d44A59004Da54A005ffdcf9f"
+	tests := []struct {
+		name    string
+		factory string
+		noCheck bool
+		err     error
+		warn    bool
+	}{
+		{"correct checksum", checksummed, false, nil, false},
+		{"all lowercase", strings.ToLower(checksummed), false, nil, false},
+		{"all uppercase", "0x" + strings.ToUpper(checksummed[2:]), false, nil, false},
+		{"incorrect checksum", "0xce0042b868300000d44A59004Da54A005ffdcf9f", false, ErrFactoryChecksum, false},
+		{"incorrect checksum allowed", "0xce0042b868300000d44A59004Da54A005ffdcf9f", true, nil, true},
+		{"too short", "0xce0042B868300000d44A59004Da54A005ffdcf", false, ErrInvalidFactory, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			cfg.Factories = []string{tt.factory}
+			cfg.NoChecksumCheck = tt.noCheck
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Fatalf("Validate() = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if got := cfg.GetFactoryAddress(); got != checksummed {
+				t.Errorf("GetFactoryAddress() = %s, want normalized %s", got, checksummed)
+			}
+			if warned := len(cfg.Warnings()) > 0; warned != tt.warn {
+				t.Errorf("Warnings() = %v, want warning: %v", cfg.Warnings(), tt.warn)
+			}
+		})
+	}
+}
+
+func TestGetFactories(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Factories = []string{"arachnid", "CreateX", strings.ToLower(crypto.FactoryAddress)}
+	want := []Factory{
+		{Address: crypto.ArachnidFactoryAddress},
+		{Address: crypto.CreateXAddress, CreateX: true},
+		{Address: crypto.FactoryAddress},
+	}
+	got := cfg.GetFactories()
+	if len(got) != len(want) {
+		t.Fatalf("GetFactories() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetFactories()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if !cfg.UsesCreateX() {
+		t.Error("UsesCreateX() = false with a createx factory")
+	}
+}
+
+func TestValidateResumeFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		resume  string
+		wantErr bool
+	}{
+		{"sequential with resume", SaltModeSequential, "0xdeadbeef", false},
+		{"random with resume", SaltModeRandom, "0xdeadbeef", true},
+		{"invalid hex", SaltModeSequential, "0xzz", true},
+		{"over 32 bytes", SaltModeSequential, "0x" + strings.Repeat("00", 33), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			cfg.SaltMode = tt.mode
+			cfg.ResumeFrom = tt.resume
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInitCodeHash(t *testing.T) {
+	valid := "0x453b9684db78ed19be9b289f18e18b83dda389b1fbea527aba3ab03918de91d8"
+	tests := []struct {
+		name     string
+		hash     string
+		bytecode string
+		err      error
+	}{
+		{"hash only", valid, "", nil},
+		{"short hash", "0x453b", "", ErrInvalidInitCodeHash},
+		{"non-hex hash", "0x" + strings.Repeat("zz", 32), "", ErrInvalidInitCodeHash},
+		{"hash and bytecode", valid, "6080", ErrHashWithBytecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.InitCodeHash = tt.hash
+			cfg.Bytecode = tt.bytecode
+			if err := cfg.Validate(); err != tt.err {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestGetTargetDifficulty(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(c *Config)
+		expected string
+	}{
+		{"one nibble", func(c *Config) { c.Prefix = "0" }, "expected ~16 attempts"},
+		{"four nibble prefix", func(c *Config) { c.Prefix = "dead" }, "expected ~65,536 attempts"},
+		{"casing is ignored", func(c *Config) { c.Prefix = "DEAD" }, "expected ~65,536 attempts"},
+		{"eight nibble prefix", func(c *Config) { c.Prefix = "0x00000000" }, "expected ~4,294,967,296 attempts"},
+		{"prefix and suffix", func(c *Config) { c.Prefix = "dead"; c.Suffix = "beef" }, "expected ~4,294,967,296 attempts"},
+		{"template overlapping prefix", func(c *Config) { c.Prefix = "de"; c.Template = "dead..ef" }, "expected ~16,777,216 attempts"},
+		{"full target", func(c *Config) { c.Target = "0x0000002DBE996066c3F322753B4AB7F245C13981" }, "expected ~1.5e+48 attempts"},
+		{"two init codes", func(c *Config) { c.Prefix = "dead"; c.BytecodeFiles = []string{"a", "b"} }, "expected ~4,294,967,296 attempts"},
+		{"two factories", func(c *Config) { c.Prefix = "dead"; c.Factories = []string{FactoryKindERC2470, FactoryArachnid} }, "expected ~32,768 attempts"},
+		{"offset prefix overlapping template", func(c *Config) { c.Prefix = "dead"; c.PrefixOffset = 2; c.Template = "00de" }, "expected ~16,777,216 attempts"},
+		{"palindrome", func(c *Config) { c.Palindrome = true }, "expected ~1.2e+24 attempts"},
+		{"palindrome mirrors prefix", func(c *Config) { c.Prefix = "dead"; c.Palindrome = true }, "expected ~7.9e+28 attempts"},
+		{"low zero bits", func(c *Config) { c.LowZeroBits = 10 }, "expected ~1,024 attempts"},
+		{"low zero bits under a suffix", func(c *Config) { c.Suffix = "00"; c.LowZeroBits = 10 }, "expected ~1,024 attempts"},
+		{"low zero bits past a suffix", func(c *Config) { c.Suffix = "0"; c.LowZeroBits = 6 }, "expected ~64 attempts"},
+		{"prefix bits", func(c *Config) { c.PrefixBits = 6 }, "expected ~64 attempts"},
+		{"prefix bits past a prefix", func(c *Config) { c.Prefix = "00"; c.PrefixBits = 13 }, "expected ~8,192 attempts"},
+		{"all same", func(c *Config) { c.AllSame = true }, "expected ~9.1e+46 attempts"},
+		{"all same with a prefix", func(c *Config) { c.Prefix = "aa"; c.AllSame = true }, "expected ~1.5e+48 attempts"},
+		{"scoring mode", func(c *Config) { c.Words = true }, ""},
+		{"ascending scoring mode", func(c *Config) { c.Ascending = true }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			tt.setup(cfg)
+			if got := cfg.GetTargetDifficulty(); got != tt.expected {
+				t.Errorf("GetTargetDifficulty() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		wantErr bool
+		warn    bool
+	}{
+		{"checksummed", "0x0000002DBE996066c3F322753B4AB7F245C13981", false, false},
+		{"lowercase", "0x0000002dbe996066c3f322753b4ab7f245c13981", false, false},
+		{"uppercase without prefix", "0000002DBE996066C3F322753B4AB7F245C13981", false, false},
+		{"bad checksum casing", "0x0000002dBE996066c3F322753B4AB7F245C13981", false, true},
+		{"too short", "0x0000002DBE996066c3F322753B4AB7F245C139", true, false},
+		{"too long", "0x0000002DBE996066c3F322753B4AB7F245C1398100", true, false},
+		{"non-hex", "0x0000002DBE996066c3F322753B4AB7F245C1398g", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Target = tt.target
+			cfg.Bytecode = "6080"
+			err := cfg.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTarget) {
+					t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidTarget)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if warned := len(cfg.Warnings()) > 0; warned != tt.warn {
+				t.Errorf("Warnings() = %v, want warning: %v", cfg.Warnings(), tt.warn)
+			}
+		})
+	}
+}
+
+func TestValidateTargetCaseSensitive(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"0x0000002DBE996066c3F322753B4AB7F245C13981", false},
+		{"0000002DBE996066c3F322753B4AB7F245C13981", false},
+		{"0x0000002dbe996066c3f322753b4ab7f245c13981", true},
+		{"0x0000002DBE996066C3F322753B4AB7F245C13981", true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Target = tt.target
+		cfg.CaseSensitive = true
+		cfg.Bytecode = "6080"
+		if err := cfg.Validate(); errors.Is(err, ErrTargetCase) != tt.wantErr {
+			t.Errorf("Validate(%s) error = %v, want ErrTargetCase: %v", tt.target, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSaltEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		resumeFrom string
+		saltEnd    string
+		err        error
+	}{
+		{"sequential", SaltModeSequential, "", "0xff", nil},
+		{"after resume point", SaltModeSequential, "0x10", "0x11", nil},
+		{"at resume point", SaltModeSequential, "0x10", "0x10", ErrInvalidSaltEnd},
+		{"random mode", SaltModeRandom, "", "0xff", ErrInvalidSaltEnd},
+		{"too long", SaltModeSequential, "", "0x" + strings.Repeat("ff", 33), crypto.ErrInvalidSaltLength},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = "00"
+		cfg.Bytecode = "6080"
+		cfg.SaltMode = tt.mode
+		cfg.ResumeFrom = tt.resumeFrom
+		cfg.SaltEnd = tt.saltEnd
+		if err := cfg.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Validate() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestValidateSaltFromBytecode(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SaltFromBytecode = true
+	if err := cfg.Validate(); !errors.Is(err, ErrNoBytecodeSpecified) {
+		t.Errorf("Validate() without bytecode error = %v, want %v", err, ErrNoBytecodeSpecified)
+	}
+	cfg.Bytecode = "6080"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() without a pattern error = %v, want none", err)
+	}
+	cfg.FactoryKind = FactoryKindCreateX
+	if err := cfg.Validate(); !errors.Is(err, ErrBytecodeSaltCreateX) {
+		t.Errorf("Validate() with CreateX error = %v, want %v", err, ErrBytecodeSaltCreateX)
+	}
+}
+
+func TestNoPatternListsSelectors(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Bytecode = "6080"
+	if err := cfg.Validate(); !errors.Is(err, ErrNoPatternSpecified) {
+		t.Fatalf("Validate() without a pattern error = %v, want %v", err, ErrNoPatternSpecified)
+	}
+
+	// Every selector Validate accepts is named in the error
+	tests := []struct {
+		flag string
+		set  func(*Config)
+	}{
+		{"--prefix", func(c *Config) { c.Prefix = "dead" }},
+		{"--prefix-bytes", func(c *Config) { c.PrefixBytes = "dead" }},
+		{"--suffix", func(c *Config) { c.Suffix = "beef" }},
+		{"--match-tail-of", func(c *Config) { c.MatchTailOf = "0x" + strings.Repeat("ab", 20) }},
+		{"--template", func(c *Config) { c.Template = "dead" + strings.Repeat("x", 36) }},
+		{"--target", func(c *Config) { c.Target = "0x" + strings.Repeat("ab", 20) }},
+		{"--target-file", func(c *Config) { c.TargetFile = "targets.txt" }},
+		{"--palindrome", func(c *Config) { c.Palindrome = true }},
+		{"--palindrome-checksum", func(c *Config) { c.PalindromeChecksum = true }},
+		{"--repeating", func(c *Config) { c.Repeating = 8 }},
+		{"--all-same", func(c *Config) { c.AllSame = true }},
+		{"--low-zero-bits", func(c *Config) { c.LowZeroBits = 16 }},
+		{"--prefix-bits", func(c *Config) { c.PrefixBits = 12 }},
+		{"--match-expr", func(c *Config) { c.MatchExpr = "prefix(dead)" }},
+		{"--numeric-property", func(c *Config) { c.NumericProperty = "addr % 97 == 0" }},
+		{"--nice-set", func(c *Config) { c.NiceSet = "eights" }},
+		{"--ascending", func(c *Config) { c.Ascending = true }},
+		{"--words", func(c *Config) { c.Words = true }},
+		{"--score", func(c *Config) { c.Score = ScoreGas }},
+		{"--closest-to", func(c *Config) { c.ClosestTo = "0x" + strings.Repeat("ab", 20) }},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Bytecode = "6080"
+		tt.set(cfg)
+		if err := cfg.Validate(); errors.Is(err, ErrNoPatternSpecified) {
+			t.Errorf("Validate() with %s error = %v, want it accepted as a pattern", tt.flag, err)
+		}
+		if !strings.Contains(ErrNoPatternSpecified.Error(), tt.flag+",") && !strings.Contains(ErrNoPatternSpecified.Error(), tt.flag+" or") &&
+			!strings.HasSuffix(ErrNoPatternSpecified.Error(), tt.flag) {
+			t.Errorf("ErrNoPatternSpecified does not list %s: %v", tt.flag, ErrNoPatternSpecified)
+		}
+	}
+}
+
+func TestValidateHDSaltMode(t *testing.T) {
+	mnemonic := filepath.Join(t.TempDir(), "mnemonic.txt")
+	if err := os.WriteFile(mnemonic, []byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		mode     string
+		file     string
+		index    uint32
+		expected error
+	}{
+		{"hd with mnemonic", SaltModeHD, mnemonic, 5, nil},
+		{"hd without mnemonic", SaltModeHD, "", 0, ErrNoMnemonic},
+		{"mnemonic without hd", SaltModeRandom, mnemonic, 0, ErrMnemonicWithoutHD},
+		{"index without hd", SaltModeSequential, "", 3, ErrMnemonicWithoutHD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			cfg.SaltMode = tt.mode
+			cfg.MnemonicFile = tt.file
+			cfg.DerivationIndex = tt.index
+			if err := cfg.Validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetWorkers(t *testing.T) {
+	tests := []struct {
+		value   string
+		workers int
+		auto    bool
+		err     error
+	}{
+		{"4", 4, false, nil},
+		{"auto", 0, true, nil},
+		{"0", 0, false, ErrInvalidWorkers},
+		{"-2", 0, false, ErrInvalidWorkers},
+		{"many", 0, false, ErrInvalidWorkers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.SetWorkers(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SetWorkers(%q) = %v, want %v", tt.value, err, tt.err)
+			}
+			if cfg.Workers != tt.workers || cfg.AutoWorkers != tt.auto {
+				t.Errorf("SetWorkers(%q) gave workers=%d auto=%v, want %d %v", tt.value, cfg.Workers, cfg.AutoWorkers, tt.workers, tt.auto)
+			}
+		})
+	}
+}
+
+func TestValidatePrefixOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		offset int
+		err    error
+	}{
+		{"default offset", "dead", 0, nil},
+		{"offset 2", "dead", 2, nil},
+		{"prefix ends at the last character", "0xdead", 36, nil},
+		{"prefix runs past the address", "dead", 37, ErrInvalidPrefixOffset},
+		{"negative offset", "dead", -1, ErrInvalidPrefixOffset},
+		{"offset without prefix", "", 2, ErrInvalidPrefixOffset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = tt.prefix
+			cfg.Suffix = "beef"
+			cfg.Bytecode = "6080"
+			cfg.PrefixOffset = tt.offset
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		suffix string
+		err    error
+	}{
+		{"hex prefix and suffix", "0xDEAD", "beef", nil},
+		{"odd suffix", "", "0xbee", nil},
+		{"odd prefix", "abc", "", ErrInvalidPrefix},
+		{"bare 0x prefix", "0x", "", ErrInvalidPrefix},
+		{"non-hex prefix", "zz", "", ErrInvalidPrefix},
+		{"prefix longer than an address", strings.Repeat("ab", 21), "", ErrInvalidPrefix},
+		{"non-hex suffix", "", "xyz", ErrInvalidSuffix},
+		{"suffix longer than an address", "", strings.Repeat("a", 41), ErrInvalidSuffix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = tt.prefix
+			cfg.Suffix = tt.suffix
+			cfg.Bytecode = "6080"
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidatePrefixBits(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    int
+		pattern string
+		wantErr bool
+	}{
+		{"zeros by default", 13, "", false},
+		{"odd pattern fills the high nibble", 6, "0xf", false},
+		{"pattern longer than needed", 3, "dead", false},
+		{"pattern too short", 13, "de", true},
+		{"non-hex pattern", 4, "zz", true},
+		{"pattern without bits", 0, "de", true},
+		{"more bits than an address", 161, "", true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.PrefixBits = tt.bits
+		cfg.PrefixBitsPattern = tt.pattern
+		cfg.Suffix = "beef"
+		cfg.Bytecode = "6080"
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidPrefixBits) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidPrefixBits: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGetTargetSet(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	good := write("good.txt", "# lost deployments\n0x0000002dbe996066c3f322753b4ab7f245c13981\n\n"+
+		"1D8BFDC5D46DC4F61D6B6115972536EBE6A8854C\n0x0000002DBE996066C3F322753B4AB7F245C13981\n")
+
+	cfg := NewConfig()
+	cfg.TargetFile = good
+	cfg.Bytecode = "6080"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	targets, err := cfg.GetTargetSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := crypto.MustAddressBytes("0000002dbe996066c3f322753b4ab7f245c13981")
+	second, _ := crypto.MustAddressBytes("1d8bfdc5d46dc4f61d6b6115972536ebe6a8854c")
+	if len(targets) != 2 || targets[[20]byte(first)] != 2 || targets[[20]byte(second)] != 4 {
+		t.Errorf("GetTargetSet() = %v, want the two distinct addresses at lines 2 and 4", targets)
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		target string
+	}{
+		{"short address", write("short.txt", "0x1234\n"), ""},
+		{"non-hex address", write("hex.txt", strings.Repeat("zz", 20)), ""},
+		{"only comments", write("empty.txt", "# nothing yet\n\n"), ""},
+		{"with --target", good, "0x0000002dbe996066c3f322753b4ab7f245c13981"},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.TargetFile = tt.file
+		cfg.Target = tt.target
+		cfg.Bytecode = "6080"
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidTargetFile) {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidTargetFile", tt.name, err)
+		}
+	}
+}
+
+func TestValidatePrefixBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefixBytes string
+		prefix      string
+		wantErr     bool
+	}{
+		{"whole bytes", "0xc0ffee", "", false},
+		{"one byte", "00", "", false},
+		{"half byte", "c0f", "", true},
+		{"single nibble", "0x0", "", true},
+		{"non-hex", "zz", "", true},
+		{"longer than an address", strings.Repeat("ab", 21), "", true},
+		{"with --prefix", "c0", "ff", true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.PrefixBytes = tt.prefixBytes
+		cfg.Prefix = tt.prefix
+		cfg.Bytecode = "6080"
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidPrefixBytes) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidPrefixBytes: %v", tt.name, err, tt.wantErr)
+		}
+	}
+	cfg := NewConfig()
+	cfg.PrefixBytes = "c0f"
+	if _, err := cfg.GetPrefixBytes(); err == nil || !strings.Contains(err.Error(), "got 3 hex characters") {
+		t.Errorf("GetPrefixBytes() error = %v, want one naming the 3 characters", err)
+	}
+}
+
+func TestValidateBestEffort(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"prefix and timeout", "dead", time.Second, false},
+		{"no timeout", "dead", 0, true},
+		{"no prefix", "", time.Second, true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = tt.prefix
+		cfg.Suffix = "beef"
+		cfg.Timeout = tt.timeout
+		cfg.BestEffort = true
+		cfg.Bytecode = "6080"
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidBestEffort) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidBestEffort: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSolidityNew(t *testing.T) {
+	const deployer = "0x00000000000000000000000000000000deadbeef"
+	tests := []struct {
+		name string
+		set  func(c *Config)
+		err  error
+	}{
+		{"deploying contract", func(c *Config) {}, nil},
+		{"with constructor args", func(c *Config) { c.ConstructorArgs = "0x2a" }, nil},
+		{"no factory", func(c *Config) { c.Factories = nil }, ErrSolidityNew},
+		{"named factory", func(c *Config) { c.Factories = []string{FactoryKindERC2470} }, ErrSolidityNew},
+		{"two factories", func(c *Config) { c.Factories = append(c.Factories, deployer) }, ErrSolidityNew},
+		{"createx", func(c *Config) { c.FactoryKind = FactoryKindCreateX }, ErrSolidityNew},
+		{"init code hash", func(c *Config) { c.Bytecode = ""; c.InitCodeHash = strings.Repeat("ab", 32) }, ErrSolidityNew},
+		{"unknown mode", func(c *Config) { c.Mode = "create3" }, ErrInvalidMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Mode = ModeSolidityNew
+			cfg.Prefix = "dead"
+			cfg.Bytecode = "6080"
+			cfg.Factories = []string{deployer}
+			tt.set(cfg)
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateSaltLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		set   func(c *Config)
+		err   error
+	}{
+		{"label", "TREASURY", func(c *Config) {}, nil},
+		{"longest label", strings.Repeat("A", MaxSaltLabel), func(c *Config) {}, nil},
+		{"sequential", "TREASURY", func(c *Config) { c.SaltMode = SaltModeSequential }, nil},
+		{"too long", strings.Repeat("A", MaxSaltLabel+1), func(c *Config) {}, ErrInvalidSaltLabel},
+		{"not printable", "TREASURY\n", func(c *Config) {}, ErrInvalidSaltLabel},
+		{"not ASCII", "TRÉSOR", func(c *Config) {}, ErrInvalidSaltLabel},
+		{"os-hybrid keeps its tail", "THIRTEEN-CHAR", func(c *Config) { c.Entropy = EntropyOSHybrid }, ErrInvalidSaltLabel},
+		{"createx", "TREASURY", func(c *Config) { c.FactoryKind = FactoryKindCreateX }, ErrSaltLabelConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "dead"
+			cfg.Bytecode = "6080"
+			cfg.SaltLabel = tt.label
+			tt.set(cfg)
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateKeepSearching(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		wantErr bool
+	}{
+		{"timeout", func(c *Config) { c.Timeout = time.Second }, false},
+		{"max attempts", func(c *Config) { c.MaxAttempts = 1000 }, false},
+		{"salt end", func(c *Config) { c.SaltMode = SaltModeSequential; c.SaltEnd = "0xff" }, false},
+		{"no budget", func(c *Config) {}, true},
+		{"several matches", func(c *Config) { c.Timeout = time.Second; c.Count = 2 }, true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = "dead"
+		cfg.KeepSearching = true
+		cfg.Bytecode = "6080"
+		tt.set(cfg)
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidKeepSearch) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidKeepSearch: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateDeterministicWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		wantErr bool
+	}{
+		{"sequential", func(c *Config) { c.SaltMode = SaltModeSequential }, false},
+		{"random", func(c *Config) { c.SaltMode = SaltModeRandom }, true},
+		{"keep searching", func(c *Config) { c.SaltMode = SaltModeSequential; c.KeepSearching = true; c.Timeout = time.Second }, true},
+		{"words", func(c *Config) { c.SaltMode = SaltModeSequential; c.Prefix = ""; c.Words = true }, true},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = "dead"
+		cfg.DeterministicWorkers = true
+		cfg.Bytecode = "6080"
+		tt.set(cfg)
+		if err := cfg.Validate(); errors.Is(err, ErrDeterministic) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrDeterministic: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateScore(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(c *Config)
+		err  error
+	}{
+		{"gas", func(c *Config) {}, nil},
+		{"gas with prefix", func(c *Config) { c.Prefix = "00" }, nil},
+		{"unknown mode", func(c *Config) { c.Score = "zeros" }, ErrInvalidScore},
+		{"with words", func(c *Config) { c.Words = true }, ErrInvalidScore},
+		{"with closest-to", func(c *Config) { c.ClosestTo = "0x" + strings.Repeat("00", 20) }, ErrInvalidScore},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Score = ScoreGas
+		cfg.Bytecode = "6080"
+		tt.set(cfg)
+		if err := cfg.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Prefix = "cafe"
+	cfg.Bytecode = "6080"
+	cfg.Factories = []string{FactoryKindCreateX}
+	cfg.SaltMode = SaltModeSequential
+	cfg.Timeout = 90 * time.Second
+	cfg.Workers = 6
+	cfg.AutoWorkers = true
+	cfg.PrivateKey = "0x01"
+	path := filepath.Join(t.TempDir(), "run.json")
+	cfg.SaveConfig = path
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := *cfg
+	want.AutoWorkers = false
+	want.SaveConfig = ""
+	want.PrivateKey = ""
+	if !reflect.DeepEqual(loaded, &want) {
+		t.Errorf("Load() = %+v, want %+v", loaded, &want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := cfg.GetInitCodeHash()
+	for _, s := range []string{cfg.GetFactories()[0].Address, hex.EncodeToString(hash)} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("saved config does not record %s", s)
+		}
+	}
+	if strings.Contains(string(data), "private") {
+		t.Errorf("saved config holds the private key:\n%s", data)
+	}
+
+	// A misspelt field is an error, not a default
+	if err := os.WriteFile(path, []byte(`{"prefx": "cafe"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() accepted an unknown field")
+	}
+}
+
+func TestValidateSaltBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(c *Config)
+		err  error
+	}{
+		{"random", func(c *Config) {}, nil},
+		{"all 32", func(c *Config) { c.SaltBytes = 32 }, nil},
+		{"too many", func(c *Config) { c.SaltBytes = 33 }, ErrInvalidSaltBytes},
+		{"negative", func(c *Config) { c.SaltBytes = -1 }, ErrInvalidSaltBytes},
+		{"resume inside", func(c *Config) { c.SaltMode = SaltModeSequential; c.ResumeFrom = "0xffffffff" }, nil},
+		{"resume outside", func(c *Config) { c.SaltMode = SaltModeSequential; c.ResumeFrom = "0x0100000000" }, ErrInvalidSaltBytes},
+		{"salt end outside", func(c *Config) { c.SaltMode = SaltModeSequential; c.SaltEnd = "0x0100000000" }, ErrInvalidSaltBytes},
+		{"salt label", func(c *Config) { c.SaltLabel = "TREASURY" }, ErrSaltBytesConflict},
+		{"os-hybrid", func(c *Config) { c.Entropy = EntropyOSHybrid }, ErrSaltBytesConflict},
+		{"createx", func(c *Config) { c.Factories = []string{FactoryKindCreateX} }, nil},
+		{"createx too wide", func(c *Config) { c.Factories = []string{FactoryKindCreateX}; c.SaltBytes = 12 }, ErrSaltBytesConflict},
+	}
+
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = "dead"
+		cfg.Bytecode = "6080"
+		cfg.SaltBytes = 4
+		tt.set(cfg)
+		if err := cfg.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	cfg := NewConfig()
+	cfg.SaltBytes = 2
+	if got := cfg.SaltKeyspace().Int64(); got != 65536 {
+		t.Errorf("SaltKeyspace() = %d, want 65536", got)
+	}
+	if last, ok := cfg.LastSalt(); !ok || last != [32]byte{30: 0xff, 31: 0xff} {
+		t.Errorf("LastSalt() = %x, %v, want 0xffff", last, ok)
+	}
+}
+
+func TestMatchTailOf(t *testing.T) {
+	const addr = "0xce0042B868300000d44A59004Da54A005ffdcf9f"
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		suffix  string
+		wantErr bool
+	}{
+		{"default nibbles", func(c *Config) {}, "5ffdcf9f", false},
+		{"odd nibbles", func(c *Config) { c.TailNibbles = 3 }, "f9f", false},
+		{"whole address", func(c *Config) { c.TailNibbles = 40 }, "ce0042b868300000d44a59004da54a005ffdcf9f", false},
+		{"too many nibbles", func(c *Config) { c.TailNibbles = 41 }, "", true},
+		{"negative nibbles", func(c *Config) { c.TailNibbles = -1 }, "", true},
+		{"short address", func(c *Config) { c.MatchTailOf = "0xdeadbeef" }, "", true},
+		{"with suffix", func(c *Config) { c.Suffix = "00" }, "", true},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Bytecode = "6080"
+		cfg.MatchTailOf = addr
+		tt.set(cfg)
+		err := cfg.Validate()
+		if errors.Is(err, ErrInvalidMatchTail) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidMatchTail: %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && cfg.GetSuffix() != tt.suffix {
+			t.Errorf("%s: GetSuffix() = %q, want %q", tt.name, cfg.GetSuffix(), tt.suffix)
+		}
+	}
+}
+
+func TestValidateNiceSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(c *Config)
+		wantErr bool
+	}{
+		{"alone", func(c *Config) {}, false},
+		{"with prefix", func(c *Config) { c.Prefix = "ab" }, false},
+		{"unknown", func(c *Config) { c.NiceSet = "sevens" }, true},
+		{"with words", func(c *Config) { c.Words = true }, true},
+		{"with gas score", func(c *Config) { c.Score = ScoreGas }, true},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Bytecode = "6080"
+		cfg.NiceSet = "mirror"
+		tt.set(cfg)
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidNiceSet) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidNiceSet: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateRPCRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"defaults", rpc.DefaultRetries, rpc.DefaultTimeout, false},
+		{"no retries", 0, time.Second, false},
+		{"negative retries", -1, time.Second, true},
+		{"no timeout", 3, 0, true},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		cfg.Prefix = "dead"
+		cfg.Bytecode = "6080"
+		cfg.RPCRetries = tt.retries
+		cfg.RPCTimeout = tt.timeout
+		if err := cfg.Validate(); errors.Is(err, ErrInvalidRPCRetry) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want ErrInvalidRPCRetry: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateEntropy(t *testing.T) {
+	device := filepath.Join(t.TempDir(), "rng")
+	if err := os.WriteFile(device, make([]byte, 64), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		entropy string
+		file    string
+		mode    string
+		err     error
+	}{
+		{"crypto", EntropyCrypto, "", SaltModeRandom, nil},
+		{"os-hybrid", EntropyOSHybrid, "", SaltModeRandom, nil},
+		{"file", EntropyFile, device, SaltModeRandom, nil},
+		{"file without path", EntropyFile, "", SaltModeRandom, ErrEntropyFile},
+		{"path without file mode", EntropyCrypto, device, SaltModeRandom, ErrEntropyFile},
+		{"unknown source", "dice", "", SaltModeRandom, ErrInvalidEntropy},
+		{"hybrid with sequential salts", EntropyOSHybrid, "", SaltModeSequential, ErrEntropyNotRandom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Prefix = "00"
+			cfg.Bytecode = "6080"
+			cfg.Entropy = tt.entropy
+			cfg.EntropyFile = tt.file
+			cfg.SaltMode = tt.mode
+			if err := cfg.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
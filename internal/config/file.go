@@ -0,0 +1,54 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SavedConfig is the file --save-config writes: the run's job file, with the values resolved
+// from it at startup recorded alongside. Load reads the job file back and ignores them.
+type SavedConfig struct {
+	*Config
+	ResolvedFactories    []string `json:"resolved_factories"`     // checksummed factory addresses
+	ResolvedInitCodeHash string   `json:"resolved_initcode_hash"` // keccak256 of the primary init code
+}
+
+// Save writes the effective configuration to path as indented JSON. The worker count is
+// saved as tuned, so a reloaded run does not benchmark again, and the private key is left out.
+func (c *Config) Save(path string) error {
+	saved := *c
+	saved.AutoWorkers = false
+	saved.SaveConfig = "" // reloading must not overwrite the archived file
+	out := SavedConfig{Config: &saved}
+	for _, f := range c.GetFactories() {
+		out.ResolvedFactories = append(out.ResolvedFactories, f.Address)
+	}
+	if hash, err := c.GetInitCodeHash(); err == nil {
+		out.ResolvedInitCodeHash = "0x" + hex.EncodeToString(hash)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Load reads a job file, such as one written by Save, over the defaults of NewConfig.
+// Unknown fields are rejected so a misspelt option is not silently ignored.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	saved := SavedConfig{Config: NewConfig()}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&saved); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return saved.Config, nil
+}
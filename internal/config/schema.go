@@ -0,0 +1,97 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/color"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+)
+
+// SchemaURI is the JSON Schema dialect Schema emits
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Property describes one job file field in a JSON Schema
+type Property struct {
+	Type        string    `json:"type"`
+	Items       *Property `json:"items,omitempty"`
+	Enum        []string  `json:"enum,omitempty"`
+	Default     any       `json:"default,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// JSONSchema is the schema of a job file, the JSON form of Config
+type JSONSchema struct {
+	Schema               string               `json:"$schema"`
+	Title                string               `json:"title"`
+	Type                 string               `json:"type"`
+	Properties           map[string]*Property `json:"properties"`
+	AdditionalProperties bool                 `json:"additionalProperties"`
+}
+
+// schemaEnums lists the accepted values of the string fields that take one of a fixed set
+var schemaEnums = map[string][]string{
+	"factory_kind":   {FactoryKindERC2470, FactoryKindCreateX},
+	"mode":           {ModeFactory, ModeSolidityNew},
+	"createx_guard":  {"none", "msgsender", "crosschain"},
+	"salt_mode":      {SaltModeRandom, SaltModeSequential, SaltModeHD},
+	"salt_format":    {"hex", "decimal"},
+	"entropy":        {EntropyCrypto, EntropyOSHybrid, EntropyFile},
+	"best":           {BestLowest, BestHighest},
+	"keccak_backend": append(crypto.KeccakBackends(), crypto.KeccakAuto),
+	"color":          {color.ModeAuto, color.ModeAlways, color.ModeNever},
+	"summary":        {SummaryText, SummaryJSON},
+	"print":          {PrintSalt, PrintAddress, PrintBoth},
+	"format":         {FormatCreate2Crunch},
+	"score":          {ScoreGas},
+	"nice_set":       crypto.NiceSetNames(),
+}
+
+// Schema describes every Config field that can be set from a job file, with its JSON type,
+// accepted values and the default NewConfig gives it. Durations are nanoseconds, as
+// encoding/json writes time.Duration.
+func Schema() *JSONSchema {
+	s := &JSONSchema{
+		Schema:     SchemaURI,
+		Title:      "erc2470-miner job",
+		Type:       "object",
+		Properties: map[string]*Property{},
+	}
+	defaults := reflect.ValueOf(NewConfig()).Elem()
+	t := defaults.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		p := schemaProperty(f.Type)
+		p.Enum = schemaEnums[name]
+		if v := defaults.Field(i); !v.IsZero() {
+			p.Default = v.Interface()
+		}
+		s.Properties[name] = p
+	}
+	// The worker default depends on the machine printing the schema
+	s.Properties["workers"].Default = nil
+	s.Properties["workers"].Description = "defaults to the number of CPUs"
+	return s
+}
+
+// schemaProperty maps a Go field type to its JSON Schema type
+func schemaProperty(t reflect.Type) *Property {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return &Property{Type: "integer", Description: "nanoseconds"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Property{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return &Property{Type: "integer"}
+	case reflect.Slice:
+		return &Property{Type: "array", Items: schemaProperty(t.Elem())}
+	default:
+		return &Property{Type: "string"}
+	}
+}
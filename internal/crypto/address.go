@@ -6,14 +6,15 @@ import (
 	"fmt"
 	"hash"
 	"strings"
-
-	"golang.org/x/crypto/sha3"
 )
 
 const (
 	// ERC-2470 Singleton Factory address
 	FactoryAddress = "0xce0042B868300000d44A59004Da54A005ffdcf9f"
 
+	// Arachnid's deterministic deployment proxy, a plain CREATE2 deployer
+	ArachnidFactoryAddress = "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+
 	// CREATE2 input layout: 0xff (1) + factory (20) + salt (32) + initcodeHash (32) = 85
 	Create2PrefixLen = 1 + 20
 	Create2SaltLen   = 32
@@ -21,6 +22,13 @@ const (
 	Create2InputLen  = Create2PrefixLen + Create2SaltLen + Create2SuffixLen
 )
 
+// Errors returned when decoding addresses and hex patterns
+var (
+	ErrInvalidAddressLength = errors.New("address must be exactly 40 hex characters")
+	ErrInvalidAddressHex    = errors.New("address is not valid hex")
+	ErrOddHex               = errors.New("hex string must have even length")
+)
+
 var (
 	// Pre-primed factory address with 0xff prefix for CREATE2 (1 + 20 = 21 bytes)
 	create2Prefix = [Create2PrefixLen]byte{
@@ -34,6 +42,25 @@ func Create2PrefixBytes() [Create2PrefixLen]byte {
 	return create2Prefix
 }
 
+// Create2PrefixFor returns the CREATE2 input prefix (0xff + factory, 21 bytes) for an arbitrary factory.
+func Create2PrefixFor(factory []byte) [Create2PrefixLen]byte {
+	var prefix [Create2PrefixLen]byte
+	prefix[0] = 0xff
+	copy(prefix[1:], factory)
+	return prefix
+}
+
+// Create2Preimage returns the 85 bytes CREATE2 hashes: 0xff ++ factory (20) ++ salt (32) ++
+// initCodeHash (32)
+func Create2Preimage(factory, salt, initCodeHash []byte) [Create2InputLen]byte {
+	var input [Create2InputLen]byte
+	prefix := Create2PrefixFor(factory)
+	copy(input[:], prefix[:])
+	copy(input[Create2PrefixLen:], salt)
+	copy(input[Create2PrefixLen+Create2SaltLen:], initCodeHash)
+	return input
+}
+
 // Create2AddressInto hashes CREATE2 input and writes the 20-byte address into addrBuf.
 // Reuses the provided hasher to avoid allocations. inputBuf must be Create2InputLen (85),
 // hashBuf must be at least 32 bytes, addrBuf must be 20 bytes.
@@ -71,10 +98,18 @@ func CalculateCreate2Address(initCodeHash []byte, saltBytes []byte) string {
 	return toChecksumAddress(addressBytes)
 }
 
+// CalculateCreate2AddressFor calculates the checksummed CREATE2 address deployed by an arbitrary
+// 20-byte factory
+func CalculateCreate2AddressFor(factory, initCodeHash, saltBytes []byte) string {
+	prefix := Create2PrefixFor(factory)
+	input := append(append(prefix[:], saltBytes...), initCodeHash...)
+	return toChecksumAddress(keccak256Bytes(input)[12:])
+}
+
 // ---- helpers ----
 
 func keccak256Bytes(b []byte) []byte {
-	h := sha3.NewLegacyKeccak256()
+	h := keccakBackends[DefaultKeccakBackend]()
 	_, _ = h.Write(b)
 	return h.Sum(nil)
 }
@@ -92,7 +127,7 @@ func HexToAddressBytes(hexStr string) ([]byte, error) {
 		h = h[2:]
 	}
 	if len(h)%2 != 0 {
-		return nil, fmt.Errorf("hex string must have even length")
+		return nil, ErrOddHex
 	}
 	return hex.DecodeString(h)
 }
@@ -104,15 +139,89 @@ func MustAddressBytes(addr string) ([]byte, error) {
 		h = h[2:]
 	}
 	if len(h) != 40 {
-		return nil, fmt.Errorf("invalid address length: got %d hex chars, want 40", len(h))
+		return nil, fmt.Errorf("%w (got %d)", ErrInvalidAddressLength, len(h))
 	}
 	b, err := hex.DecodeString(h)
 	if err != nil {
-		return nil, fmt.Errorf("invalid address hex: %w", err)
+		return nil, fmt.Errorf("%w (%v)", ErrInvalidAddressHex, err)
 	}
 	return b, nil
 }
 
+// LeadingZeroNibbles counts the leading zero hex characters of a raw address.
+func LeadingZeroNibbles(addr []byte) int {
+	n := 0
+	for _, b := range addr {
+		if b != 0 {
+			if b>>4 == 0 {
+				n++
+			}
+			break
+		}
+		n += 2
+	}
+	return n
+}
+
+// ZeroBytes counts the leading zero bytes and the total zero bytes of a raw address. Each zero
+// byte in calldata costs 4 gas instead of 16, and leading ones shorten a PUSH.
+func ZeroBytes(addr []byte) (leading, total int) {
+	leading = -1
+	for i, b := range addr {
+		if b != 0 {
+			if leading < 0 {
+				leading = i
+			}
+			continue
+		}
+		total++
+	}
+	if leading < 0 {
+		leading = len(addr)
+	}
+	return leading, total
+}
+
+// GasScore ranks an address the way create2crunch does: by leading zero bytes, ties broken by
+// total zero bytes. Leading zeros always dominate, as an address holds at most 20 zero bytes.
+func GasScore(addr []byte) int {
+	leading, total := ZeroBytes(addr)
+	return leading*(len(addr)+1) + total
+}
+
+// MatchingPrefixNibbles counts how many leading hex characters of addr equal those of prefix.
+func MatchingPrefixNibbles(addr, prefix []byte) int {
+	n := 0
+	for i := 0; i < len(addr) && i < len(prefix); i++ {
+		if addr[i] != prefix[i] {
+			if addr[i]>>4 == prefix[i]>>4 {
+				n++
+			}
+			return n
+		}
+		n += 2
+	}
+	return n
+}
+
+// ShiftNibbles returns addr with its first offset hex characters dropped and zeros shifted
+// in at the end, so prefix helpers can compare from a nibble offset.
+func ShiftNibbles(addr []byte, offset int) [20]byte {
+	var out [20]byte
+	start := offset / 2
+	if offset%2 == 0 {
+		copy(out[:], addr[min(start, len(addr)):])
+		return out
+	}
+	for i := 0; start+i < len(addr); i++ {
+		out[i] = addr[start+i] << 4
+		if start+i+1 < len(addr) {
+			out[i] |= addr[start+i+1] >> 4
+		}
+	}
+	return out
+}
+
 // toChecksumAddress converts 20-byte address to EIP-55 checksummed string.
 func toChecksumAddress(addr20 []byte) string {
 	if len(addr20) != 20 {
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -63,3 +64,95 @@ func TestCalculateCreate2Address(t *testing.T) {
 		t.Errorf("CalculateCreate2Address() = %s, want %s", address, expectedAddress)
 	}
 }
+
+func TestMatchingPrefixNibbles(t *testing.T) {
+	addr := []byte{0xab, 0xcd, 0xef}
+	tests := []struct {
+		prefix   []byte
+		expected int
+	}{
+		{[]byte{0xab, 0xcd}, 4},
+		{[]byte{0xab, 0xc0}, 3},
+		{[]byte{0xa0}, 1},
+		{[]byte{0x12}, 0},
+	}
+	for _, tt := range tests {
+		if got := MatchingPrefixNibbles(addr, tt.prefix); got != tt.expected {
+			t.Errorf("MatchingPrefixNibbles(%x, %x) = %d, want %d", addr, tt.prefix, got, tt.expected)
+		}
+	}
+}
+
+func TestGasScore(t *testing.T) {
+	addr := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	tests := []struct {
+		address        string
+		leading, total int
+	}{
+		{"0000000000000000000000000000000000000000", 20, 20},
+		{"0000ab0000cd00ef00000000000000000000ff00", 2, 16},
+		{"00ab000000000000000000000000000000000000", 1, 19},
+		{"ab00000000000000000000000000000000000000", 0, 19},
+		{"0a00000000000000000000000000000000000000", 0, 19},
+	}
+	for _, tt := range tests {
+		if leading, total := ZeroBytes(addr(tt.address)); leading != tt.leading || total != tt.total {
+			t.Errorf("ZeroBytes(%s) = %d, %d, want %d, %d", tt.address, leading, total, tt.leading, tt.total)
+		}
+	}
+
+	// Each pair is listed better first
+	pairs := []struct {
+		name          string
+		better, worse string
+	}{
+		{"leading zeros beat total zeros", "0000ffffffffffffffffffffffffffffffffffff", "00ff000000000000000000000000000000000000"},
+		{"tie broken by total zeros", "0000ff00ffffffffffffffffffffffffffffffff", "0000ffffffffffffffffffffffffffffffffffff"},
+		{"a leading zero nibble does not count", "00ffffffffffffffffffffffffffffffffffff00", "000fffffffffffffffffffffffffffffffffffff"},
+	}
+	for _, p := range pairs {
+		if b, w := GasScore(addr(p.better)), GasScore(addr(p.worse)); b <= w {
+			t.Errorf("%s: GasScore(%s) = %d, not above GasScore(%s) = %d", p.name, p.better, b, p.worse, w)
+		}
+	}
+
+	// Equal counts tie
+	if a, b := GasScore(addr("00ff00ffffffffffffffffffffffffffffffffff")), GasScore(addr("00ffffffffffffffffffffffffffffffffffff00")); a != b {
+		t.Errorf("GasScore of equal counts = %d and %d, want a tie", a, b)
+	}
+}
+
+func TestAddressErrors(t *testing.T) {
+	tests := []struct {
+		addr string
+		err  error
+	}{
+		{"0x0000002DBE996066c3F322753B4AB7F245C139", ErrInvalidAddressLength},
+		{"", ErrInvalidAddressLength},
+		{"0x0000002DBE996066c3F322753B4AB7F245C1398g", ErrInvalidAddressHex},
+	}
+	for _, tt := range tests {
+		if _, err := MustAddressBytes(tt.addr); !errors.Is(err, tt.err) {
+			t.Errorf("MustAddressBytes(%q) error = %v, want %v", tt.addr, err, tt.err)
+		}
+	}
+	if _, err := HexToAddressBytes("0xabc"); !errors.Is(err, ErrOddHex) {
+		t.Errorf("HexToAddressBytes(0xabc) error = %v, want %v", err, ErrOddHex)
+	}
+}
+
+func TestCalculateCreate2AddressFor(t *testing.T) {
+	hash := Keccak256([]byte{0x60, 0x80})
+	salt := make([]byte, 32)
+	salt[31] = 0x2a
+	factory, _ := MustAddressBytes(FactoryAddress)
+	if got, want := CalculateCreate2AddressFor(factory, hash, salt), CalculateCreate2Address(hash, salt); got != want {
+		t.Errorf("CalculateCreate2AddressFor(ERC-2470) = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// CreateX factory address (same on every supported chain)
+const CreateXAddress = "0xba5Ed099633D3B313e4D5F7bdc1305d3c28ba5Ed"
+
+// CreateXGuard selects how CreateX's _guard derives the effective CREATE2 salt
+type CreateXGuard int
+
+const (
+	// GuardNone: zero address in salt[0:20], flag 0x00; guarded salt = keccak256(salt)
+	GuardNone CreateXGuard = iota
+	// GuardMsgSender: sender in salt[0:20], flag 0x00; guarded salt = keccak256(pad32(sender) || salt)
+	GuardMsgSender
+	// GuardCrossChain: zero address in salt[0:20], flag 0x01; guarded salt = keccak256(uint256(chainid) || salt)
+	GuardCrossChain
+)
+
+// ParseCreateXGuard parses a --createx-guard value
+func ParseCreateXGuard(s string) (CreateXGuard, error) {
+	switch s {
+	case "", "none":
+		return GuardNone, nil
+	case "msgsender":
+		return GuardMsgSender, nil
+	case "crosschain":
+		return GuardCrossChain, nil
+	default:
+		return GuardNone, fmt.Errorf("invalid CreateX guard %q (want none, msgsender or crosschain)", s)
+	}
+}
+
+// ApplyCreateXSaltFlags writes the sender bytes and protection flag that select the guard mode
+// into salt[0:21]. The remaining 11 bytes are left for mining.
+func ApplyCreateXSaltFlags(guard CreateXGuard, sender []byte, salt *[32]byte) {
+	switch guard {
+	case GuardMsgSender:
+		copy(salt[0:20], sender)
+		salt[20] = 0x00
+	case GuardCrossChain:
+		clear(salt[0:20])
+		salt[20] = 0x01
+	default:
+		clear(salt[0:20])
+		salt[20] = 0x00
+	}
+}
+
+// CreateXGuardedSaltInto computes the salt CreateX passes to CREATE2 for a raw user salt,
+// following CreateX's _guard rules. Reuses the hasher and writes the result into out.
+func CreateXGuardedSaltInto(hasher hash.Hash, guard CreateXGuard, sender []byte, chainID uint64, salt, out *[32]byte) {
+	var word [32]byte
+	hasher.Reset()
+	switch guard {
+	case GuardMsgSender:
+		copy(word[12:], sender)
+		hasher.Write(word[:])
+	case GuardCrossChain:
+		binary.BigEndian.PutUint64(word[24:], chainID)
+		hasher.Write(word[:])
+	}
+	hasher.Write(salt[:])
+	hasher.Sum(out[:0])
+}
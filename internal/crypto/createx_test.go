@@ -0,0 +1,88 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func TestCreateXGuardedSalt(t *testing.T) {
+	sender, _ := MustAddressBytes("0x000000000000000000000000000000000000dEaD")
+
+	var raw [32]byte
+	for i := range raw {
+		raw[i] = byte(i + 1)
+	}
+
+	tests := []struct {
+		name     string
+		guard    CreateXGuard
+		flag     byte
+		preimage func(salt [32]byte) []byte
+	}{
+		{
+			name: "none",
+			flag: 0x00,
+			preimage: func(salt [32]byte) []byte {
+				return salt[:]
+			},
+		},
+		{
+			name:  "msgsender",
+			guard: GuardMsgSender,
+			flag:  0x00,
+			preimage: func(salt [32]byte) []byte {
+				word := make([]byte, 32)
+				copy(word[12:], sender)
+				return append(word, salt[:]...)
+			},
+		},
+		{
+			name:  "crosschain",
+			guard: GuardCrossChain,
+			flag:  0x01,
+			preimage: func(salt [32]byte) []byte {
+				word := make([]byte, 32)
+				word[31] = 10 // chain id
+				return append(word, salt[:]...)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salt := raw
+			ApplyCreateXSaltFlags(tt.guard, sender, &salt)
+
+			wantSender := make([]byte, 20)
+			if tt.guard == GuardMsgSender {
+				wantSender = sender
+			}
+			if !bytes.Equal(salt[0:20], wantSender) || salt[20] != tt.flag {
+				t.Fatalf("salt[0:21] = %x, want %x%02x", salt[0:21], wantSender, tt.flag)
+			}
+			if !bytes.Equal(salt[21:], raw[21:]) {
+				t.Errorf("salt[21:] = %x, want mined bytes %x left untouched", salt[21:], raw[21:])
+			}
+
+			var guarded [32]byte
+			CreateXGuardedSaltInto(sha3.NewLegacyKeccak256(), tt.guard, sender, 10, &salt, &guarded)
+			if want := Keccak256(tt.preimage(salt)); !bytes.Equal(guarded[:], want) {
+				t.Errorf("CreateXGuardedSaltInto() = %x, want %x", guarded, want)
+			}
+		})
+	}
+}
+
+func TestParseCreateXGuard(t *testing.T) {
+	for in, want := range map[string]CreateXGuard{"": GuardNone, "none": GuardNone, "msgsender": GuardMsgSender, "crosschain": GuardCrossChain} {
+		got, err := ParseCreateXGuard(in)
+		if err != nil || got != want {
+			t.Errorf("ParseCreateXGuard(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseCreateXGuard("bogus"); err == nil {
+		t.Error("ParseCreateXGuard(\"bogus\") expected error")
+	}
+}
@@ -0,0 +1,46 @@
+package crypto
+
+import (
+	"math/big"
+)
+
+// DistanceTo returns |addr - target| treating both hex addresses as 160-bit unsigned integers.
+// It returns nil if either address is invalid.
+func DistanceTo(addr, target string) *big.Int {
+	a, err := MustAddressBytes(addr)
+	if err != nil {
+		return nil
+	}
+	t, err := MustAddressBytes(target)
+	if err != nil {
+		return nil
+	}
+	d := new(big.Int).SetBytes(a)
+	return d.Abs(d.Sub(d, new(big.Int).SetBytes(t)))
+}
+
+// AddressDistance returns |a - b| as a big-endian 20-byte value. Comparing two distances
+// lexicographically orders them numerically, without allocating on the hot path.
+func AddressDistance(a, b [20]byte) [20]byte {
+	// Subtract the smaller from the larger so the result never underflows
+	for i := 0; i < 20; i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				a, b = b, a
+			}
+			break
+		}
+	}
+	var out [20]byte
+	borrow := 0
+	for i := 19; i >= 0; i-- {
+		d := int(a[i]) - int(b[i]) - borrow
+		borrow = 0
+		if d < 0 {
+			d += 256
+			borrow = 1
+		}
+		out[i] = byte(d)
+	}
+	return out
+}
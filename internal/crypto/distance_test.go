@@ -0,0 +1,42 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDistanceTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		target   string
+		expected string // hex
+	}{
+		{"equal", "0x1234567890123456789012345678901234567890", "0x1234567890123456789012345678901234567890", "0"},
+		{"above target", "0x0000000000000000000000000000000000000105", "0x0000000000000000000000000000000000000100", "5"},
+		{"below target", "0x00000000000000000000000000000000000000ff", "0x0000000000000000000000000000000000000100", "1"},
+		{"full range", "0xffffffffffffffffffffffffffffffffffffffff", "0x0000000000000000000000000000000000000000", "ffffffffffffffffffffffffffffffffffffffff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceTo(tt.addr, tt.target)
+			want, _ := new(big.Int).SetString(tt.expected, 16)
+			if got == nil || got.Cmp(want) != 0 {
+				t.Fatalf("DistanceTo() = %v, want %v", got, want)
+			}
+
+			// The allocation-free byte form must agree with the big.Int form
+			a, _ := MustAddressBytes(tt.addr)
+			b, _ := MustAddressBytes(tt.target)
+			d := AddressDistance([20]byte(a), [20]byte(b))
+			if new(big.Int).SetBytes(d[:]).Cmp(want) != 0 {
+				t.Errorf("AddressDistance() = %x, want %v", d, want.Text(16))
+			}
+		})
+	}
+
+	if DistanceTo("0x1234", "0x1234567890123456789012345678901234567890") != nil {
+		t.Error("DistanceTo() with an invalid address should return nil")
+	}
+}
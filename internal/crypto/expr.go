@@ -0,0 +1,271 @@
+package crypto
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MatchExpr is a compiled --match-expr boolean expression over address predicates.
+//
+// Grammar, with NOT binding tighter than AND and AND tighter than OR:
+//
+//	expr    = and { ("or" | "||") and }
+//	and     = unary { ("and" | "&&") unary }
+//	unary   = ("not" | "!") unary | "(" expr ")" | predicate
+//	predicate = "prefix" HEX | "suffix" HEX | "contains" HEX | "zerobytes" N | numeric
+//	numeric = "addr" [ "%" N ] ("==" | "!=" | "<" | "<=" | ">" | ">=") N
+//
+// Keywords and hex are case-insensitive. zerobytes N holds when the address starts
+// with at least N zero bytes. A numeric predicate compares the address as a 160-bit
+// integer, or its remainder modulo N, with a decimal or 0x-prefixed hex number.
+type MatchExpr struct {
+	root exprNode
+}
+
+// exprNode evaluates against the address bytes and their lowercase hex
+type exprNode interface {
+	eval(addr []byte, hexAddr []byte) bool
+}
+
+type andNode struct{ l, r exprNode }
+type orNode struct{ l, r exprNode }
+type notNode struct{ x exprNode }
+type prefixNode struct{ s []byte }
+type suffixNode struct{ s []byte }
+type containsNode struct{ s []byte }
+type zeroBytesNode struct{ n int }
+
+// numericNode compares the address, or its remainder modulo mod when mod is nonzero, with
+// value. Without a modulus value is held as 20 big-endian bytes, so neither side allocates.
+type numericNode struct {
+	mod   uint64
+	op    string
+	value uint64   // with a modulus
+	bound [20]byte // without one
+}
+
+func (n andNode) eval(a, h []byte) bool      { return n.l.eval(a, h) && n.r.eval(a, h) }
+func (n orNode) eval(a, h []byte) bool       { return n.l.eval(a, h) || n.r.eval(a, h) }
+func (n notNode) eval(a, h []byte) bool      { return !n.x.eval(a, h) }
+func (n prefixNode) eval(_, h []byte) bool   { return bytes.HasPrefix(h, n.s) }
+func (n suffixNode) eval(_, h []byte) bool   { return bytes.HasSuffix(h, n.s) }
+func (n containsNode) eval(_, h []byte) bool { return bytes.Contains(h, n.s) }
+func (n zeroBytesNode) eval(a, _ []byte) bool {
+	for i := 0; i < n.n; i++ {
+		if a[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (n numericNode) eval(a, _ []byte) bool {
+	var c int
+	if n.mod != 0 {
+		var r uint64
+		for _, b := range a {
+			r = (r<<8 | uint64(b)) % n.mod
+		}
+		c = cmp.Compare(r, n.value)
+	} else {
+		c = bytes.Compare(a, n.bound[:])
+	}
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// maxModulus keeps the remainder shifted left by a byte within a uint64
+const maxModulus = 1<<56 - 1
+
+// Match reports whether a 20-byte address satisfies the expression
+func (e *MatchExpr) Match(addr []byte) bool {
+	var hexAddr [40]byte
+	hex.Encode(hexAddr[:], addr)
+	return e.root.eval(addr, hexAddr[:])
+}
+
+// ParseMatchExpr compiles a --match-expr expression, e.g.
+// "prefix dead and zerobytes 3 and not contains beef".
+func ParseMatchExpr(src string) (*MatchExpr, error) {
+	return parseExpr(src, "match expression", false)
+}
+
+// ParseNumericProperty compiles a --numeric-property expression: the grammar of
+// ParseMatchExpr restricted to numeric predicates, e.g. "addr % 97 == 0 and addr < 0x1000".
+func ParseNumericProperty(src string) (*MatchExpr, error) {
+	return parseExpr(src, "numeric property", true)
+}
+
+func parseExpr(src, what string, numericOnly bool) (*MatchExpr, error) {
+	p := &exprParser{tokens: tokenizeExpr(src), what: what, numericOnly: numericOnly}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("%s is empty", what)
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%s: unexpected %q", what, p.tokens[p.pos])
+	}
+	return &MatchExpr{root: root}, nil
+}
+
+// exprToken matches one token: a parenthesis, an operator, or a run of anything else
+var exprToken = regexp.MustCompile(`[()]|==|!=|<=|>=|&&|\|\||[<>%!=&|]|[^\s()<>=!%&|]+`)
+
+// tokenizeExpr splits on whitespace and around parentheses and operators
+func tokenizeExpr(src string) []string {
+	return exprToken.FindAllString(strings.ToLower(src), -1)
+}
+
+type exprParser struct {
+	tokens      []string
+	pos         int
+	what        string // names the expression in errors
+	numericOnly bool   // reject the hex predicates
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "or" || t == "||"; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "and" || t == "&&"; t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	t := p.next()
+	if p.numericOnly && (t == "prefix" || t == "suffix" || t == "contains" || t == "zerobytes") {
+		return nil, fmt.Errorf("%s: only addr comparisons are allowed, got %q", p.what, t)
+	}
+	switch t {
+	case "not", "!":
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("%s: missing )", p.what)
+		}
+		return x, nil
+	case "addr":
+		return p.parseNumeric()
+	case "prefix", "suffix", "contains":
+		arg := strings.TrimPrefix(p.next(), "0x")
+		if arg == "" || len(arg) > 40 || strings.Trim(arg, "0123456789abcdef") != "" {
+			return nil, fmt.Errorf("%s: %s needs 1 to 40 hex characters", p.what, t)
+		}
+		switch t {
+		case "prefix":
+			return prefixNode{[]byte(arg)}, nil
+		case "suffix":
+			return suffixNode{[]byte(arg)}, nil
+		}
+		return containsNode{[]byte(arg)}, nil
+	case "zerobytes":
+		n, err := strconv.Atoi(p.next())
+		if err != nil || n < 1 || n > 20 {
+			return nil, fmt.Errorf("%s: zerobytes needs a count from 1 to 20", p.what)
+		}
+		return zeroBytesNode{n}, nil
+	case "":
+		return nil, fmt.Errorf("%s: unexpected end", p.what)
+	default:
+		return nil, fmt.Errorf("%s: unknown predicate %q", p.what, t)
+	}
+}
+
+// parseNumeric parses the rest of a numeric predicate after "addr"
+func (p *exprParser) parseNumeric() (exprNode, error) {
+	var n numericNode
+	if p.peek() == "%" {
+		p.next()
+		mod, ok := p.number()
+		if !ok || mod.Sign() == 0 || mod.Cmp(big.NewInt(maxModulus)) > 0 {
+			return nil, fmt.Errorf("%s: addr %% needs a modulus from 1 to 2^56-1", p.what)
+		}
+		n.mod = mod.Uint64()
+	}
+	switch n.op = p.next(); n.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return nil, fmt.Errorf("%s: addr needs a comparison (==, !=, <, <=, > or >=), got %q", p.what, n.op)
+	}
+	value, ok := p.number()
+	if !ok || n.mod == 0 && value.BitLen() > 160 || n.mod != 0 && !value.IsUint64() {
+		return nil, fmt.Errorf("%s: addr %s needs a number that fits the address or modulus", p.what, n.op)
+	}
+	if n.mod != 0 {
+		n.value = value.Uint64()
+	} else {
+		value.FillBytes(n.bound[:])
+	}
+	return n, nil
+}
+
+// number parses the next token as a non-negative decimal or 0x-prefixed hex integer
+func (p *exprParser) number() (*big.Int, bool) {
+	n, ok := new(big.Int).SetString(p.next(), 0)
+	return n, ok && n.Sign() >= 0
+}
@@ -0,0 +1,134 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMatchExpr(t *testing.T) {
+	// Three leading zero bytes, then "dead", with "beef" at the end
+	addr, _ := MustAddressBytes("0x000000dead1234567890abcdef1234567890beef")
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"prefix 000000dead", true},
+		{"prefix DEAD", false},
+		{"suffix beef", true},
+		{"zerobytes 3", true},
+		{"zerobytes 4", false},
+		{"contains dead and zerobytes 3 and not contains beef", false},
+		{"contains dead && zerobytes 3 && !contains cafe", true},
+		// AND binds tighter than OR
+		{"prefix ff or suffix beef and zerobytes 3", true},
+		{"prefix ff or suffix beef and zerobytes 4", false},
+		{"(prefix ff or suffix beef) and zerobytes 4", false},
+		{"prefix 00 or prefix ff and zerobytes 4", true},
+		{"(prefix 00 or prefix ff) and zerobytes 4", false},
+		// NOT binds tighter than AND
+		{"not prefix ff and suffix beef", true},
+		{"not (prefix ff or suffix beef)", false},
+		{"NOT NOT Contains 0xDEAD", true},
+		// Numeric predicates mix with the hex ones
+		{"zerobytes 3 and addr < 0x0000010000000000000000000000000000000000", true},
+		{"prefix 00 && addr>=0x0000010000000000000000000000000000000000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseMatchExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseMatchExpr(%q) error = %v", tt.expr, err)
+			}
+			if got := e.Match(addr); got != tt.expected {
+				t.Errorf("Match() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMatchExprErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"prefix",
+		"prefix xyz",
+		"zerobytes 21",
+		"zerobytes many",
+		"prefix dead and",
+		"(prefix dead",
+		"prefix dead)",
+		"prefix dead suffix beef",
+		"startswith dead",
+	} {
+		if _, err := ParseMatchExpr(expr); err == nil {
+			t.Errorf("ParseMatchExpr(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestNumericProperty(t *testing.T) {
+	// 0x...beef with a known value: 2^16 * 0x1234 + 0xbeef
+	addr, _ := MustAddressBytes("0x000000000000000000000000000000001234beef")
+	value := new(big.Int).SetBytes(addr)
+	mod97 := new(big.Int).Mod(value, big.NewInt(97)).Int64()
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"addr == 0x1234beef", true},
+		{"addr == 305446639", true},
+		{"addr != 0x1234beef", false},
+		{"addr % 97 == " + big.NewInt(mod97).String(), true},
+		{"addr % 97 == 0", mod97 == 0},
+		{"addr%97!=0", mod97 != 0},
+		{"addr % 0x10000 == 0xbeef", true},
+		{"addr % 2 == 1", true},
+		{"addr % 1 == 0", true},
+		// Ranges, inclusive and exclusive at the boundaries
+		{"addr > 0x1234beee and addr < 0x1234bef0", true},
+		{"addr >= 0x1234beef and addr <= 0x1234beef", true},
+		{"addr < 0x1234beef", false},
+		{"addr > 0x1234beef", false},
+		{"addr <= 0xffffffffffffffffffffffffffffffffffffffff", true},
+		{"not (addr < 0x1000 or addr > 0x100000000)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseNumericProperty(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseNumericProperty(%q) error = %v", tt.expr, err)
+			}
+			if got := e.Match(addr); got != tt.expected {
+				t.Errorf("Match() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	// Every residue of a wide modulus agrees with big.Int
+	mod := uint64(1<<56 - 1)
+	want := new(big.Int).Mod(value, new(big.Int).SetUint64(mod))
+	if e, err := ParseNumericProperty("addr % 72057594037927935 == " + want.String()); err != nil || !e.Match(addr) {
+		t.Errorf("addr %% 2^56-1 disagrees with big.Int (remainder %s, error %v)", want, err)
+	}
+}
+
+func TestParseNumericPropertyErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"addr",
+		"addr % 97",
+		"addr % 0 == 0",
+		"addr % 72057594037927936 == 0",
+		"addr % 97 = 0",
+		"addr == -1",
+		"addr == 0x1" + "0000000000000000000000000000000000000000",
+		"addr == dead",
+		"prefix dead",
+		"addr == 1 and zerobytes 2",
+	} {
+		if _, err := ParseNumericProperty(expr); err == nil {
+			t.Errorf("ParseNumericProperty(%q) succeeded, want error", expr)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// HDHardenedOffset is added to a child index for hardened BIP-32 derivation
+const HDHardenedOffset = 1 << 31
+
+// secp256k1Order is the curve order n as big-endian 64-bit limbs
+var secp256k1Order = [4]uint64{
+	0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFE, 0xBAAEDCE6AF48A03B, 0xBFD25E8CD0364141,
+}
+
+// MnemonicToSeed derives a BIP-39 seed: PBKDF2-HMAC-SHA512 over the mnemonic with salt
+// "mnemonic"+passphrase and 2048 iterations. Words are separated by single spaces; the
+// mnemonic is not checked against the BIP-39 word list.
+func MnemonicToSeed(mnemonic, passphrase string) []byte {
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+}
+
+// HDSaltDeriver derives 32-byte salts as the private keys of hardened BIP-32 children of a
+// master key: salt(i) is the key at m/i'. Not safe for concurrent use; Clone per worker.
+type HDSaltDeriver struct {
+	key   [32]byte // master private key
+	chain [32]byte // master chain code
+	mac   hash.Hash
+	data  [37]byte // 0x00 || key || ser32(index)
+	sum   [64]byte
+}
+
+// NewHDSaltDeriver builds the BIP-32 master key from a seed
+func NewHDSaltDeriver(seed []byte) (*HDSaltDeriver, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	i := mac.Sum(nil)
+
+	d := &HDSaltDeriver{}
+	copy(d.key[:], i[:32])
+	copy(d.chain[:], i[32:])
+	if k := toLimbs(&d.key); isZero(k) || !lessThanOrder(k) {
+		return nil, errors.New("invalid BIP-32 master key for this seed")
+	}
+	d.init()
+	return d, nil
+}
+
+func (d *HDSaltDeriver) init() {
+	d.mac = hmac.New(sha512.New, d.chain[:])
+	d.data[0] = 0x00
+	copy(d.data[1:33], d.key[:])
+}
+
+// Clone returns an independent deriver for the same master key
+func (d *HDSaltDeriver) Clone() *HDSaltDeriver {
+	c := &HDSaltDeriver{key: d.key, chain: d.chain}
+	c.init()
+	return c
+}
+
+// SaltAt writes the private key of the hardened child m/index' into out. It returns false
+// for the rare indices BIP-32 declares invalid, which callers should skip.
+func (d *HDSaltDeriver) SaltAt(index uint32, out *[32]byte) bool {
+	binary.BigEndian.PutUint32(d.data[33:], index|HDHardenedOffset)
+	d.mac.Reset()
+	d.mac.Write(d.data[:])
+	d.mac.Sum(d.sum[:0])
+
+	var il [32]byte
+	copy(il[:], d.sum[:32])
+	tweak := toLimbs(&il)
+	if !lessThanOrder(tweak) {
+		return false
+	}
+	child := addModOrder(tweak, toLimbs(&d.key))
+	if isZero(child) {
+		return false
+	}
+	for i, limb := range child {
+		binary.BigEndian.PutUint64(out[i*8:], limb)
+	}
+	return true
+}
+
+func toLimbs(b *[32]byte) [4]uint64 {
+	return [4]uint64{
+		binary.BigEndian.Uint64(b[0:]), binary.BigEndian.Uint64(b[8:]),
+		binary.BigEndian.Uint64(b[16:]), binary.BigEndian.Uint64(b[24:]),
+	}
+}
+
+func isZero(a [4]uint64) bool {
+	return a[0]|a[1]|a[2]|a[3] == 0
+}
+
+func lessThanOrder(a [4]uint64) bool {
+	for i := 0; i < 4; i++ {
+		if a[i] != secp256k1Order[i] {
+			return a[i] < secp256k1Order[i]
+		}
+	}
+	return false
+}
+
+// addModOrder returns (a + b) mod n for a, b < n
+func addModOrder(a, b [4]uint64) [4]uint64 {
+	var sum [4]uint64
+	var carry uint64
+	for i := 3; i >= 0; i-- {
+		sum[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	if carry == 0 && lessThanOrder(sum) {
+		return sum
+	}
+	var borrow uint64
+	for i := 3; i >= 0; i-- {
+		sum[i], borrow = bits.Sub64(sum[i], secp256k1Order[i], borrow)
+	}
+	return sum
+}
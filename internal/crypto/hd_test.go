@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestMnemonicToSeed(t *testing.T) {
+	// BIP-39 test vector with passphrase "TREZOR"
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+
+	if got := hex.EncodeToString(MnemonicToSeed(mnemonic, "TREZOR")); got != want {
+		t.Errorf("MnemonicToSeed() = %s, want %s", got, want)
+	}
+	// Extra whitespace, e.g. from a wrapped file, must not change the seed
+	if got := hex.EncodeToString(MnemonicToSeed("  abandon abandon abandon abandon abandon abandon\nabandon abandon abandon abandon abandon about\n", "TREZOR")); got != want {
+		t.Errorf("MnemonicToSeed() with extra whitespace = %s, want %s", got, want)
+	}
+}
+
+func TestHDSaltDeriver(t *testing.T) {
+	// BIP-32 test vector 1: the private key at m/0H
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	d, err := NewHDSaltDeriver(seed)
+	if err != nil {
+		t.Fatalf("NewHDSaltDeriver() error = %v", err)
+	}
+	if got := hex.EncodeToString(d.key[:]); got != "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35" {
+		t.Errorf("master key = %s", got)
+	}
+
+	var salt [32]byte
+	if !d.SaltAt(0, &salt) {
+		t.Fatal("SaltAt(0) reported an invalid child")
+	}
+	if got := hex.EncodeToString(salt[:]); got != "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea" {
+		t.Errorf("SaltAt(0) = %s, want the BIP-32 m/0H key", got)
+	}
+
+	// Derivation is deterministic across clones and independent of call order
+	clone := d.Clone()
+	var a, b [32]byte
+	clone.SaltAt(7, &a)
+	d.SaltAt(3, &b)
+	d.SaltAt(7, &b)
+	if a != b {
+		t.Errorf("SaltAt(7) differs between clones: %x vs %x", a, b)
+	}
+	d.SaltAt(8, &b)
+	if a == b {
+		t.Error("SaltAt(7) and SaltAt(8) should differ")
+	}
+}
+
+func TestAddModOrder(t *testing.T) {
+	nMinus1 := secp256k1Order
+	nMinus1[3]--
+	if got := addModOrder(nMinus1, [4]uint64{0, 0, 0, 1}); !isZero(got) {
+		t.Errorf("(n-1) + 1 mod n = %x, want 0", got)
+	}
+	if got := addModOrder(nMinus1, [4]uint64{0, 0, 0, 5}); got != [4]uint64{0, 0, 0, 4} {
+		t.Errorf("(n-1) + 5 mod n = %x, want 4", got)
+	}
+}
@@ -0,0 +1,283 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/bits"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak backends selectable with --keccak-backend
+const (
+	KeccakXCrypto    = "x-crypto"   // golang.org/x/crypto/sha3 (assembly permutation on amd64)
+	KeccakGeneric    = "generic"    // in-repo pure-Go sponge specialised for short inputs
+	KeccakGoEthereum = "goethereum" // go-ethereum's keccak; not linked into this module
+	KeccakAuto       = "auto"       // micro-benchmark the available backends at startup
+
+	DefaultKeccakBackend = KeccakXCrypto
+)
+
+// keccakBackends maps each available backend to its hasher constructor
+var keccakBackends = map[string]func() hash.Hash{
+	KeccakXCrypto: sha3.NewLegacyKeccak256,
+	KeccakGeneric: newGenericKeccak256,
+}
+
+// KeccakBackends returns the names of the backends compiled into this binary, sorted.
+func KeccakBackends() []string {
+	names := make([]string, 0, len(keccakBackends))
+	for name := range keccakBackends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeccakHasherFactory returns the constructor for a named backend. "auto" is not resolved
+// here; call FastestKeccakBackend first.
+func KeccakHasherFactory(name string) (func() hash.Hash, error) {
+	if name == "" {
+		name = DefaultKeccakBackend
+	}
+	if f, ok := keccakBackends[name]; ok {
+		return f, nil
+	}
+	if name == KeccakGoEthereum {
+		return nil, fmt.Errorf("keccak backend %s is not available: this build does not link go-ethereum", name)
+	}
+	return nil, fmt.Errorf("unknown keccak backend %q (available: %v or %s)", name, KeccakBackends(), KeccakAuto)
+}
+
+// FastestKeccakBackend hashes CREATE2-sized inputs with each available backend for roughly
+// budget and returns the name of the one with the highest throughput. With incremental the
+// generic backend is timed through a Create2Sponge, as the workers then run it.
+func FastestKeccakBackend(budget time.Duration, incremental bool) string {
+	names := KeccakBackends()
+	per := budget / time.Duration(len(names))
+
+	var input [Create2InputLen]byte
+	var out [32]byte
+	best, bestRate := DefaultKeccakBackend, 0.0
+	for _, name := range names {
+		h := keccakBackends[name]()
+		hashOnce := func() {
+			h.Reset()
+			h.Write(input[:])
+			h.Sum(out[:0])
+		}
+		if incremental && name == KeccakGeneric {
+			sponge := NewCreate2Sponge(input[:Create2PrefixLen])
+			var salt [32]byte
+			hashOnce = func() {
+				salt[0] = input[Create2PrefixLen]
+				sponge.AddressInto(&salt, input[Create2PrefixLen+Create2SaltLen:], out[:20])
+			}
+		}
+		n := 0
+		start := time.Now()
+		for time.Since(start) < per {
+			for i := 0; i < 256; i++ {
+				hashOnce()
+				input[Create2PrefixLen] = byte(n)
+				n++
+			}
+		}
+		if rate := float64(n) / time.Since(start).Seconds(); rate > bestRate {
+			best, bestRate = name, rate
+		}
+	}
+	return best
+}
+
+// keccak256Rate is the sponge rate in bytes for Keccak-256 (1600 - 2*256 bits)
+const keccak256Rate = 136
+
+// genericKeccak is a pure-Go Keccak-256 (pre-NIST padding) implementing hash.Hash.
+// Sum does not allocate when given a buffer with room for 32 bytes.
+type genericKeccak struct {
+	state [25]uint64
+	buf   [keccak256Rate]byte
+	n     int // bytes buffered in buf
+}
+
+func newGenericKeccak256() hash.Hash {
+	return &genericKeccak{}
+}
+
+func (k *genericKeccak) Size() int      { return 32 }
+func (k *genericKeccak) BlockSize() int { return keccak256Rate }
+
+func (k *genericKeccak) Reset() {
+	k.state = [25]uint64{}
+	k.n = 0
+}
+
+func (k *genericKeccak) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		c := copy(k.buf[k.n:], p)
+		k.n += c
+		p = p[c:]
+		if k.n == keccak256Rate {
+			absorb(&k.state, &k.buf)
+			k.n = 0
+		}
+	}
+	return written, nil
+}
+
+func (k *genericKeccak) Sum(b []byte) []byte {
+	// Pad a copy so the hasher can keep absorbing after Sum
+	state := k.state
+	var block [keccak256Rate]byte
+	copy(block[:], k.buf[:k.n])
+	block[k.n] ^= 0x01
+	block[keccak256Rate-1] ^= 0x80
+	absorb(&state, &block)
+
+	var out [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], state[i])
+	}
+	return append(b, out[:]...)
+}
+
+// absorb xors one rate-sized block into the state and permutes it
+func absorb(state *[25]uint64, block *[keccak256Rate]byte) {
+	for i := 0; i < keccak256Rate/8; i++ {
+		state[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+	keccakF1600(state)
+}
+
+// Create2Sponge is a Keccak-256 state that has absorbed the constant 0xff || factory prefix of
+// a CREATE2 preimage. Each address clones the state by value, absorbs the salt and init code
+// hash and finalizes, so the prefix is never absorbed again. The 85-byte preimage fits in one
+// block: absorbing is an xor into the lanes and finalizing is a single permutation.
+type Create2Sponge struct {
+	state [25]uint64
+}
+
+// NewCreate2Sponge absorbs the 21-byte CREATE2 prefix (0xff + factory)
+func NewCreate2Sponge(prefix []byte) *Create2Sponge {
+	s := &Create2Sponge{}
+	for i, b := range prefix[:Create2PrefixLen] {
+		s.state[i/8] ^= uint64(b) << (8 * (i % 8))
+	}
+	return s
+}
+
+// AddressInto writes the 20-byte CREATE2 address of salt and initCodeHash into addr.
+// It does not allocate and leaves the sponge ready for the next salt.
+func (s *Create2Sponge) AddressInto(salt *[32]byte, initCodeHash, addr []byte) {
+	state := s.state
+
+	// Bytes 16 through 87 of the block: the tail of the prefix lane, salt, init code hash
+	// and the first padding byte, xored a lane at a time
+	var block [72]byte
+	copy(block[Create2PrefixLen-16:], salt[:])
+	copy(block[Create2PrefixLen-16+Create2SaltLen:], initCodeHash[:Create2SuffixLen])
+	block[Create2InputLen-16] = 0x01
+	for i := 0; i < len(block)/8; i++ {
+		state[2+i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+	state[keccak256Rate/8-1] ^= 0x80 << 56
+	keccakF1600(&state)
+
+	// The address is bytes 12 through 31 of the digest
+	binary.LittleEndian.PutUint32(addr[0:4], uint32(state[1]>>32))
+	binary.LittleEndian.PutUint64(addr[4:12], state[2])
+	binary.LittleEndian.PutUint64(addr[12:20], state[3])
+}
+
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakF1600 applies the 24-round Keccak-f[1600] permutation. Lanes are held in locals
+// and each round is written out, so rotations compile to constant shifts.
+func keccakF1600(a *[25]uint64) {
+	a00, a01, a02, a03, a04, a05, a06, a07, a08, a09, a10, a11, a12 := a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8], a[9], a[10], a[11], a[12]
+	a13, a14, a15, a16, a17, a18, a19, a20, a21, a22, a23, a24 := a[13], a[14], a[15], a[16], a[17], a[18], a[19], a[20], a[21], a[22], a[23], a[24]
+	for round := 0; round < 24; round++ {
+		// Theta
+		c0 := a00 ^ a05 ^ a10 ^ a15 ^ a20
+		c1 := a01 ^ a06 ^ a11 ^ a16 ^ a21
+		c2 := a02 ^ a07 ^ a12 ^ a17 ^ a22
+		c3 := a03 ^ a08 ^ a13 ^ a18 ^ a23
+		c4 := a04 ^ a09 ^ a14 ^ a19 ^ a24
+		d0 := c4 ^ bits.RotateLeft64(c1, 1)
+		d1 := c0 ^ bits.RotateLeft64(c2, 1)
+		d2 := c1 ^ bits.RotateLeft64(c3, 1)
+		d3 := c2 ^ bits.RotateLeft64(c4, 1)
+		d4 := c3 ^ bits.RotateLeft64(c0, 1)
+
+		// Rho and pi: lane (x, y) moves to (y, 2x+3y)
+		b00 := a00 ^ d0
+		b01 := bits.RotateLeft64(a06^d1, 44)
+		b02 := bits.RotateLeft64(a12^d2, 43)
+		b03 := bits.RotateLeft64(a18^d3, 21)
+		b04 := bits.RotateLeft64(a24^d4, 14)
+		b05 := bits.RotateLeft64(a03^d3, 28)
+		b06 := bits.RotateLeft64(a09^d4, 20)
+		b07 := bits.RotateLeft64(a10^d0, 3)
+		b08 := bits.RotateLeft64(a16^d1, 45)
+		b09 := bits.RotateLeft64(a22^d2, 61)
+		b10 := bits.RotateLeft64(a01^d1, 1)
+		b11 := bits.RotateLeft64(a07^d2, 6)
+		b12 := bits.RotateLeft64(a13^d3, 25)
+		b13 := bits.RotateLeft64(a19^d4, 8)
+		b14 := bits.RotateLeft64(a20^d0, 18)
+		b15 := bits.RotateLeft64(a04^d4, 27)
+		b16 := bits.RotateLeft64(a05^d0, 36)
+		b17 := bits.RotateLeft64(a11^d1, 10)
+		b18 := bits.RotateLeft64(a17^d2, 15)
+		b19 := bits.RotateLeft64(a23^d3, 56)
+		b20 := bits.RotateLeft64(a02^d2, 62)
+		b21 := bits.RotateLeft64(a08^d3, 55)
+		b22 := bits.RotateLeft64(a14^d4, 39)
+		b23 := bits.RotateLeft64(a15^d0, 41)
+		b24 := bits.RotateLeft64(a21^d1, 2)
+
+		// Chi
+		a00 = b00 ^ (^b01 & b02)
+		a01 = b01 ^ (^b02 & b03)
+		a02 = b02 ^ (^b03 & b04)
+		a03 = b03 ^ (^b04 & b00)
+		a04 = b04 ^ (^b00 & b01)
+		a05 = b05 ^ (^b06 & b07)
+		a06 = b06 ^ (^b07 & b08)
+		a07 = b07 ^ (^b08 & b09)
+		a08 = b08 ^ (^b09 & b05)
+		a09 = b09 ^ (^b05 & b06)
+		a10 = b10 ^ (^b11 & b12)
+		a11 = b11 ^ (^b12 & b13)
+		a12 = b12 ^ (^b13 & b14)
+		a13 = b13 ^ (^b14 & b10)
+		a14 = b14 ^ (^b10 & b11)
+		a15 = b15 ^ (^b16 & b17)
+		a16 = b16 ^ (^b17 & b18)
+		a17 = b17 ^ (^b18 & b19)
+		a18 = b18 ^ (^b19 & b15)
+		a19 = b19 ^ (^b15 & b16)
+		a20 = b20 ^ (^b21 & b22)
+		a21 = b21 ^ (^b22 & b23)
+		a22 = b22 ^ (^b23 & b24)
+		a23 = b23 ^ (^b24 & b20)
+		a24 = b24 ^ (^b20 & b21)
+
+		// Iota
+		a00 ^= keccakRoundConstants[round]
+	}
+	a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8], a[9], a[10], a[11], a[12] = a00, a01, a02, a03, a04, a05, a06, a07, a08, a09, a10, a11, a12
+	a[13], a[14], a[15], a[16], a[17], a[18], a[19], a[20], a[21], a[22], a[23], a[24] = a13, a14, a15, a16, a17, a18, a19, a20, a21, a22, a23, a24
+}
@@ -0,0 +1,105 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestKeccakBackendsAgree(t *testing.T) {
+	// Empty-input Keccak-256, the well-known Ethereum constant
+	empty := "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+
+	for _, name := range KeccakBackends() {
+		newHasher, err := KeccakHasherFactory(name)
+		if err != nil {
+			t.Fatalf("KeccakHasherFactory(%s) error = %v", name, err)
+		}
+		h := newHasher()
+		if got := hex.EncodeToString(h.Sum(nil)); got != empty {
+			t.Errorf("%s: keccak256(\"\") = %s, want %s", name, got, empty)
+		}
+
+		// Lengths around the 136-byte rate exercise padding and multi-block absorption
+		for _, n := range []int{1, 32, 64, Create2InputLen, 135, 136, 137, 272, 500} {
+			input := make([]byte, n)
+			for i := range input {
+				input[i] = byte(i * 7)
+			}
+			h.Reset()
+			h.Write(input[:n/3])
+			h.Write(input[n/3:])
+			if got, want := h.Sum(nil), keccak256Bytes(input); !bytes.Equal(got, want) {
+				t.Errorf("%s: keccak256(%d bytes) = %x, want %x", name, n, got, want)
+			}
+		}
+	}
+}
+
+func TestCreate2Sponge(t *testing.T) {
+	for _, factory := range []string{FactoryAddress, ArachnidFactoryAddress, CreateXAddress} {
+		factoryBytes, _ := MustAddressBytes(factory)
+		prefix := Create2PrefixFor(factoryBytes)
+		sponge := NewCreate2Sponge(prefix[:])
+		for i := 0; i < 64; i++ {
+			var salt [32]byte
+			for j := range salt {
+				salt[j] = byte(i*31 + j*7)
+			}
+			initCodeHash := keccak256Bytes([]byte{byte(i)})
+
+			var addr [20]byte
+			sponge.AddressInto(&salt, initCodeHash, addr[:])
+			want := CalculateCreate2AddressFor(factoryBytes, initCodeHash, salt[:])
+			if got := AddressBytesToChecksumString(addr[:]); got != want {
+				t.Fatalf("%s: salt %x gives %s, want %s", factory, salt, got, want)
+			}
+		}
+	}
+}
+
+func TestKeccakHasherFactoryErrors(t *testing.T) {
+	for _, name := range []string{KeccakGoEthereum, "avx512"} {
+		if _, err := KeccakHasherFactory(name); err == nil {
+			t.Errorf("KeccakHasherFactory(%s) expected error", name)
+		}
+	}
+	for _, incremental := range []bool{false, true} {
+		if got := FastestKeccakBackend(10*time.Millisecond, incremental); got != KeccakXCrypto && got != KeccakGeneric {
+			t.Errorf("FastestKeccakBackend(%v) = %s, want an available backend", incremental, got)
+		}
+	}
+}
+
+func BenchmarkKeccakBackends(b *testing.B) {
+	for _, name := range KeccakBackends() {
+		newHasher, _ := KeccakHasherFactory(name)
+		b.Run(name, func(b *testing.B) {
+			h := newHasher()
+			var input [Create2InputLen]byte
+			var hashBuf [32]byte
+			var addr [20]byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				input[Create2PrefixLen] = byte(i)
+				Create2AddressInto(h, input[:], hashBuf[:], addr[:])
+			}
+		})
+	}
+}
+
+// BenchmarkCreate2Sponge measures the cloned prefix state against hashing the full preimage
+// with each backend, as BenchmarkKeccakBackends does
+func BenchmarkCreate2Sponge(b *testing.B) {
+	prefix := Create2PrefixBytes()
+	sponge := NewCreate2Sponge(prefix[:])
+	var salt [32]byte
+	var initCodeHash [32]byte
+	var addr [20]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		salt[0] = byte(i)
+		sponge.AddressInto(&salt, initCodeHash[:], addr[:])
+	}
+}
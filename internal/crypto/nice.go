@@ -0,0 +1,120 @@
+package crypto
+
+// NiceSet is a curated family of appealing addresses, selected with --nice-set so a casual
+// user need not write a pattern. Score rates how well a raw address fits the set, higher is
+// better; an address scoring at least Threshold is a match.
+type NiceSet struct {
+	Name        string
+	Description string // what a match looks like, for help and logs
+	Threshold   int
+	Score       func(addr []byte) int
+}
+
+// niceSets are the built-in --nice-set families. Each threshold needs around 10^7 attempts
+// to reach, a few seconds to minutes on one machine.
+var niceSets = []NiceSet{
+	{
+		Name:        "eights",
+		Description: "a run of at least 7 eights, e.g. 0x...88888888...",
+		Threshold:   7,
+		Score:       func(addr []byte) int { return LongestNibbleRun(addr, 8) },
+	},
+	{
+		Name:        "counting",
+		Description: "at least 8 hex characters counting up, e.g. 0x...12345678...",
+		Threshold:   8,
+		Score:       LongestAscendingRunBytes,
+	},
+	{
+		Name:        "bookends",
+		Description: "at least 4 repeated characters at both ends, e.g. 0xaaaa...7777",
+		Threshold:   4,
+		Score: func(addr []byte) int {
+			leading, trailing := EdgeRuns(addr)
+			return min(leading, trailing)
+		},
+	},
+	{
+		Name:        "mirror",
+		Description: "the first 6 characters mirrored by the last 6, e.g. 0xabc123...321cba",
+		Threshold:   6,
+		Score:       MirrorDepth,
+	},
+}
+
+// NiceSets returns the built-in --nice-set families
+func NiceSets() []NiceSet {
+	return append([]NiceSet(nil), niceSets...)
+}
+
+// NiceSetNames returns the names --nice-set accepts
+func NiceSetNames() []string {
+	names := make([]string, len(niceSets))
+	for i, s := range niceSets {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// LookupNiceSet returns the built-in set called name
+func LookupNiceSet(name string) (*NiceSet, bool) {
+	for i := range niceSets {
+		if niceSets[i].Name == name {
+			return &niceSets[i], true
+		}
+	}
+	return nil, false
+}
+
+// LongestNibbleRun returns the length of the longest run of the hex character nibble in a raw
+// address
+func LongestNibbleRun(addr []byte, nibble byte) int {
+	longest, run := 0, 0
+	for _, b := range addr {
+		for _, n := range [2]byte{b >> 4, b & 0x0f} {
+			if n == nibble {
+				run++
+				longest = max(longest, run)
+			} else {
+				run = 0
+			}
+		}
+	}
+	return longest
+}
+
+// EdgeRuns returns how many identical hex characters a raw address starts with and how many
+// it ends with
+func EdgeRuns(addr []byte) (leading, trailing int) {
+	n := 2 * len(addr)
+	nibble := func(i int) byte {
+		if i%2 == 0 {
+			return addr[i/2] >> 4
+		}
+		return addr[i/2] & 0x0f
+	}
+	for leading < n && nibble(leading) == nibble(0) {
+		leading++
+	}
+	for trailing < n && nibble(n-1-trailing) == nibble(n-1) {
+		trailing++
+	}
+	return leading, trailing
+}
+
+// MirrorDepth counts the hex characters from the start of a raw address that mirror those at
+// its end, working inwards: 20 for a full palindrome (see IsPalindromeBytes)
+func MirrorDepth(addr []byte) int {
+	depth := 0
+	for i, j := 0, len(addr)-1; i <= j; i, j = i+1, j-1 {
+		if addr[i]>>4 != addr[j]&0x0f {
+			return depth
+		}
+		depth++
+		if addr[i]&0x0f != addr[j]>>4 {
+			return depth
+		}
+		depth++
+	}
+	return depth
+}
@@ -0,0 +1,162 @@
+package crypto
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// IsPalindrome reports whether the hex of an address reads the same forwards and backwards.
+// Characters are compared exactly, so an EIP-55 checksummed address must mirror its casing
+// too; pass the lowercase address to ignore the checksum.
+func IsPalindrome(address string) bool {
+	h := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+		if h[i] != h[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// LongestRun returns the length of the longest run of identical hex characters in an
+// address, ignoring case
+func LongestRun(address string) int {
+	h := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	longest, run := 0, 0
+	for i := 0; i < len(h); i++ {
+		if i > 0 && h[i] == h[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+// LongestAscendingRun returns the length of the longest run of hex characters counting up by
+// one, such as 0123456789abcdef, ignoring case. There is no wrap from f back to 0.
+func LongestAscendingRun(address string) int {
+	h := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	longest, run, prev := 0, 0, -1
+	for i := 0; i < len(h); i++ {
+		d := strings.IndexByte(hexDigits, h[i])
+		if d > 0 && d == prev+1 {
+			run++
+		} else {
+			run = 1
+		}
+		prev = d
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+const hexDigits = "0123456789abcdef"
+
+// IsAllSameNibble reports whether every hex character of an address is the same, ignoring case
+func IsAllSameNibble(address string) bool {
+	h := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	return h != "" && strings.Count(h, h[:1]) == len(h)
+}
+
+// NibbleHistogram counts how often each hex character 0-f occurs in an address, ignoring case
+func NibbleHistogram(address string) [16]int {
+	var counts [16]int
+	h := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X"))
+	for i := 0; i < len(h); i++ {
+		if d := strings.IndexByte(hexDigits, h[i]); d >= 0 {
+			counts[d]++
+		}
+	}
+	return counts
+}
+
+// IsPalindromeBytes is IsPalindrome over the nibbles of a raw address, ignoring the checksum
+func IsPalindromeBytes(addr []byte) bool {
+	for i, j := 0, len(addr)-1; i <= j; i, j = i+1, j-1 {
+		if addr[i]>>4 != addr[j]&0x0f || addr[i]&0x0f != addr[j]>>4 {
+			return false
+		}
+	}
+	return true
+}
+
+// LongestRunBytes is LongestRun over the nibbles of a raw address
+func LongestRunBytes(addr []byte) int {
+	longest, run := 0, 0
+	prev := byte(0xff)
+	for _, b := range addr {
+		for _, n := range [2]byte{b >> 4, b & 0x0f} {
+			if n == prev {
+				run++
+			} else {
+				run = 1
+				prev = n
+			}
+			longest = max(longest, run)
+		}
+	}
+	return longest
+}
+
+// TrailingZeroBits counts the low zero bits of an address read as a big-endian integer,
+// i.e. the largest N for which it is divisible by 2^N
+func TrailingZeroBits(addr []byte) int {
+	n := 0
+	for i := len(addr) - 1; i >= 0; i-- {
+		if addr[i] != 0 {
+			return n + bits.TrailingZeros8(addr[i])
+		}
+		n += 8
+	}
+	return n
+}
+
+// MatchPrefixBits reports whether the top bits bits of a raw address equal the top bits bits of
+// pattern, which must be at least (bits+7)/8 bytes long. Unlike hex prefixes, bits need not be a
+// multiple of 4.
+func MatchPrefixBits(addr20 []byte, pattern []byte, bits int) bool {
+	full := bits / 8
+	for i := 0; i < full; i++ {
+		if addr20[i] != pattern[i] {
+			return false
+		}
+	}
+	if rem := bits % 8; rem != 0 {
+		mask := byte(0xff) << (8 - rem)
+		return addr20[full]&mask == pattern[full]&mask
+	}
+	return true
+}
+
+// LongestAscendingRunBytes is LongestAscendingRun over the nibbles of a raw address
+func LongestAscendingRunBytes(addr []byte) int {
+	longest, run := 0, 0
+	prev := byte(0xff)
+	for _, b := range addr {
+		for _, n := range [2]byte{b >> 4, b & 0x0f} {
+			if n == prev+1 {
+				run++
+			} else {
+				run = 1
+			}
+			prev = n
+			longest = max(longest, run)
+		}
+	}
+	return longest
+}
+
+// IsAllSameNibbleBytes is IsAllSameNibble over a raw address
+func IsAllSameNibbleBytes(addr []byte) bool {
+	if len(addr) == 0 || addr[0]>>4 != addr[0]&0x0f {
+		return false
+	}
+	for _, b := range addr[1:] {
+		if b != addr[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestIsPalindrome(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected bool
+	}{
+		{"lowercase palindrome", "0x0123456789abcdef01100110fedcba9876543210", true},
+		{"not a palindrome", "0x0123456789abcdef01100110fedcba9876543211", false},
+		{"all zero", "0x0000000000000000000000000000000000000000", true},
+		{"casing must mirror", "0xAbcdef0123456789abcddcba9876543210fedcba", false},
+		{"mirrored casing", "0xAbcdef0123456789abcddcba9876543210fedcbA", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPalindrome(tt.address); got != tt.expected {
+				t.Errorf("IsPalindrome(%s) = %v, want %v", tt.address, got, tt.expected)
+			}
+
+			// The byte form ignores casing, so it agrees with the lowercase string form
+			lower := strings.ToLower(tt.address)
+			addr, _ := hex.DecodeString(lower[2:])
+			if got, want := IsPalindromeBytes(addr), IsPalindrome(lower); got != want {
+				t.Errorf("IsPalindromeBytes(%s) = %v, want %v", tt.address, got, want)
+			}
+		})
+	}
+}
+
+func TestLongestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected int
+	}{
+		{"no repeats", "0x0123456789abcdef0123456789abcdef01234567", 1},
+		{"run at start", "0x0000123456789abcdef0123456789abcdef01234", 4},
+		{"run across a byte boundary", "0x1233334567890abcdef1234567890abcdef12345", 4},
+		{"run at end ignores case", "0x0123456789abcdef0123456789abcdef012aaAAA", 5},
+		{"all the same", "0x0000000000000000000000000000000000000000", 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LongestRun(tt.address); got != tt.expected {
+				t.Errorf("LongestRun(%s) = %d, want %d", tt.address, got, tt.expected)
+			}
+			addr, _ := hex.DecodeString(strings.ToLower(tt.address[2:]))
+			if got := LongestRunBytes(addr); got != tt.expected {
+				t.Errorf("LongestRunBytes(%s) = %d, want %d", tt.address, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNiceSets(t *testing.T) {
+	tests := []struct {
+		set     string
+		address string
+		score   int
+		match   bool
+	}{
+		{"eights", "0x01238888888456790abcdef0123456789abcdef0", 7, true},
+		{"eights", "0x0123888888456790abcdef0123456789abcdef01", 6, false},
+		{"counting", "0x0a12345678bcdef0123456fedcba9876543210ff", 8, true},
+		{"counting", "0x0a1234567bbcdef0123456fedcba9876543210ff", 7, false},
+		{"bookends", "0xaaaa0123456789abcdef0123456789abcdef7777", 4, true},
+		{"bookends", "0xaaaa0123456789abcdef0123456789abcdef0777", 3, false},
+		{"mirror", "0xabc1230123456789abcdef0123456789ab321cba", 6, true},
+		{"mirror", "0xabc1230123456789abcdef0123456789ab421cba", 5, false},
+		{"mirror", "0xabc1230000000000000000000000000000321cba", 20, true},
+	}
+
+	for _, tt := range tests {
+		set, ok := LookupNiceSet(tt.set)
+		if !ok {
+			t.Fatalf("LookupNiceSet(%q) found no set", tt.set)
+		}
+		addr, _ := hex.DecodeString(tt.address[2:])
+		score := set.Score(addr)
+		if score != tt.score || (score >= set.Threshold) != tt.match {
+			t.Errorf("%s: score(%s) = %d (threshold %d), want %d, match %v", tt.set, tt.address, score, set.Threshold, tt.score, tt.match)
+		}
+	}
+
+	if _, ok := LookupNiceSet("sevens"); ok {
+		t.Error("LookupNiceSet(sevens) found a set")
+	}
+	if got := len(NiceSetNames()); got != len(NiceSets()) {
+		t.Errorf("NiceSetNames() has %d names for %d sets", got, len(NiceSets()))
+	}
+}
+
+func TestTrailingZeroBits(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected int
+	}{
+		{"0x0123456789abcdef0123456789abcdef01234567", 0},
+		{"0x0123456789abcdef0123456789abcdef01234570", 4},
+		{"0x0123456789abcdef0123456789abcdef01234500", 8},
+		{"0x0123456789abcdef0123456789abcdef01234800", 11},
+		{"0x0123456789abcdef0123456789abcdef01000000", 24},
+		{"0x8000000000000000000000000000000000000000", 159},
+		{"0x0000000000000000000000000000000000000000", 160},
+	}
+
+	for _, tt := range tests {
+		addr, _ := hex.DecodeString(tt.address[2:])
+		got := TrailingZeroBits(addr)
+		if got != tt.expected {
+			t.Errorf("TrailingZeroBits(%s) = %d, want %d", tt.address, got, tt.expected)
+		}
+		// big.Int agrees for any non-zero address
+		if n := new(big.Int).SetBytes(addr); n.Sign() != 0 && n.TrailingZeroBits() != uint(got) {
+			t.Errorf("TrailingZeroBits(%s) = %d, big.Int says %d", tt.address, got, n.TrailingZeroBits())
+		}
+	}
+}
+
+func TestMatchPrefixBits(t *testing.T) {
+	tests := []struct {
+		address string
+		pattern string
+		bits    int
+		want    bool
+	}{
+		{"0xfc23456789abcdef0123456789abcdef01234567", "fc", 6, true},
+		{"0xff23456789abcdef0123456789abcdef01234567", "fc", 6, true},
+		{"0xf823456789abcdef0123456789abcdef01234567", "fc", 6, false},
+		{"0x1f23456789abcdef0123456789abcdef01234567", "00", 3, true},
+		{"0x2023456789abcdef0123456789abcdef01234567", "00", 3, false},
+		{"0xdead56789abcdef0123456789abcdef012345678", "dea8", 13, true},
+		{"0xdeaf56789abcdef0123456789abcdef012345678", "dea8", 13, true},
+		{"0xdeb056789abcdef0123456789abcdef012345678", "dea8", 13, false},
+		{"0xdead56789abcdef0123456789abcdef012345678", "dead", 16, true},
+		{"0xdeac56789abcdef0123456789abcdef012345678", "dead", 16, false},
+	}
+
+	for _, tt := range tests {
+		addr, _ := hex.DecodeString(tt.address[2:])
+		pattern, _ := hex.DecodeString(tt.pattern)
+		if got := MatchPrefixBits(addr, pattern, tt.bits); got != tt.want {
+			t.Errorf("MatchPrefixBits(%s, %s, %d) = %v, want %v", tt.address, tt.pattern, tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestLongestAscendingRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected int
+	}{
+		{"no steps", "0x0000000000000000000000000000000000000000", 1},
+		{"full run ignores case", "0x0123456789ABCDEF000000000000000000000000", 16},
+		{"run across a byte boundary", "0xffff3456ffffffffffffffffffffffffffffffff", 4},
+		{"no wrap from f to 0", "0xef01ffffffffffffffffffffffffffffffffffff", 2},
+		{"descending does not count", "0x9876543210999999999999999999999999999999", 1},
+		{"run at the end", "0x00000000000000000000000000000000006789ab", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LongestAscendingRun(tt.address); got != tt.expected {
+				t.Errorf("LongestAscendingRun(%s) = %d, want %d", tt.address, got, tt.expected)
+			}
+			addr, _ := hex.DecodeString(strings.ToLower(tt.address[2:]))
+			if got := LongestAscendingRunBytes(addr); got != tt.expected {
+				t.Errorf("LongestAscendingRunBytes(%s) = %d, want %d", tt.address, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsAllSameNibble(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected bool
+	}{
+		{"0x0000000000000000000000000000000000000000", true},
+		{"0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", true},
+		{"0xaaaaaaaaaaaaaaaaaaaaAAAAAAAAAAAAAAAAAAAA", true},
+		{"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab", false},
+		{"0xbaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", false},
+		{"0xabababababababababababababababababababab", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAllSameNibble(tt.address); got != tt.expected {
+			t.Errorf("IsAllSameNibble(%s) = %v, want %v", tt.address, got, tt.expected)
+		}
+		addr, _ := hex.DecodeString(strings.ToLower(tt.address[2:]))
+		if got := IsAllSameNibbleBytes(addr); got != tt.expected {
+			t.Errorf("IsAllSameNibbleBytes(%s) = %v, want %v", tt.address, got, tt.expected)
+		}
+	}
+}
+
+func TestNibbleHistogram(t *testing.T) {
+	// The ERC-2470 singleton factory: no 1 or 7, and eleven zeros
+	got := NibbleHistogram("0xce0042B868300000d44A59004Da54A005ffdcf9f")
+	want := [16]int{0: 11, 2: 1, 3: 1, 4: 5, 5: 3, 6: 1, 8: 2, 9: 2, 0xa: 3, 0xb: 1, 0xc: 2, 0xd: 3, 0xe: 1, 0xf: 4}
+	if got != want {
+		t.Errorf("NibbleHistogram() = %v, want %v", got, want)
+	}
+	total := 0
+	for _, n := range got {
+		total += n
+	}
+	if total != 40 {
+		t.Errorf("histogram counts %d characters, want 40", total)
+	}
+}
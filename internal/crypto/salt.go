@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Errors returned when parsing salts
+var (
+	ErrInvalidSaltFormat  = errors.New("salt format must be hex or decimal")
+	ErrInvalidSaltHex     = errors.New("salt is not valid hex")
+	ErrInvalidSaltDecimal = errors.New("salt is not a non-negative decimal integer")
+	ErrInvalidSaltLength  = errors.New("salt must be at most 32 bytes")
+)
+
+// SaltFormat selects how a salt string is interpreted
+type SaltFormat int
+
+const (
+	SaltFormatHex SaltFormat = iota
+	SaltFormatDecimal
+)
+
+// ParseSaltFormat parses a --salt-input-format value
+func ParseSaltFormat(s string) (SaltFormat, error) {
+	switch s {
+	case "", "hex":
+		return SaltFormatHex, nil
+	case "decimal":
+		return SaltFormatDecimal, nil
+	default:
+		return SaltFormatHex, fmt.Errorf("%w (got %q)", ErrInvalidSaltFormat, s)
+	}
+}
+
+// ParseSalt parses a salt in the given format into its 32-byte big-endian form.
+// There is no guessing: "255" is 0x0255 as hex and 0xff as decimal.
+func ParseSalt(salt string, format SaltFormat) ([32]byte, error) {
+	if format != SaltFormatDecimal {
+		return ParseHexSalt(salt)
+	}
+
+	var out [32]byte
+	n, ok := new(big.Int).SetString(strings.TrimSpace(salt), 10)
+	if !ok || n.Sign() < 0 {
+		return out, fmt.Errorf("%w (got %q)", ErrInvalidSaltDecimal, salt)
+	}
+	if n.BitLen() > 256 {
+		return out, fmt.Errorf("%w (decimal %s)", ErrInvalidSaltLength, salt)
+	}
+	n.FillBytes(out[:])
+	return out, nil
+}
+
+// normalizeSalt converts a user-supplied salt string into the 32-byte CREATE2 salt.
+// Hex input (with or without 0x, odd lengths allowed) is left-padded to 32 bytes; input longer
+// than 32 bytes is rejected rather than silently truncated. Non-hex input is hashed with keccak256.
+func normalizeSalt(salt string) ([32]byte, error) {
+	if _, err := decodeSaltHex(salt); err != nil {
+		var out [32]byte
+		copy(out[:], keccak256Bytes([]byte(salt)))
+		return out, nil
+	}
+	return ParseHexSalt(salt)
+}
+
+// ParseHexSalt strictly parses a hex salt (with or without 0x, odd lengths allowed) of at most
+// 32 bytes, left-padding it to 32 bytes.
+func ParseHexSalt(salt string) ([32]byte, error) {
+	var out [32]byte
+	b, err := decodeSaltHex(salt)
+	if err != nil {
+		return out, fmt.Errorf("%w (%v)", ErrInvalidSaltHex, err)
+	}
+	if len(b) > 32 {
+		return out, fmt.Errorf("%w (got %d)", ErrInvalidSaltLength, len(b))
+	}
+	copy(out[32-len(b):], b)
+	return out, nil
+}
+
+// decodeSaltHex decodes a salt hex string, tolerating a 0x prefix and odd length
+func decodeSaltHex(salt string) ([]byte, error) {
+	h := strings.TrimSpace(salt)
+	if len(h) >= 2 && (h[0:2] == "0x" || h[0:2] == "0X") {
+		h = h[2:]
+	}
+	if len(h)%2 != 0 {
+		h = "0" + h
+	}
+	return hex.DecodeString(h)
+}
+
+// AddToSalt adds n to the salt interpreted as a 256-bit big-endian integer, wrapping on overflow.
+func AddToSalt(salt *[32]byte, n uint64) {
+	for i := 31; i >= 0 && n > 0; i-- {
+		sum := uint64(salt[i]) + (n & 0xff)
+		salt[i] = byte(sum)
+		n = (n >> 8) + (sum >> 8)
+	}
+}
+
+// FormatSalt renders a salt in the given input format, so it can be passed back to ParseSalt.
+func FormatSalt(salt [32]byte, format SaltFormat) string {
+	if format == SaltFormatDecimal {
+		return new(big.Int).SetBytes(salt[:]).String()
+	}
+	return "0x" + hex.EncodeToString(salt[:])
+}
+
+// CalculateCreate2AddressFromSalt calculates the CREATE2 address for a salt given as a string.
+// See normalizeSalt for how the string is interpreted.
+func CalculateCreate2AddressFromSalt(initCodeHash []byte, salt string) (string, error) {
+	saltBytes, err := normalizeSalt(salt)
+	if err != nil {
+		return "", err
+	}
+	return CalculateCreate2Address(initCodeHash, saltBytes[:]), nil
+}
@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// FuzzNormalizeSalt checks that any salt string either hashes, left-pads or is rejected,
+// and never panics. Run with: go test -fuzz=FuzzNormalizeSalt ./internal/crypto
+func FuzzNormalizeSalt(f *testing.F) {
+	for _, seed := range []string{
+		"", "0x", "0X", "1", "0x1", "0xabc", "ABC", " 0x01 ", "0xg1", "zz", "hello", "héllo",
+		strings.Repeat("ff", 32), "0x" + strings.Repeat("ff", 32), "0x" + strings.Repeat("f", 65),
+		"0x" + strings.Repeat("00", 33), "0x0x01",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, salt string) {
+		out, err := normalizeSalt(salt)
+		raw, hexErr := decodeSaltHex(salt)
+		switch {
+		case hexErr != nil:
+			// Non-hex input is hashed, never rejected
+			if err != nil {
+				t.Fatalf("normalizeSalt(%q) error = %v, want keccak256 of the input", salt, err)
+			}
+			if !bytes.Equal(out[:], keccak256Bytes([]byte(salt))) {
+				t.Fatalf("normalizeSalt(%q) = %x, want keccak256 of the input", salt, out)
+			}
+		case len(raw) > 32:
+			if err == nil {
+				t.Fatalf("normalizeSalt(%q) = %x, want error for %d bytes", salt, out, len(raw))
+			}
+		default:
+			if err != nil {
+				t.Fatalf("normalizeSalt(%q) error = %v", salt, err)
+			}
+			if !bytes.Equal(out[32-len(raw):], raw) || !bytes.Equal(out[:32-len(raw)], make([]byte, 32-len(raw))) {
+				t.Fatalf("normalizeSalt(%q) = %x, want %x left-padded", salt, out, raw)
+			}
+			// The formatted salt parses back to itself
+			if again, err := ParseHexSalt(FormatSalt(out, SaltFormatHex)); err != nil || again != out {
+				t.Fatalf("ParseHexSalt(FormatSalt(%x)) = %x, %v", out, again, err)
+			}
+		}
+	})
+}
+
+func TestSaltErrors(t *testing.T) {
+	tests := []struct {
+		salt   string
+		format SaltFormat
+		err    error
+	}{
+		{"0x" + strings.Repeat("00", 33), SaltFormatHex, ErrInvalidSaltLength},
+		{"0xzz", SaltFormatHex, ErrInvalidSaltHex},
+		{"-1", SaltFormatDecimal, ErrInvalidSaltDecimal},
+		{"0x10", SaltFormatDecimal, ErrInvalidSaltDecimal},
+		{"1" + strings.Repeat("0", 78), SaltFormatDecimal, ErrInvalidSaltLength},
+	}
+	for _, tt := range tests {
+		if _, err := ParseSalt(tt.salt, tt.format); !errors.Is(err, tt.err) {
+			t.Errorf("ParseSalt(%q) error = %v, want %v", tt.salt, err, tt.err)
+		}
+	}
+	if _, err := normalizeSalt("0x" + strings.Repeat("ff", 33)); !errors.Is(err, ErrInvalidSaltLength) {
+		t.Errorf("normalizeSalt() of 33 bytes error = %v, want %v", err, ErrInvalidSaltLength)
+	}
+	if _, err := ParseSaltFormat("base64"); !errors.Is(err, ErrInvalidSaltFormat) {
+		t.Errorf("ParseSaltFormat(base64) error = %v, want %v", err, ErrInvalidSaltFormat)
+	}
+}
@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+// Divergence describes a salt whose CREATE2 address differs between computation paths
+type Divergence struct {
+	Salt [32]byte
+	Path string // the path that disagreed with the reference
+	Got  string
+	Want string // address from the reference CalculateCreate2Address
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("salt 0x%s: %s computed %s, reference %s", hex.EncodeToString(d.Salt[:]), d.Path, d.Got, d.Want)
+}
+
+// CompareCreate2Paths computes the address of each salt with the reference CalculateCreate2Address
+// and with the hot-path Create2AddressInto under every keccak backend, reusing hasher and buffers the
+// way workers do. It returns the first divergence, or nil if every path agrees.
+func CompareCreate2Paths(initCodeHash []byte, salts [][32]byte) *Divergence {
+	return compareCreate2Paths(initCodeHash, salts, keccakBackends)
+}
+
+func compareCreate2Paths(initCodeHash []byte, salts [][32]byte, backends map[string]func() hash.Hash) *Divergence {
+	var inputBuf [Create2InputLen]byte
+	var hashBuf [32]byte
+	var addrBuf [20]byte
+	copy(inputBuf[:Create2PrefixLen], create2Prefix[:])
+	copy(inputBuf[Create2PrefixLen+32:], initCodeHash)
+
+	names := make([]string, 0, len(backends))
+	hashers := make(map[string]hash.Hash, len(backends))
+	for name, newHasher := range backends {
+		names = append(names, name)
+		hashers[name] = newHasher()
+	}
+	sort.Strings(names)
+	for _, salt := range salts {
+		want := CalculateCreate2Address(initCodeHash, salt[:])
+		copy(inputBuf[Create2PrefixLen:Create2PrefixLen+32], salt[:])
+		for _, name := range names {
+			Create2AddressInto(hashers[name], inputBuf[:], hashBuf[:], addrBuf[:])
+			if got := AddressBytesToChecksumString(addrBuf[:]); got != want {
+				return &Divergence{Salt: salt, Path: "Create2AddressInto/" + name, Got: got, Want: want}
+			}
+		}
+	}
+	return nil
+}
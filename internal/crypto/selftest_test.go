@@ -0,0 +1,39 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"hash"
+	"testing"
+)
+
+// brokenKeccak flips a bit of every digest to simulate a faulty backend
+type brokenKeccak struct{ hash.Hash }
+
+func (b brokenKeccak) Sum(in []byte) []byte {
+	out := b.Hash.Sum(in)
+	out[len(out)-1] ^= 1
+	return out
+}
+
+func TestCompareCreate2Paths(t *testing.T) {
+	initCodeHash := Keccak256([]byte{0x60, 0x80})
+	salts := make([][32]byte, 200)
+	for i := range salts {
+		rand.Read(salts[i][:])
+	}
+	if d := CompareCreate2Paths(initCodeHash, salts); d != nil {
+		t.Fatalf("CompareCreate2Paths() = %v, want agreement", d)
+	}
+
+	backends := map[string]func() hash.Hash{
+		KeccakGeneric: newGenericKeccak256,
+		"broken":      func() hash.Hash { return brokenKeccak{newGenericKeccak256()} },
+	}
+	d := compareCreate2Paths(initCodeHash, salts, backends)
+	if d == nil {
+		t.Fatal("compareCreate2Paths() missed a faulty backend")
+	}
+	if d.Path != "Create2AddressInto/broken" || d.Salt != salts[0] {
+		t.Errorf("divergence = %v, want the broken backend on the first salt", d)
+	}
+}
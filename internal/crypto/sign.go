@@ -0,0 +1,55 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadSigningKey reads an ed25519 private key from a file.
+// The file holds either a 32-byte seed or a 64-byte private key, hex-encoded (with or without 0x).
+func LoadSigningKey(filename string) (ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	h := strings.TrimSpace(string(content))
+	if len(h) >= 2 && (h[0:2] == "0x" || h[0:2] == "0X") {
+		h = h[2:]
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key hex: %w", err)
+	}
+
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("invalid signing key length: got %d bytes, want %d or %d",
+			len(b), ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// resultMessage builds the canonical signed message: salt(32) || address(20).
+func resultMessage(salt [32]byte, addr20 []byte) []byte {
+	msg := make([]byte, 0, 32+20)
+	msg = append(msg, salt[:]...)
+	msg = append(msg, addr20...)
+	return msg
+}
+
+// SignResult signs the canonical salt||address bytes of a mining result.
+func SignResult(key ed25519.PrivateKey, salt [32]byte, addr20 []byte) []byte {
+	return ed25519.Sign(key, resultMessage(salt, addr20))
+}
+
+// VerifyResult checks a signature produced by SignResult.
+func VerifyResult(pub ed25519.PublicKey, salt [32]byte, addr20 []byte, sig []byte) bool {
+	return ed25519.Verify(pub, resultMessage(salt, addr20), sig)
+}
@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignResultRoundTrip(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	// Write the seed as 0x-prefixed hex, as a user would
+	keyFile := filepath.Join(t.TempDir(), "key.hex")
+	if err := os.WriteFile(keyFile, []byte("0x"+hex.EncodeToString(seed)+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	key, err := LoadSigningKey(keyFile)
+	if err != nil {
+		t.Fatalf("LoadSigningKey() error = %v", err)
+	}
+
+	var salt [32]byte
+	salt[31] = 0x2a
+	addr, err := MustAddressBytes("0x0000002DBE996066c3F322753B4AB7F245C13981")
+	if err != nil {
+		t.Fatalf("MustAddressBytes() error = %v", err)
+	}
+
+	sig := SignResult(key, salt, addr)
+	pub := key.Public().(ed25519.PublicKey)
+	if !VerifyResult(pub, salt, addr, sig) {
+		t.Error("VerifyResult() = false for a valid signature")
+	}
+
+	// Tampering with either half of the message must invalidate the signature
+	salt[0] = 1
+	if VerifyResult(pub, salt, addr, sig) {
+		t.Error("VerifyResult() = true for a tampered salt")
+	}
+	salt[0] = 0
+	addr[19] ^= 0xff
+	if VerifyResult(pub, salt, addr, sig) {
+		t.Error("VerifyResult() = true for a tampered address")
+	}
+}
+
+func TestLoadSigningKeyInvalidLength(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key.hex")
+	if err := os.WriteFile(keyFile, []byte("deadbeef"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	if _, err := LoadSigningKey(keyFile); err == nil {
+		t.Error("LoadSigningKey() expected error for short key")
+	}
+}
@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isWildcard reports whether a template character matches any nibble
+func isWildcard(c byte) bool {
+	return c == '.' || c == 'x' || c == 'X'
+}
+
+// MatchTemplate reports whether a hex address matches a template anchored at its start.
+// In the template '.' or 'x' matches any hex character; other characters must match
+// case-insensitively. The template may be shorter than the 40-char address.
+func MatchTemplate(addr, template string) bool {
+	addr = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X"))
+	template = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(template, "0x"), "0X"))
+	if len(template) > len(addr) {
+		return false
+	}
+	for i := 0; i < len(template); i++ {
+		if !isWildcard(template[i]) && template[i] != addr[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CompileTemplate converts a template into per-byte mask and value slices for raw address
+// matching: an address matches when addr[i]&mask[i] == value[i] for every i.
+func CompileTemplate(template string) (mask, value []byte, err error) {
+	t := strings.TrimPrefix(strings.TrimPrefix(template, "0x"), "0X")
+	if len(t) == 0 || len(t) > 40 {
+		return nil, nil, fmt.Errorf("template must be 1 to 40 characters, got %d", len(t))
+	}
+
+	n := (len(t) + 1) / 2
+	mask = make([]byte, n)
+	value = make([]byte, n)
+	for i := 0; i < len(t); i++ {
+		if isWildcard(t[i]) {
+			continue
+		}
+		nibble, ok := hexNibble(t[i])
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid template character %q", t[i])
+		}
+		shift := uint(4 * (1 - i%2))
+		mask[i/2] |= 0xF << shift
+		value[i/2] |= nibble << shift
+	}
+	return mask, value, nil
+}
+
+// hexNibble decodes a single hex character
+func hexNibble(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,55 @@
+package crypto
+
+import "testing"
+
+func TestMatchTemplate(t *testing.T) {
+	addr := "0xDEAD1234567890abcdef1234567890abcdefBEEF"
+	tests := []struct {
+		name     string
+		template string
+		expected bool
+	}{
+		{"literal prefix", "dead", true},
+		{"dot wildcards", "dead....5678", true},
+		{"x wildcards", "0xdeadxxxx5678", true},
+		{"anchored at start", "1234", false},
+		{"mismatch after wildcard", "dead....9999", false},
+		{"full length", "dead1234567890abcdef1234567890abcdef....", true},
+		{"longer than address", "dead1234567890abcdef1234567890abcdefbeef0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchTemplate(addr, tt.template); got != tt.expected {
+				t.Errorf("MatchTemplate(%s, %s) = %v, want %v", addr, tt.template, got, tt.expected)
+			}
+
+			// The compiled form must agree with the string form
+			mask, value, err := CompileTemplate(tt.template)
+			if err != nil {
+				if tt.name != "longer than address" {
+					t.Fatalf("CompileTemplate(%s) error = %v", tt.template, err)
+				}
+				return
+			}
+			raw, _ := MustAddressBytes(addr)
+			match := true
+			for i := range mask {
+				if raw[i]&mask[i] != value[i] {
+					match = false
+				}
+			}
+			if match != tt.expected {
+				t.Errorf("compiled template %s match = %v, want %v", tt.template, match, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompileTemplateInvalid(t *testing.T) {
+	for _, template := range []string{"", "dezd", "0x"} {
+		if _, _, err := CompileTemplate(template); err == nil {
+			t.Errorf("CompileTemplate(%q) expected error", template)
+		}
+	}
+}
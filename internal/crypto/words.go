@@ -0,0 +1,25 @@
+package crypto
+
+import "strings"
+
+// DefaultWords is the built-in list of recognizable hex words used by --words
+var DefaultWords = []string{
+	"dead", "beef", "cafe", "face", "babe", "fade", "feed", "bead",
+	"deaf", "c0de", "c0ffee", "decade", "facade", "0ff1ce", "abba", "b00b",
+}
+
+// WordScore counts the non-overlapping occurrences of each word in the hex address.
+// Matching is case-insensitive and ignores a 0x prefix.
+func WordScore(address string, words []string) int {
+	addr := strings.ToLower(address)
+	if len(addr) >= 2 && addr[0:2] == "0x" {
+		addr = addr[2:]
+	}
+	score := 0
+	for _, word := range words {
+		if word != "" {
+			score += strings.Count(addr, strings.ToLower(word))
+		}
+	}
+	return score
+}
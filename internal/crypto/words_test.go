@@ -0,0 +1,27 @@
+package crypto
+
+import "testing"
+
+func TestWordScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		words    []string
+		expected int
+	}{
+		{"no words", "0x1234567890123456789012345678901234567890", DefaultWords, 0},
+		{"single word", "0xdead567890123456789012345678901234567890", DefaultWords, 1},
+		{"repeated word", "0xdeaddead90123456789012345678901234567890", DefaultWords, 2},
+		{"adjacent words", "0xDeadBeef90123456789012345678901234567890", DefaultWords, 2},
+		{"custom list", "0xdeadbeef90123456789012345678901234567890", []string{"ef90"}, 1},
+		{"empty word ignored", "0xdeadbeef90123456789012345678901234567890", []string{""}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordScore(tt.address, tt.words); got != tt.expected {
+				t.Errorf("WordScore(%s) = %d, want %d", tt.address, got, tt.expected)
+			}
+		})
+	}
+}
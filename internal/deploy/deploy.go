@@ -0,0 +1,215 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/internal/rpc"
+)
+
+// Defaults for Deployer
+const (
+	DefaultPollInterval = 2 * time.Second
+	GasMarginPercent    = 20 // added to the node's gas estimate
+)
+
+var (
+	ErrReverted        = errors.New("deployment transaction reverted")
+	ErrAddressMismatch = errors.New("deployment does not produce the mined address")
+	ErrNoFactoryCode   = errors.New("factory is not deployed on this chain")
+)
+
+// Function selectors of the supported factories
+var (
+	selectorDeploy        = crypto.Keccak256([]byte("deploy(bytes,bytes32)"))[:4]
+	selectorDeployCreate2 = crypto.Keccak256([]byte("deployCreate2(bytes32,bytes)"))[:4]
+)
+
+// Calldata builds the factory call deploying initcode under salt. CreateX takes
+// deployCreate2(bytes32,bytes), the Arachnid proxy takes salt||initcode as raw calldata,
+// and any other factory is called through ERC-2470's deploy(bytes,bytes32).
+func Calldata(factory string, createX bool, salt [32]byte, initcode []byte) []byte {
+	switch {
+	case createX:
+		data := append([]byte(nil), selectorDeployCreate2...)
+		data = append(data, salt[:]...)
+		data = append(data, abiWord(64)...)
+		return append(data, abiBytes(initcode)...)
+	case strings.EqualFold(factory, crypto.ArachnidFactoryAddress):
+		return append(salt[:], initcode...)
+	default:
+		data := append([]byte(nil), selectorDeploy...)
+		data = append(data, abiWord(64)...)
+		data = append(data, salt[:]...)
+		return append(data, abiBytes(initcode)...)
+	}
+}
+
+// abiWord encodes n as a 32-byte big-endian word
+func abiWord(n uint64) []byte {
+	return new(big.Int).SetUint64(n).FillBytes(make([]byte, 32))
+}
+
+// abiBytes encodes the tail of a dynamic bytes argument: length, then data padded to 32 bytes
+func abiBytes(b []byte) []byte {
+	out := abiWord(uint64(len(b)))
+	out = append(out, b...)
+	if pad := len(b) % 32; pad != 0 {
+		out = append(out, make([]byte, 32-pad)...)
+	}
+	return out
+}
+
+// CheckFactory returns ErrNoFactoryCode if factory holds no code on the node's chain, in
+// which case salts mined for it cannot be deployed there
+func CheckFactory(ctx context.Context, client *rpc.Client, factory string) error {
+	code, err := client.GetCode(ctx, factory)
+	if err != nil {
+		return fmt.Errorf("get code: %w", err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w: no code at %s", ErrNoFactoryCode, factory)
+	}
+	return nil
+}
+
+// Deployer signs and broadcasts factory deployments from one key
+type Deployer struct {
+	Client       *rpc.Client
+	Key          *secp256k1.PrivateKey
+	PollInterval time.Duration // wait between receipt polls
+}
+
+// New creates a deployer sending through the node at url
+func New(url string, key *secp256k1.PrivateKey) *Deployer {
+	return &Deployer{Client: rpc.New(url), Key: key, PollInterval: DefaultPollInterval}
+}
+
+// Plan is a deployment transaction ready to be signed, shown for confirmation first
+type Plan struct {
+	From     string
+	Factory  string
+	Address  string // the mined address the deployment must produce
+	ChainID  uint64
+	Nonce    uint64
+	Gas      uint64
+	GasPrice *big.Int
+	Data     []byte
+}
+
+// MaxCost returns the most the transaction can cost, gas times gas price
+func (p *Plan) MaxCost() *big.Int {
+	return new(big.Int).Mul(p.GasPrice, new(big.Int).SetUint64(p.Gas))
+}
+
+func (p *Plan) String() string {
+	return fmt.Sprintf("deploy %s via factory %s from %s on chain %d (nonce %d, gas %d at %s, at most %s)",
+		p.Address, p.Factory, p.From, p.ChainID, p.Nonce, p.Gas, formatWei(p.GasPrice), formatWei(p.MaxCost()))
+}
+
+// Result reports a confirmed deployment
+type Result struct {
+	TxHash  string
+	Address string
+	Block   uint64
+	GasUsed uint64
+}
+
+// Prepare fetches the chain ID, nonce, gas price and gas estimate for deploying the
+// mined address through factory
+func (d *Deployer) Prepare(ctx context.Context, factory string, createX bool, salt [32]byte, initcode []byte, address string) (*Plan, error) {
+	// CreateX derives the CREATE2 salt on chain; other factories use it as given
+	if !createX {
+		factoryBytes, err := crypto.MustAddressBytes(factory)
+		if err != nil {
+			return nil, err
+		}
+		prefix := crypto.Create2PrefixFor(factoryBytes)
+		input := append(append(prefix[:], salt[:]...), crypto.Keccak256(initcode)...)
+		if got := crypto.AddressBytesToChecksumString(crypto.Keccak256(input)[12:]); !strings.EqualFold(got, address) {
+			return nil, fmt.Errorf("%w: salt and init code give %s, not %s", ErrAddressMismatch, got, address)
+		}
+	}
+
+	p := &Plan{
+		From:    KeyAddress(d.Key),
+		Factory: factory,
+		Address: address,
+		Data:    Calldata(factory, createX, salt, initcode),
+	}
+	var err error
+	if p.ChainID, err = d.Client.ChainID(ctx); err != nil {
+		return nil, fmt.Errorf("chain id: %w", err)
+	}
+	if p.Nonce, err = d.Client.PendingNonce(ctx, p.From); err != nil {
+		return nil, fmt.Errorf("nonce: %w", err)
+	}
+	if p.GasPrice, err = d.Client.GasPrice(ctx); err != nil {
+		return nil, fmt.Errorf("gas price: %w", err)
+	}
+	gas, err := d.Client.EstimateGas(ctx, p.From, factory, p.Data)
+	if err != nil {
+		return nil, fmt.Errorf("estimate gas: %w", err)
+	}
+	p.Gas = gas + gas*GasMarginPercent/100
+	return p, nil
+}
+
+// Send signs and broadcasts the plan, waits for its receipt and checks that the mined
+// address now holds code
+func (d *Deployer) Send(ctx context.Context, p *Plan) (*Result, error) {
+	to, err := crypto.MustAddressBytes(p.Factory)
+	if err != nil {
+		return nil, err
+	}
+	tx := &legacyTx{Nonce: p.Nonce, GasPrice: p.GasPrice, Gas: p.Gas, To: to, Data: p.Data, ChainID: p.ChainID}
+	raw, _ := tx.sign(d.Key)
+	hash, err := d.Client.SendRawTransaction(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("send transaction: %w", err)
+	}
+
+	receipt, err := d.waitReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{TxHash: hash, Address: p.Address}
+	res.Block, _ = rpc.ParseUint(receipt.BlockNumber)
+	res.GasUsed, _ = rpc.ParseUint(receipt.GasUsed)
+	if !receipt.Succeeded() {
+		return res, fmt.Errorf("%w (tx %s)", ErrReverted, hash)
+	}
+
+	code, err := d.Client.GetCode(ctx, p.Address)
+	if err != nil {
+		return res, fmt.Errorf("get code: %w", err)
+	}
+	if len(code) == 0 {
+		return res, fmt.Errorf("%w: no code at %s after tx %s", ErrAddressMismatch, p.Address, hash)
+	}
+	return res, nil
+}
+
+// waitReceipt polls until the transaction is mined or ctx is done
+func (d *Deployer) waitReceipt(ctx context.Context, hash string) (*rpc.Receipt, error) {
+	for {
+		receipt, err := d.Client.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("receipt: %w", err)
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for %s: %w", hash, ctx.Err())
+		case <-time.After(d.PollInterval):
+		}
+	}
+}
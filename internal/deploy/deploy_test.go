@@ -0,0 +1,285 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/internal/rpc"
+)
+
+const testKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+// simNode is an in-memory JSON-RPC backend. It checks each raw transaction's EIP-155
+// signature and executes ERC-2470 deploy calls by storing code at the CREATE2 address.
+type simNode struct {
+	t        *testing.T
+	chainID  uint64
+	revert   bool // receipts report failure
+	mutate   bool // deploy to a different address than the calldata implies
+	pending  int  // receipt polls answered with null first
+	mu       sync.Mutex
+	code     map[string][]byte
+	receipts map[string]map[string]string
+	sender   string
+}
+
+func newSimNode(t *testing.T) (*simNode, *httptest.Server) {
+	n := &simNode{t: t, chainID: 1337, code: map[string][]byte{}, receipts: map[string]map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(srv.Close)
+	return n, srv
+}
+
+func (n *simNode) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int64             `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		n.t.Errorf("decode request: %v", err)
+		return
+	}
+	param := func(i int) string {
+		var s string
+		json.Unmarshal(req.Params[i], &s)
+		return s
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var result any
+	switch req.Method {
+	case "eth_chainId":
+		result = "0x539"
+	case "eth_getTransactionCount":
+		result = "0x7"
+	case "eth_gasPrice":
+		result = "0x3b9aca00"
+	case "eth_estimateGas":
+		result = "0x30d40"
+	case "eth_sendRawTransaction":
+		raw, _ := hex.DecodeString(strings.TrimPrefix(param(0), "0x"))
+		result = n.execute(raw)
+	case "eth_getTransactionReceipt":
+		if n.pending > 0 {
+			n.pending--
+			break
+		}
+		result = n.receipts[param(0)]
+	case "eth_getCode":
+		result = "0x" + hex.EncodeToString(n.code[strings.ToLower(param(0))])
+	default:
+		n.t.Errorf("unexpected method %s", req.Method)
+	}
+	json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+// execute verifies a signed legacy transaction and applies its deploy call
+func (n *simNode) execute(raw []byte) string {
+	fields := decodeRLPList(n.t, raw)
+	if len(fields) != 9 {
+		n.t.Fatalf("transaction has %d fields, want 9", len(fields))
+	}
+	v := new(big.Int).SetBytes(fields[6]).Uint64()
+	recovery := v - 35 - 2*n.chainID
+	if recovery > 1 {
+		n.t.Fatalf("v = %d is not EIP-155 for chain %d", v, n.chainID)
+	}
+	chainID := new(big.Int).SetUint64(n.chainID)
+	unsigned := [][]byte{}
+	for _, f := range fields[:6] {
+		unsigned = append(unsigned, rlpString(f))
+	}
+	unsigned = append(unsigned, rlpUint(chainID), rlpUint(new(big.Int)), rlpUint(new(big.Int)))
+	sigHash := crypto.Keccak256(rlpList(unsigned...))
+	compact := append([]byte{byte(27 + recovery)}, append(leftPad(fields[7]), leftPad(fields[8])...)...)
+	pub, _, err := ecdsa.RecoverCompact(compact, sigHash)
+	if err != nil {
+		n.t.Fatalf("recover sender: %v", err)
+	}
+	uncompressed := pub.SerializeUncompressed()
+	n.sender = crypto.AddressBytesToChecksumString(crypto.Keccak256(uncompressed[1:])[12:])
+
+	// deploy(bytes,bytes32): selector, offset, salt, length, init code
+	data := fields[5]
+	if !bytes.Equal(data[:4], selectorDeploy) {
+		n.t.Fatalf("calldata selector %x, want deploy(bytes,bytes32)", data[:4])
+	}
+	salt := data[36:68]
+	size := new(big.Int).SetBytes(data[68:100]).Int64()
+	initcode := data[100 : 100+size]
+	if n.mutate {
+		salt = make([]byte, 32)
+	}
+	prefix := crypto.Create2PrefixFor(fields[3])
+	input := append(append(prefix[:], salt...), crypto.Keccak256(initcode)...)
+	addr := "0x" + hex.EncodeToString(crypto.Keccak256(input)[12:])
+	n.code[addr] = []byte{0x60, 0x00}
+
+	hash := "0x" + hex.EncodeToString(crypto.Keccak256(raw))
+	status := "0x1"
+	if n.revert {
+		status = "0x0"
+	}
+	n.receipts[hash] = map[string]string{"transactionHash": hash, "status": status, "blockNumber": "0x10", "gasUsed": "0x1d4c0"}
+	return hash
+}
+
+// leftPad pads a big-endian integer to 32 bytes
+func leftPad(b []byte) []byte {
+	return append(make([]byte, 32-len(b)), b...)
+}
+
+// decodeRLPList decodes a flat RLP list of byte strings
+func decodeRLPList(t *testing.T, b []byte) [][]byte {
+	payload, rest := rlpItem(t, b)
+	if len(rest) != 0 {
+		t.Fatalf("trailing bytes after RLP list")
+	}
+	var out [][]byte
+	for len(payload) > 0 {
+		var item []byte
+		item, payload = rlpItem(t, payload)
+		out = append(out, item)
+	}
+	return out
+}
+
+// rlpItem splits the first item's payload from the rest
+func rlpItem(t *testing.T, b []byte) ([]byte, []byte) {
+	switch p := b[0]; {
+	case p < 0x80:
+		return b[:1], b[1:]
+	case p <= 0xb7:
+		n := int(p - 0x80)
+		return b[1 : 1+n], b[1+n:]
+	case p < 0xc0:
+		ll := int(p - 0xb7)
+		n := int(new(big.Int).SetBytes(b[1 : 1+ll]).Int64())
+		return b[1+ll : 1+ll+n], b[1+ll+n:]
+	case p <= 0xf7:
+		n := int(p - 0xc0)
+		return b[1 : 1+n], b[1+n:]
+	default:
+		ll := int(p - 0xf7)
+		n := int(new(big.Int).SetBytes(b[1 : 1+ll]).Int64())
+		return b[1+ll : 1+ll+n], b[1+ll+n:]
+	}
+}
+
+func TestDeploy(t *testing.T) {
+	initcode, _ := hex.DecodeString("608060405234801561001057600080fd5b50600436106100365760003560e01c8063")
+	var salt [32]byte
+	salt[31] = 0x2a
+	factory := crypto.FactoryAddress
+	address := crypto.CalculateCreate2Address(crypto.Keccak256(initcode), salt[:])
+
+	tests := []struct {
+		name    string
+		setup   func(n *simNode)
+		address string
+		err     error
+	}{
+		{"deployed", func(n *simNode) { n.pending = 2 }, address, nil},
+		{"reverted", func(n *simNode) { n.revert = true }, address, ErrReverted},
+		{"code lands elsewhere", func(n *simNode) { n.mutate = true }, address, ErrAddressMismatch},
+		{"wrong mined address", func(n *simNode) {}, crypto.FactoryAddress, ErrAddressMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, srv := newSimNode(t)
+			tt.setup(node)
+			key, err := ParsePrivateKey(testKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := New(srv.URL, key)
+			d.PollInterval = time.Millisecond
+			ctx := context.Background()
+
+			plan, err := d.Prepare(ctx, factory, false, salt, initcode, tt.address)
+			if err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Prepare() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if plan.ChainID != 1337 || plan.Nonce != 7 || plan.Gas != 240000 {
+				t.Errorf("plan = %+v, want chain 1337, nonce 7 and the estimate plus 20%%", plan)
+			}
+			res, err := d.Send(ctx, plan)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Send() error = %v, want %v", err, tt.err)
+			}
+			if node.sender != KeyAddress(key) {
+				t.Errorf("transaction signed by %s, want %s", node.sender, KeyAddress(key))
+			}
+			if tt.err == nil && (res.Address != address || res.Block != 16 || !strings.HasPrefix(res.TxHash, "0x")) {
+				t.Errorf("result = %+v", res)
+			}
+		})
+	}
+}
+
+func TestCheckFactory(t *testing.T) {
+	node, srv := newSimNode(t)
+	client := rpc.New(srv.URL)
+	ctx := context.Background()
+
+	if err := CheckFactory(ctx, client, crypto.FactoryAddress); !errors.Is(err, ErrNoFactoryCode) {
+		t.Fatalf("CheckFactory() on an empty chain = %v, want ErrNoFactoryCode", err)
+	}
+	node.code[strings.ToLower(crypto.FactoryAddress)] = []byte{0x60, 0x00}
+	if err := CheckFactory(ctx, client, crypto.FactoryAddress); err != nil {
+		t.Fatalf("CheckFactory() with factory code = %v, want nil", err)
+	}
+}
+
+func TestKeyAddress(t *testing.T) {
+	// Well-known test vector from the web3 documentation
+	key, err := ParsePrivateKey(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := KeyAddress(key), "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"; got != want {
+		t.Errorf("KeyAddress() = %s, want %s", got, want)
+	}
+	for _, bad := range []string{"", "0x1234", "0x" + strings.Repeat("00", 32), "0x" + strings.Repeat("ff", 32)} {
+		if _, err := ParsePrivateKey(bad); err == nil {
+			t.Errorf("ParsePrivateKey(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestCalldata(t *testing.T) {
+	var salt [32]byte
+	salt[0] = 0xaa
+	initcode := []byte{0x60, 0x80}
+
+	erc2470 := Calldata(crypto.FactoryAddress, false, salt, initcode)
+	if hex.EncodeToString(erc2470[:4]) != "4af63f02" || len(erc2470) != 4+4*32 {
+		t.Errorf("deploy(bytes,bytes32) calldata = %x", erc2470)
+	}
+	createX := Calldata(crypto.CreateXAddress, true, salt, initcode)
+	if hex.EncodeToString(createX[:4]) != "26307668" || !bytes.Equal(createX[4:36], salt[:]) {
+		t.Errorf("deployCreate2(bytes32,bytes) calldata = %x", createX)
+	}
+	arachnid := Calldata(crypto.ArachnidFactoryAddress, false, salt, initcode)
+	if !bytes.Equal(arachnid, append(salt[:], initcode...)) {
+		t.Errorf("arachnid calldata = %x", arachnid)
+	}
+}
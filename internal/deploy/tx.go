@@ -0,0 +1,104 @@
+package deploy
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+)
+
+// ParsePrivateKey decodes a hex secp256k1 private key (with or without 0x)
+func ParsePrivateKey(s string) (*secp256k1.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil || len(b) != 32 {
+		return nil, errors.New("private key must be 32 bytes of hex")
+	}
+	var k secp256k1.ModNScalar
+	if overflow := k.SetByteSlice(b); overflow || k.IsZero() {
+		return nil, errors.New("private key is zero or not below the curve order")
+	}
+	return secp256k1.NewPrivateKey(&k), nil
+}
+
+// KeyAddress returns the checksummed Ethereum address controlled by key
+func KeyAddress(key *secp256k1.PrivateKey) string {
+	pub := key.PubKey().SerializeUncompressed()
+	return crypto.AddressBytesToChecksumString(crypto.Keccak256(pub[1:])[12:])
+}
+
+// legacyTx is an EIP-155 replay-protected legacy transaction with no value
+type legacyTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       []byte // 20 bytes
+	Data     []byte
+	ChainID  uint64
+}
+
+// sign returns the RLP-encoded signed transaction and its hash
+func (tx *legacyTx) sign(key *secp256k1.PrivateKey) ([]byte, []byte) {
+	fields := [][]byte{
+		rlpUint(new(big.Int).SetUint64(tx.Nonce)),
+		rlpUint(tx.GasPrice),
+		rlpUint(new(big.Int).SetUint64(tx.Gas)),
+		rlpString(tx.To),
+		rlpUint(new(big.Int)),
+		rlpString(tx.Data),
+	}
+	chainID := new(big.Int).SetUint64(tx.ChainID)
+	sigHash := crypto.Keccak256(rlpList(append(fields, rlpUint(chainID), rlpUint(new(big.Int)), rlpUint(new(big.Int)))...))
+
+	// Compact signatures are <27 + recovery id><R><S>
+	sig := ecdsa.SignCompact(key, sigHash, false)
+	v := new(big.Int).SetUint64(uint64(sig[0] - 27))
+	v.Add(v, new(big.Int).Lsh(chainID, 1))
+	v.Add(v, big.NewInt(35))
+	r := new(big.Int).SetBytes(sig[1:33])
+	s := new(big.Int).SetBytes(sig[33:65])
+
+	raw := rlpList(append(fields, rlpUint(v), rlpUint(r), rlpUint(s))...)
+	return raw, crypto.Keccak256(raw)
+}
+
+// rlpString encodes a byte string
+func rlpString(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpUint encodes a non-negative integer as its minimal big-endian bytes
+func rlpUint(n *big.Int) []byte {
+	return rlpString(n.Bytes())
+}
+
+// rlpList encodes already-encoded items as a list
+func rlpList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(rlpHeader(0xc0, len(payload)), payload...)
+}
+
+// rlpHeader encodes the prefix for a string (base 0x80) or list (base 0xc0) of n bytes
+func rlpHeader(base byte, n int) []byte {
+	if n <= 55 {
+		return []byte{base + byte(n)}
+	}
+	size := new(big.Int).SetInt64(int64(n)).Bytes()
+	return append([]byte{base + 55 + byte(len(size))}, size...)
+}
+
+// formatWei formats a wei amount in gwei for confirmation prompts
+func formatWei(wei *big.Int) string {
+	gwei := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9))
+	return fmt.Sprintf("%s gwei", gwei.Text('f', 2))
+}
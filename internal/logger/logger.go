@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
 )
 
 // Log flags
@@ -12,6 +14,10 @@ const (
 	Lmicroseconds = log.Lmicroseconds
 )
 
+// MaxWriteFailures is how many consecutive failed writes make a logger give up on its
+// output and write to stderr instead
+const MaxWriteFailures = 3
+
 // Logger wraps the standard log.Logger with additional functionality
 type Logger struct {
 	*log.Logger
@@ -24,19 +30,60 @@ func New() *Logger {
 	}
 }
 
-// NewWriter creates a new logger that writes to the provided writer
+// NewWriter creates a new logger that writes to the provided writer. If the writer keeps
+// failing (a full disk, a removed file), lines go to stderr instead.
 func NewWriter(w io.Writer) *Logger {
 	return &Logger{
-		Logger: log.New(w, "", log.LstdFlags),
+		Logger: log.New(newFallbackWriter(w, os.Stderr), "", log.LstdFlags),
 	}
 }
 
-// SetOutput sets the output destination for the logger
+// SetOutput sets the output destination for the logger, with the same stderr fallback as NewWriter
 func (l *Logger) SetOutput(w io.Writer) {
-	l.Logger.SetOutput(w)
+	l.Logger.SetOutput(newFallbackWriter(w, os.Stderr))
 }
 
 // SetFlags sets the output flags for the logger
 func (l *Logger) SetFlags(flag int) {
 	l.Logger.SetFlags(flag)
 }
+
+// SetPrefix tags every line with prefix, such as an instance name, ahead of the timestamp.
+// A space is added to separate it from the rest of the line.
+func (l *Logger) SetPrefix(prefix string) {
+	if prefix != "" && !strings.HasSuffix(prefix, " ") {
+		prefix += " "
+	}
+	l.Logger.SetPrefix(prefix)
+}
+
+// fallbackWriter copies lines the primary writer fails to accept to the fallback, and stops
+// trying the primary after MaxWriteFailures consecutive failures. log.Logger serializes
+// calls to Write, so no locking is needed.
+type fallbackWriter struct {
+	w        io.Writer
+	fallback io.Writer
+	failures int
+	degraded bool
+}
+
+func newFallbackWriter(w, fallback io.Writer) *fallbackWriter {
+	return &fallbackWriter{w: w, fallback: fallback}
+}
+
+func (f *fallbackWriter) Write(p []byte) (int, error) {
+	if f.degraded {
+		return f.fallback.Write(p)
+	}
+	n, err := f.w.Write(p)
+	if err == nil {
+		f.failures = 0
+		return n, nil
+	}
+	f.failures++
+	if f.failures >= MaxWriteFailures {
+		f.degraded = true
+		fmt.Fprintf(f.fallback, "Warning: log output failed %d times in a row (%v); logging to stderr from now on\n", f.failures, err)
+	}
+	return f.fallback.Write(p)
+}
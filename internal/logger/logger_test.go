@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+// failingWriter accepts ok writes, then fails every write after that
+type failingWriter struct {
+	ok    int
+	lines []string
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(w.lines) >= w.ok {
+		return 0, errors.New("no space left on device")
+	}
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func TestFallbackAfterRepeatedFailures(t *testing.T) {
+	primary := &failingWriter{ok: 2}
+	var stderr bytes.Buffer
+	l := &Logger{Logger: log.New(newFallbackWriter(primary, &stderr), "", 0)}
+
+	for i := 0; i < 10; i++ {
+		l.Printf("line %d", i)
+	}
+
+	if len(primary.lines) != 2 {
+		t.Errorf("primary got %d lines, want the 2 written before it failed", len(primary.lines))
+	}
+	out := stderr.String()
+	if n := strings.Count(out, "logging to stderr from now on"); n != 1 {
+		t.Errorf("degradation logged %d times, want once:\n%s", n, out)
+	}
+	// No line is lost: everything the primary rejected reached stderr, in order
+	for i := 2; i < 10; i++ {
+		if !strings.Contains(out, fmt.Sprintf("line %d\n", i)) {
+			t.Errorf("line %d missing from fallback output:\n%s", i, out)
+		}
+	}
+}
+
+func TestTransientFailureRecovers(t *testing.T) {
+	var stderr, primary bytes.Buffer
+	f := newFallbackWriter(&primary, &stderr)
+	f.failures = MaxWriteFailures - 1
+
+	// A successful write resets the failure count, so later failures start over
+	if _, err := f.Write([]byte("ok\n")); err != nil {
+		t.Fatal(err)
+	}
+	if f.failures != 0 || f.degraded {
+		t.Errorf("after a good write failures=%d degraded=%v, want 0 false", f.failures, f.degraded)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected fallback output: %q", stderr.String())
+	}
+}
+
+func TestSetPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWriter(&buf)
+	l.SetFlags(0)
+	l.SetPrefix("miner-a")
+	l.Printf("Mining started")
+	l.SetPrefix("")
+	l.Printf("untagged")
+
+	if got, want := buf.String(), "miner-a Mining started\nuntagged\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package matcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Replies a matcher program may give
+const (
+	ReplyAccept = "accept"
+	ReplyReject = "reject"
+)
+
+// ErrEmptyCommand is returned by Start for a command with no program
+var ErrEmptyCommand = errors.New("matcher command is empty")
+
+// Cmd is an external program run as a second-stage address filter. It is started once
+// and serves the whole run over a line protocol. For each batch of candidates, which
+// already passed the miner's own criteria, the miner writes one line per candidate holding
+// its EIP-55 checksummed address, e.g.
+//
+//	0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A
+//
+// and then reads one reply line per candidate, in the same order: accept or reject. The
+// program must flush its output after each reply. Any other reply, or the program exiting,
+// is an error that ends the run. Filter is safe for concurrent use; batches are serialized.
+type Cmd struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// Start launches command, split on whitespace into the program and its arguments. The
+// program's stderr is passed through.
+func Start(command string) (*Cmd, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, ErrEmptyCommand
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start matcher: %w", err)
+	}
+	return &Cmd{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// Filter sends a batch of addresses and reports, for each, whether the program accepted it
+func (c *Cmd) Filter(addresses []string) ([]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var batch strings.Builder
+	for _, addr := range addresses {
+		batch.WriteString(addr)
+		batch.WriteByte('\n')
+	}
+	if _, err := io.WriteString(c.stdin, batch.String()); err != nil {
+		return nil, fmt.Errorf("write to matcher: %w", err)
+	}
+
+	accepted := make([]bool, len(addresses))
+	for i, addr := range addresses {
+		line, err := c.stdout.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("read matcher reply for %s: %w", addr, err)
+		}
+		switch reply := strings.TrimSpace(line); reply {
+		case ReplyAccept:
+			accepted[i] = true
+		case ReplyReject:
+		default:
+			return nil, fmt.Errorf("matcher replied %q for %s, want %s or %s", reply, addr, ReplyAccept, ReplyReject)
+		}
+	}
+	return accepted, nil
+}
+
+// Close closes the program's input and waits for it to exit
+func (c *Cmd) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stdin.Close()
+	return c.cmd.Wait()
+}
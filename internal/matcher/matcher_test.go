@@ -0,0 +1,75 @@
+package matcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// script writes an executable shell script into a temporary directory
+func script(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("matcher scripts need a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "matcher.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFilter(t *testing.T) {
+	// Accepts addresses ending in an odd hex digit
+	m, err := Start(script(t, `while read addr; do
+  case "$addr" in *[13579bBdDfF]) echo accept ;; *) echo reject ;; esac
+done
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	addrs := []string{
+		"0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9A",
+		"0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A9b",
+		"0xdEAd2c60bfbEbd6a2C1c5Bd2CE3e8Ab2cc6a1A91",
+	}
+	// Two batches over the same process
+	for range 2 {
+		got, err := m.Filter(addrs)
+		if err != nil {
+			t.Fatalf("Filter() error = %v", err)
+		}
+		if len(got) != 3 || got[0] || !got[1] || !got[2] {
+			t.Errorf("Filter() = %v, want [false true true]", got)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown reply", "while read addr; do echo maybe; done\n"},
+		{"exits early", "read addr; echo accept\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Start(script(t, tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Close()
+			if _, err := m.Filter([]string{"0x01", "0x02"}); err == nil {
+				t.Error("Filter() succeeded, want an error")
+			}
+		})
+	}
+	if _, err := Start("  "); err != ErrEmptyCommand {
+		t.Errorf("Start() error = %v, want ErrEmptyCommand", err)
+	}
+}
@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Defaults for Client
+const (
+	DefaultTimeout = 30 * time.Second // bounds each JSON-RPC request
+	DefaultRetries = 3                // further attempts after a transient failure
+	DefaultBackoff = 500 * time.Millisecond
+)
+
+// Client is a minimal Ethereum JSON-RPC client over HTTP
+type Client struct {
+	URL  string
+	HTTP *http.Client
+
+	// A call failing with a transient error (see Retryable) is retried up to Retries times,
+	// waiting Backoff before the first retry and doubling the wait each time
+	Retries int
+	Backoff time.Duration
+
+	nextID int64
+}
+
+// New creates a client for url with the default timeout and retries
+func New(url string) *Client {
+	return &Client{
+		URL:     url,
+		HTTP:    &http.Client{Timeout: DefaultTimeout},
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}
+}
+
+// Error is an error object returned by the node
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// StatusError is a non-2xx HTTP response from the node
+type StatusError struct {
+	Method string
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: node returned %s", e.Method, e.Status)
+}
+
+// Retryable reports whether err may clear up if the call is repeated: a network error or
+// request timeout, or an HTTP 5xx or 429 from the node. Node error objects, such as a revert,
+// malformed responses and other HTTP 4xx responses are permanent, as is cancellation. Call
+// also stops once its context is done.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code >= 500 || status.Code == http.StatusTooManyRequests
+	}
+	var rpcErr *Error
+	var syntax *json.SyntaxError
+	return !errors.As(err, &rpcErr) && !errors.As(err, &syntax) && !errors.As(err, new(*json.UnmarshalTypeError))
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Call invokes method with params and decodes the result into result, which may be nil.
+// Transient failures are retried with backoff.
+func (c *Client) Call(ctx context.Context, result any, method string, params ...any) error {
+	return c.retry(ctx, func() error {
+		return c.call(ctx, result, method, params)
+	})
+}
+
+// retry runs attempt until it succeeds, fails permanently, the retries run out or ctx is done
+func (c *Client) retry(ctx context.Context, attempt func() error) error {
+	wait := c.Backoff
+	for retry := 0; ; retry++ {
+		err := attempt()
+		if retry >= c.Retries || !Retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// call makes a single attempt of Call
+func (c *Client) call(ctx context.Context, result any, method string, params []any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(request{JSONRPC: "2.0", ID: atomic.AddInt64(&c.nextID, 1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		return &StatusError{Method: method, Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	var out response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if out.Error != nil {
+		return out.Error
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(out.Result, result)
+}
+
+// ChainID returns the chain ID the node serves
+func (c *Client) ChainID(ctx context.Context) (uint64, error) {
+	return c.quantity(ctx, "eth_chainId")
+}
+
+// PendingNonce returns the next nonce for addr, counting pending transactions
+func (c *Client) PendingNonce(ctx context.Context, addr string) (uint64, error) {
+	return c.quantity(ctx, "eth_getTransactionCount", addr, "pending")
+}
+
+// GasPrice returns the node's suggested legacy gas price in wei
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	var s string
+	if err := c.Call(ctx, &s, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return ParseBig(s)
+}
+
+// EstimateGas estimates the gas for a call from from to to with data
+func (c *Client) EstimateGas(ctx context.Context, from, to string, data []byte) (uint64, error) {
+	msg := map[string]string{"from": from, "to": to, "data": EncodeBytes(data)}
+	return c.quantity(ctx, "eth_estimateGas", msg)
+}
+
+// SendRawTransaction broadcasts a signed transaction and returns its hash. A send that fails
+// in transit may still have reached the node, so a retry rejected with an error such as
+// "already known" or "nonce too low" counts as sent when the node has the transaction.
+func (c *Client) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	var hash string
+	ambiguous := false
+	err := c.retry(ctx, func() error {
+		err := c.call(ctx, &hash, "eth_sendRawTransaction", []any{EncodeBytes(raw)})
+		var rpcErr *Error
+		if ambiguous && errors.As(err, &rpcErr) {
+			h := sha3.NewLegacyKeccak256()
+			h.Write(raw)
+			if sent := EncodeBytes(h.Sum(nil)); c.hasTransaction(ctx, sent) {
+				hash = sent
+				return nil
+			}
+		}
+		ambiguous = ambiguous || Retryable(err)
+		return err
+	})
+	return hash, err
+}
+
+// hasTransaction reports whether the node knows the transaction, pending or mined
+func (c *Client) hasTransaction(ctx context.Context, hash string) bool {
+	var tx json.RawMessage
+	err := c.Call(ctx, &tx, "eth_getTransactionByHash", hash)
+	return err == nil && len(tx) > 0 && string(tx) != "null"
+}
+
+// Receipt is the part of a transaction receipt the miner reports
+type Receipt struct {
+	TxHash      string `json:"transactionHash"`
+	Status      string `json:"status"`
+	BlockNumber string `json:"blockNumber"`
+	GasUsed     string `json:"gasUsed"`
+}
+
+// Succeeded returns true if the transaction did not revert
+func (r *Receipt) Succeeded() bool {
+	return r.Status == "0x1"
+}
+
+// TransactionReceipt returns the receipt of a mined transaction, or nil while it is pending
+func (c *Client) TransactionReceipt(ctx context.Context, hash string) (*Receipt, error) {
+	var r *Receipt
+	if err := c.Call(ctx, &r, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCode returns the code deployed at addr in the latest block
+func (c *Client) GetCode(ctx context.Context, addr string) ([]byte, error) {
+	var s string
+	if err := c.Call(ctx, &s, "eth_getCode", addr, "latest"); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func (c *Client) quantity(ctx context.Context, method string, params ...any) (uint64, error) {
+	var s string
+	if err := c.Call(ctx, &s, method, params...); err != nil {
+		return 0, err
+	}
+	return ParseUint(s)
+}
+
+// EncodeBytes encodes data as 0x-prefixed hex
+func EncodeBytes(data []byte) string {
+	return "0x" + hex.EncodeToString(data)
+}
+
+// EncodeUint encodes a quantity as minimal 0x-prefixed hex
+func EncodeUint(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+// ParseUint decodes a 0x-prefixed hex quantity
+func ParseUint(s string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	return n, nil
+}
+
+// ParseBig decodes a 0x-prefixed hex quantity of any size
+func ParseBig(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+	return n, nil
+}
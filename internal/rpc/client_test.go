@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// flakyNode answers eth_chainId after failing the first failures requests with status
+func flakyNode(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			http.Error(w, "unavailable", status)
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x539"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestCallRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		retries  int
+		calls    int32
+		wantErr  bool
+	}{
+		{"succeeds after retries", 2, http.StatusBadGateway, 3, 3, false},
+		{"rate limited", 1, http.StatusTooManyRequests, 3, 2, false},
+		{"retries run out", 5, http.StatusServiceUnavailable, 2, 3, true},
+		{"client error is permanent", 1, http.StatusUnauthorized, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyNode(t, tt.failures, tt.status)
+			c := New(srv.URL)
+			c.Retries = tt.retries
+			c.Backoff = time.Millisecond
+
+			id, err := c.ChainID(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChainID() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if err == nil && id != 1337 {
+				t.Errorf("ChainID() = %d, want 1337", id)
+			}
+			if got := calls.Load(); got != tt.calls {
+				t.Errorf("node saw %d requests, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("dial tcp: connection refused"), true},
+		{&StatusError{Code: http.StatusInternalServerError}, true},
+		{&StatusError{Code: http.StatusNotFound}, false},
+		{&Error{Code: 3, Message: "execution reverted"}, false},
+		{context.Canceled, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestSendRawTransactionAmbiguous covers a send whose response is lost: the retry is
+// rejected, and counts as sent only if the node has the transaction
+func TestSendRawTransactionAmbiguous(t *testing.T) {
+	raw := []byte{0xf8, 0x6b, 0x01, 0x02}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(raw)
+	txHash := EncodeBytes(h.Sum(nil))
+
+	tests := []struct {
+		name      string
+		firstLost bool   // the node accepts the first send but never answers it
+		rejection string // error message for every send after the first
+		wantErr   bool
+		sends     int32
+	}{
+		{"first send timed out after acceptance", true, "already known", false, 2},
+		{"mined before the retry", true, "nonce too low", false, 2},
+		{"rejected outright", false, "nonce too low", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sends atomic.Int32
+			var known atomic.Bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req request
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode request: %v", err)
+					return
+				}
+				switch req.Method {
+				case "eth_sendRawTransaction":
+					if sends.Add(1) == 1 && tt.firstLost {
+						known.Store(true)
+						<-r.Context().Done()
+						return
+					}
+					w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "` + tt.rejection + `"}}`))
+				case "eth_getTransactionByHash":
+					if known.Load() && req.Params[0] == txHash {
+						w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": {"hash": "` + txHash + `"}}`))
+						return
+					}
+					w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": null}`))
+				default:
+					t.Errorf("unexpected method %s", req.Method)
+				}
+			}))
+			t.Cleanup(srv.Close)
+			c := New(srv.URL)
+			c.HTTP.Timeout = 50 * time.Millisecond
+			c.Backoff = time.Millisecond
+
+			hash, err := c.SendRawTransaction(context.Background(), raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendRawTransaction() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if err == nil && hash != txHash {
+				t.Errorf("SendRawTransaction() = %s, want %s", hash, txHash)
+			}
+			if got := sends.Load(); got != tt.sends {
+				t.Errorf("node saw %d sends, want %d", got, tt.sends)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/internal/logger"
+	"github.com/screa/erc2470-address-miner/pkg/miner"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// Job statuses
+const (
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusNoMatch = "no_match"
+)
+
+// JobRequest is the JSON body accepted by POST /jobs
+type JobRequest struct {
+	Bytecode        string `json:"bytecode"`
+	ConstructorArgs string `json:"constructor_args,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Suffix          string `json:"suffix,omitempty"`
+	Workers         int    `json:"workers,omitempty"`
+	Count           int    `json:"count,omitempty"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty"` // stop the job after this long (0 = no limit)
+}
+
+// JobStatus is the JSON body returned by GET /jobs/{id}
+type JobStatus struct {
+	ID      string          `json:"id"`
+	Status  string          `json:"status"`
+	Results []*types.Result `json:"results,omitempty"`
+}
+
+type job struct {
+	id     string
+	miner  *miner.Miner
+	status string
+}
+
+// Server runs mining jobs submitted over HTTP
+type Server struct {
+	logger *logger.Logger
+	jobs   map[string]*job
+	mu     sync.RWMutex
+}
+
+// NewServer creates a new job server
+func NewServer(log *logger.Logger) *Server {
+	return &Server{
+		logger: log,
+		jobs:   make(map[string]*job),
+	}
+}
+
+// Handler returns the HTTP handler exposing the job API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", s.handleSubmit)
+	mux.HandleFunc("GET /jobs/{id}", s.handleStatus)
+	return mux
+}
+
+// Close stops all running jobs
+func (s *Server) Close() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, j := range s.jobs {
+		j.miner.Stop()
+	}
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := config.NewConfig()
+	cfg.Bytecode = req.Bytecode
+	cfg.ConstructorArgs = req.ConstructorArgs
+	cfg.Prefix = req.Prefix
+	cfg.Suffix = req.Suffix
+	if req.Workers > 0 {
+		cfg.Workers = req.Workers
+	}
+	if req.Count > 0 {
+		cfg.Count = req.Count
+	}
+	cfg.Timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	if err := validateJob(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		http.Error(w, "failed to allocate job id", http.StatusInternalServerError)
+		return
+	}
+
+	j := &job{
+		id:     id,
+		miner:  miner.NewMiner(cfg, s.logger),
+		status: StatusRunning,
+	}
+	s.mu.Lock()
+	s.jobs[id] = j
+	s.mu.Unlock()
+
+	go s.run(j)
+	s.logger.Printf("Job %s started (%s, %d workers)", id, cfg.GetTargetDescription(), cfg.Workers)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(JobStatus{ID: id, Status: StatusRunning})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	j, ok := s.jobs[r.PathValue("id")]
+	var status string
+	if ok {
+		status = j.status
+	}
+	s.mu.RUnlock()
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	resp := JobStatus{ID: j.id, Status: status}
+	if status != StatusRunning {
+		resp.Results = j.miner.Results()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// run mines a job to completion or until its limits are reached
+func (s *Server) run(j *job) {
+	j.miner.Mine()
+
+	s.mu.Lock()
+	if len(j.miner.Results()) > 0 {
+		j.status = StatusDone
+	} else {
+		j.status = StatusNoMatch
+	}
+	s.mu.Unlock()
+	s.logger.Printf("Job %s finished: %s", j.id, j.status)
+}
+
+// validateJob rejects configurations that NewMiner cannot handle
+func validateJob(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if _, err := cfg.GetBytecode(); err != nil {
+		return err
+	}
+	if cfg.Prefix != "" {
+		if _, err := crypto.HexToAddressBytes(cfg.Prefix); err != nil {
+			return err
+		}
+	}
+	if cfg.Suffix != "" {
+		if _, err := crypto.HexToAddressBytes(cfg.Suffix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newJobID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/logger"
+)
+
+func TestServerJobLifecycle(t *testing.T) {
+	srv := NewServer(logger.NewWriter(io.Discard))
+	defer srv.Close()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	body, _ := json.Marshal(JobRequest{
+		Bytecode: "608060405234801561001057600080fd5b50600436106100365760003560e01c8063",
+		Prefix:   "00",
+		Workers:  2,
+	})
+	resp, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /jobs error = %v", err)
+	}
+	var submitted JobStatus
+	json.NewDecoder(resp.Body).Decode(&submitted)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || submitted.ID == "" {
+		t.Fatalf("POST /jobs = %d %+v, want 202 with a job id", resp.StatusCode, submitted)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err := http.Get(ts.URL + "/jobs/" + submitted.ID)
+		if err != nil {
+			t.Fatalf("GET /jobs/{id} error = %v", err)
+		}
+		var status JobStatus
+		json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+
+		if status.Status == StatusDone {
+			if len(status.Results) != 1 || status.Results[0].Address[2:4] != "00" {
+				t.Errorf("job results = %+v, want one address with prefix 00", status.Results)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not complete in time, last status %q", status.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerRejectsInvalidJob(t *testing.T) {
+	srv := NewServer(logger.NewWriter(io.Discard))
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader([]byte(`{"prefix":"00"}`)))
+	if err != nil {
+		t.Fatalf("POST /jobs error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /jobs without bytecode = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	resp, err = http.Get(ts.URL + "/jobs/unknown")
+	if err != nil {
+		t.Fatalf("GET /jobs/unknown error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /jobs/unknown = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// Defaults for Client
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultRetryDelay = 2 * time.Second
+)
+
+// Client POSTs mining results as JSON to a URL, retrying once on failure
+type Client struct {
+	URL        string
+	HTTP       *http.Client
+	RetryDelay time.Duration // wait before the retry
+}
+
+// New creates a client for url with the default timeout and retry delay
+func New(url string) *Client {
+	return &Client{
+		URL:        url,
+		HTTP:       &http.Client{Timeout: DefaultTimeout},
+		RetryDelay: DefaultRetryDelay,
+	}
+}
+
+// Send posts a types.OutputEvent of type match carrying result. A transport error or a
+// non-2xx status is retried once; the error of the last attempt is returned.
+func (c *Client) Send(result *types.Result) error {
+	body, err := json.Marshal(types.OutputEvent{
+		Type:      types.OutputMatch,
+		Timestamp: time.Now().UTC(),
+		Result:    result,
+	})
+	if err != nil {
+		return err
+	}
+	if err = c.post(body); err == nil {
+		return nil
+	}
+	time.Sleep(c.RetryDelay)
+	return c.post(body)
+}
+
+func (c *Client) post(body []byte) error {
+	resp, err := c.HTTP.Post(c.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+func TestSendRetriesOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32 // requests answered with 500 before succeeding
+		wantErr  bool
+		wantHits int32
+	}{
+		{"first attempt succeeds", 0, false, 1},
+		{"retry succeeds", 1, false, 2},
+		{"retry fails", 2, true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			var got types.OutputEvent
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("got %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+				}
+				if atomic.AddInt32(&hits, 1) <= tt.failures {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode payload: %v", err)
+				}
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL)
+			c.RetryDelay = 0
+			result := &types.Result{Salt: "01", Address: "0x0000002DBE996066c3F322753B4AB7F245C13981", Attempts: 42}
+			err := c.Send(result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if hits != tt.wantHits {
+				t.Errorf("server saw %d requests, want %d", hits, tt.wantHits)
+			}
+			if !tt.wantErr && (got.Type != types.OutputMatch || got.Result == nil || got.Result.Address != result.Address) {
+				t.Errorf("payload = %+v, want a match event for %s", got, result.Address)
+			}
+		})
+	}
+}
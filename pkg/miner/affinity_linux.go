@@ -0,0 +1,29 @@
+//go:build linux
+
+package miner
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// pinWorker locks the calling goroutine to its OS thread and restricts that thread to one
+// CPU, taken round-robin by workerID from the CPUs the process may run on. The thread is
+// never unlocked, so it exits with the worker instead of returning to the scheduler pinned.
+func pinWorker(workerID int) error {
+	runtime.LockOSThread()
+	var allowed unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &allowed); err != nil {
+		return err
+	}
+	var cpus []int
+	for cpu := 0; len(cpus) < allowed.Count(); cpu++ {
+		if allowed.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	var set unix.CPUSet
+	set.Set(cpus[workerID%len(cpus)])
+	return unix.SchedSetaffinity(0, &set)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package miner
+
+import "errors"
+
+// pinWorker is unsupported off Linux; workers run unpinned
+func pinWorker(workerID int) error {
+	return errors.New("CPU pinning is only supported on Linux")
+}
@@ -0,0 +1,98 @@
+package miner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/worker"
+)
+
+// WorkerRate is the hash rate measured for one worker count while auto-tuning
+type WorkerRate struct {
+	Workers int
+	Rate    float64 // attempts per second
+}
+
+// WorkerCandidates returns the worker counts tried by --workers auto: half, all and twice the CPUs
+func WorkerCandidates(cpus int) []int {
+	var counts []int
+	for _, n := range []int{cpus / 2, cpus, cpus * 2} {
+		if n >= 1 && (len(counts) == 0 || counts[len(counts)-1] != n) {
+			counts = append(counts, n)
+		}
+	}
+	return counts
+}
+
+// PickWorkers measures each worker count and returns the fastest along with every rate
+// measured. Ties keep the smaller count.
+func PickWorkers(counts []int, measure func(workers int) float64) (int, []WorkerRate) {
+	rates := make([]WorkerRate, 0, len(counts))
+	best := WorkerRate{}
+	for _, n := range counts {
+		r := WorkerRate{Workers: n, Rate: measure(n)}
+		rates = append(rates, r)
+		if best.Workers == 0 || r.Rate > best.Rate {
+			best = r
+		}
+	}
+	return best.Workers, rates
+}
+
+// AutoTuneWorkers benchmarks each count in WorkerCandidates(cpus) for roughly per and sets
+// the configured worker count to the fastest. Call it before Mine. Cancelling ctx ends the
+// benchmark early, leaving the worker count unchanged, and returns ctx's error.
+func (m *Miner) AutoTuneWorkers(ctx context.Context, cpus int, per time.Duration) ([]WorkerRate, error) {
+	best, rates := PickWorkers(WorkerCandidates(cpus), func(n int) float64 {
+		return m.measureRate(ctx, n, per)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.config.Workers = best
+	return rates, nil
+}
+
+// measureRate runs n workers over the mining hot path for d and returns attempts per second.
+// Attempts are counted separately, so the run's totals are untouched.
+func (m *Miner) measureRate(ctx context.Context, n int, d time.Duration) float64 {
+	if ctx.Err() != nil {
+		return 0
+	}
+	var attempts int64
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			w := worker.NewWorker(m.workerConfig, &attempts)
+			if m.workerConfig.HDSalts != nil {
+				w.SetHDCursor(m.config.DerivationIndex+uint32(id), uint32(n))
+			}
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					for j := 0; j < 1000; j++ {
+						w.GenerateAddress()
+					}
+					w.Flush()
+				}
+			}
+		}(i)
+	}
+	timer := time.NewTimer(d)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+	}
+	close(stop)
+	wg.Wait()
+	return float64(atomic.LoadInt64(&attempts)) / time.Since(start).Seconds()
+}
@@ -0,0 +1,53 @@
+package miner
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Salt collision sampling for --debug-collisions
+const (
+	CollisionSampleEvery = 64      // each worker samples one salt in this many
+	CollisionSampleSize  = 1 << 16 // salts remembered before the oldest are forgotten
+)
+
+// saltSampler remembers a bounded window of sampled salts and counts repeats. Random salts
+// should never repeat, so any collision means two workers (or runs) share generator state.
+type saltSampler struct {
+	mu         sync.Mutex
+	seen       map[[32]byte]struct{}
+	ring       [][32]byte // insertion order, for evicting the oldest salt once full
+	next       int
+	collisions int64
+}
+
+func newSaltSampler(size int) *saltSampler {
+	return &saltSampler{seen: make(map[[32]byte]struct{}, size), ring: make([][32]byte, 0, size)}
+}
+
+// add records a salt and returns true if it collides with one already in the window
+func (s *saltSampler) add(salt [32]byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[salt]; ok {
+		atomic.AddInt64(&s.collisions, 1)
+		return true
+	}
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, salt)
+	} else {
+		delete(s.seen, s.ring[s.next])
+		s.ring[s.next] = salt
+		s.next = (s.next + 1) % len(s.ring)
+	}
+	s.seen[salt] = struct{}{}
+	return false
+}
+
+// Collisions returns the number of sampled salts seen twice, or 0 without --debug-collisions
+func (m *Miner) Collisions() int64 {
+	if m.sampler == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&m.sampler.collisions)
+}
@@ -0,0 +1,69 @@
+package miner
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// heldMatch is a match held by --deterministic-workers until every earlier salt is tried.
+// pos orders salts across workers: worker i's k-th salt is at k*Workers+i.
+type heldMatch struct {
+	pos    uint64
+	result *types.WorkerResult
+}
+
+// deterministicMerge collects matches so the run reports those at the lowest salt positions,
+// whichever worker reaches them first
+type deterministicMerge struct {
+	mu    sync.Mutex
+	held  []heldMatch   // sorted by pos
+	bound atomic.Uint64 // workers stop past this position; MaxUint64 until Count matches are held
+}
+
+func newDeterministicMerge() *deterministicMerge {
+	d := &deterministicMerge{}
+	d.bound.Store(math.MaxUint64)
+	return d
+}
+
+// offer holds a match and, once count distinct addresses are held, lowers the bound to the
+// position of the last one needed
+func (d *deterministicMerge) offer(pos uint64, result *types.WorkerResult, count int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := sort.Search(len(d.held), func(i int) bool { return d.held[i].pos > pos })
+	d.held = append(d.held, heldMatch{})
+	copy(d.held[i+1:], d.held[i:])
+	d.held[i] = heldMatch{pos: pos, result: result}
+
+	seen := make(map[[20]byte]struct{}, count)
+	for _, h := range d.held {
+		seen[h.result.AddressBytes] = struct{}{}
+		if len(seen) == count {
+			d.bound.Store(h.pos)
+			return
+		}
+	}
+}
+
+// done reports whether a worker whose next salt is at pos can stop: every match it could
+// still find comes after the ones already held
+func (d *deterministicMerge) done(pos uint64) bool {
+	return pos > d.bound.Load()
+}
+
+// resolve accepts the held matches in salt order once the workers have stopped
+func (m *Miner) resolveDeterministic() {
+	m.merge.mu.Lock()
+	held := m.merge.held
+	m.merge.mu.Unlock()
+	for _, h := range held {
+		if m.acceptMatch(h.result) {
+			return
+		}
+	}
+}
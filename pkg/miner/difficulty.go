@@ -0,0 +1,103 @@
+package miner
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+)
+
+// ExpectedAttempts returns how many salts a run of cfg is expected to try before its first
+// match, for embedders showing difficulty or an ETA without the CLI. It counts the nibbles
+// fixed by prefix, suffix, template and target once where they overlap, plus palindromes,
+// --all-same, --low-zero-bits and --prefix-bits, across every init code, factory and
+// --target-file address (see config.Config.ExpectedAttempts). The count is a big.Int because a
+// full 40-character target needs around 2^160 attempts. Returns nil in pure scoring modes,
+// which never finish on a match.
+func ExpectedAttempts(cfg *config.Config) *big.Int {
+	return cfg.ExpectedAttempts()
+}
+
+// Outlook estimates what is left of a run that has not matched yet, see Miner.Outlook
+type Outlook struct {
+	Attempts float64       // expected further attempts to a match; 0 when unknown
+	ETA      time.Duration // Attempts at the current rate; 0 when unknown
+	Chance   float64       // probability that a match is left to find: 1 when unbounded
+
+	Bounded   bool   // sequential with --salt-end or --salt-bytes
+	Remaining uint64 // salts left in a bounded keyspace
+}
+
+// estimate computes the outlook after searched attempts without a match, where each salt
+// matches with probability 1/expected. Random salts are memoryless, so the attempts already
+// spent change nothing. A bounded keyspace shrinks as it is searched: the chance that a match
+// remains is 1-(1-p)^r for the r salts left, and the expected attempts to it, given that it
+// exists, are those of a geometric distribution truncated at r, falling from 1/p towards r/2
+// as the remaining space becomes small next to 1/p.
+func estimate(expected float64, searched int64, keyspace uint64, bounded bool) Outlook {
+	if expected <= 0 || math.IsInf(expected, 0) {
+		return Outlook{Bounded: bounded}
+	}
+	if !bounded {
+		return Outlook{Attempts: expected, Chance: 1}
+	}
+	o := Outlook{Bounded: true}
+	if searched >= 0 && uint64(searched) < keyspace {
+		o.Remaining = keyspace - uint64(searched)
+	}
+	if o.Remaining == 0 {
+		return o
+	}
+	p, r := 1/expected, float64(o.Remaining)
+	if p >= 1 {
+		return Outlook{Attempts: 1, Chance: 1, Bounded: true, Remaining: o.Remaining}
+	}
+	// 1-(1-p)^r, accurate when p*r is tiny
+	o.Chance = -math.Expm1(r * math.Log1p(-p))
+	missAll := 1 - o.Chance
+	if o.Chance < 1e-9 {
+		// Too unlikely for the closed form to be stable: a lone match is uniform over the rest
+		o.Attempts = (r + 1) / 2
+	} else {
+		o.Attempts = 1/p - r*missAll/o.Chance
+	}
+	return o
+}
+
+// Outlook returns the estimate for the rest of the run given the attempts made so far
+// without a match, and the ETA at the current hash rate
+func (m *Miner) Outlook() Outlook {
+	var expected float64
+	if n := ExpectedAttempts(m.config); n != nil {
+		expected, _ = new(big.Float).SetInt(n).Float64()
+	}
+	stats := m.Stats()
+	o := estimate(expected, stats.Attempts, m.keyspace, m.bounded)
+	if o.Attempts > 0 && stats.Rate > 0 {
+		if secs := o.Attempts / stats.Rate; secs < math.MaxInt64/float64(time.Second) {
+			o.ETA = time.Duration(secs * float64(time.Second))
+		}
+	}
+	return o
+}
+
+// describe renders the outlook for a progress line, or "" when nothing is known
+func (o Outlook) describe() string {
+	switch {
+	case o.Bounded && o.Remaining > 0 && o.Chance > 0:
+		return fmt.Sprintf(", %.2g%% chance of a match in the %d salts left%s", 100*o.Chance, o.Remaining, o.eta(" if so"))
+	case !o.Bounded && o.Attempts > 0:
+		return o.eta("")
+	}
+	return ""
+}
+
+// eta renders the ETA, or "" when the rate is not known yet
+func (o Outlook) eta(qualifier string) string {
+	if o.ETA <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(", expected in ~%v%s", o.ETA.Round(time.Second), qualifier)
+}
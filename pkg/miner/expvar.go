@@ -0,0 +1,89 @@
+package miner
+
+import (
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// expvar variables are process-global, so they are published once and read
+// whichever miner most recently started serving them
+var (
+	expvarOnce  sync.Once
+	expvarMiner atomic.Pointer[Miner]
+)
+
+// publishExpvar registers the attempts, rate and bestAddress variables
+func publishExpvar() {
+	expvar.Publish("attempts", expvar.Func(func() any {
+		if m := expvarMiner.Load(); m != nil {
+			return m.Attempts()
+		}
+		return int64(0)
+	}))
+	expvar.Publish("rate", expvar.Func(func() any {
+		if m := expvarMiner.Load(); m != nil {
+			return m.currentRate()
+		}
+		return 0.0
+	}))
+	expvar.Publish("collisions", expvar.Func(func() any {
+		if m := expvarMiner.Load(); m != nil {
+			return m.Collisions()
+		}
+		return int64(0)
+	}))
+	expvar.Publish("bestAddress", expvar.Func(func() any {
+		if m := expvarMiner.Load(); m != nil {
+			if best := m.GetBestResult(); best != nil {
+				return best.Address
+			}
+		}
+		return ""
+	}))
+}
+
+// currentRate returns the average hash rate since Mine started, or 0 before it has
+func (m *Miner) currentRate() float64 {
+	m.mu.RLock()
+	start := m.start
+	m.mu.RUnlock()
+	if start.IsZero() {
+		return 0
+	}
+	_, rate := m.rate(start)
+	return rate
+}
+
+// ServeExpvar publishes the miner's live statistics through expvar and serves
+// /debug/vars on addr. The server shuts down when Mine returns. It returns the
+// address actually listened on, which differs from addr for port 0.
+func (m *Miner) ServeExpvar(addr string) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	expvarOnce.Do(publishExpvar)
+	expvarMiner.Store(m)
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	srv := &http.Server{Handler: mux}
+	m.expvarServer = srv
+	go srv.Serve(ln)
+	return ln.Addr().String(), nil
+}
+
+// closeExpvar shuts the expvar server down, if one was started
+func (m *Miner) closeExpvar() {
+	if m.expvarServer == nil {
+		return
+	}
+	if err := m.expvarServer.Shutdown(context.Background()); err != nil {
+		m.logger.Printf("Failed to stop expvar server: %v", err)
+	}
+	expvarMiner.CompareAndSwap(m, nil)
+}
@@ -0,0 +1,57 @@
+package miner
+
+import (
+	"math/big"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+)
+
+// sequentialKeyspace counts the salts from start through --salt-end, or through the last salt
+// --salt-bytes allows. It reports false when there is no end or the span does not fit in a
+// uint64, leaving the search unbounded.
+func sequentialKeyspace(cfg *config.Config, start [32]byte) (uint64, bool) {
+	if cfg.SaltMode != config.SaltModeSequential {
+		return 0, false
+	}
+	end, ok := cfg.LastSalt()
+	if cfg.SaltEnd != "" {
+		var err error
+		end, err = cfg.ParseSaltInput(cfg.SaltEnd)
+		if err != nil {
+			panic("invalid salt end: " + err.Error())
+		}
+	} else if !ok {
+		return 0, false
+	}
+	n := new(big.Int).Sub(new(big.Int).SetBytes(end[:]), new(big.Int).SetBytes(start[:]))
+	n.Add(n, big.NewInt(1))
+	if n.Sign() < 0 {
+		return 0, true
+	}
+	if !n.IsUint64() {
+		return 0, false
+	}
+	return n.Uint64(), true
+}
+
+// workerKeyspace returns how many salts of a bounded keyspace the worker tries: worker i
+// takes start+i, start+i+N, ... up to the end
+func (m *Miner) workerKeyspace(workerID int) uint64 {
+	id, workers := uint64(workerID), uint64(m.config.Workers)
+	if !m.bounded || id >= m.keyspace {
+		return 0
+	}
+	return (m.keyspace-1-id)/workers + 1
+}
+
+// Keyspace returns the number of salts a sequential search with --salt-end or --salt-bytes
+// covers, and false when the search is unbounded
+func (m *Miner) Keyspace() (uint64, bool) {
+	return m.keyspace, m.bounded
+}
+
+// Exhausted reports whether every salt of a bounded keyspace has been tried. A run that
+// ends exhausted without a match proves no salt in the range produces one.
+func (m *Miner) Exhausted() bool {
+	return m.bounded && int(m.exhausted.Load()) == m.config.Workers
+}
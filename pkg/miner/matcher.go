@@ -0,0 +1,52 @@
+package miner
+
+import (
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// Matcher is a second-stage filter over candidates that already meet the run's criteria,
+// such as a --matcher-cmd program. Filter reports, for each checksummed address, whether
+// it is accepted; an error stops the run.
+type Matcher interface {
+	Filter(addresses []string) ([]bool, error)
+}
+
+// SetMatcher passes every match through f before it is accepted. Workers call it once per
+// batch with the batch's matches, so the criteria should be selective enough that calls
+// are rare.
+func (m *Miner) SetMatcher(f Matcher) {
+	m.matcher = f
+}
+
+// MatcherErr returns the error that stopped the run if the matcher failed
+func (m *Miner) MatcherErr() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.matcherErr
+}
+
+// filterMatches passes a batch's matches through the matcher and accepts those it approves
+func (m *Miner) filterMatches(candidates []*types.WorkerResult) {
+	addrs := make([]string, len(candidates))
+	for i, c := range candidates {
+		addrs[i] = c.Address
+	}
+	accepted, err := m.matcher.Filter(addrs)
+	if err != nil {
+		m.mu.Lock()
+		if m.matcherErr == nil {
+			m.matcherErr = err
+			m.logger.Printf("Matcher failed: %v", err)
+		}
+		m.mu.Unlock()
+		m.Stop()
+		return
+	}
+	m.matcherSeen.Add(int64(len(candidates)))
+	for i, ok := range accepted {
+		if ok {
+			m.matcherAccepted.Add(1)
+			m.acceptMatch(candidates[i])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package miner
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// memoryCheckInterval is how often --max-memory samples the heap
+const memoryCheckInterval = time.Second
+
+// memoryGuard keeps a long run under --max-memory by shedding what the miner retains: the
+// --top-k heaps shrink and the result stream pauses while the heap is near the limit
+type memoryGuard struct {
+	limit    uint64        // bytes; zero disables the guard
+	readHeap func() uint64 // live heap in bytes; runtime.ReadMemStats outside tests
+
+	topK         atomic.Int64 // candidates each worker may keep, at most --top-k
+	retained     atomic.Int64 // candidates held across all worker heaps
+	streamPaused atomic.Bool  // drop stream candidates until the heap falls back
+}
+
+// heapAlloc reads the live heap size from the runtime
+func heapAlloc() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+// watchMemory checks the heap every memoryCheckInterval until the miner stops
+func (m *Miner) watchMemory() {
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.checkMemory()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// checkMemory trims retained data once the heap reaches 90% of the limit. Each trim halves
+// the candidates the workers hold, so repeated pressure keeps shrinking them; the stream
+// resumes once the heap is back under 75%.
+func (m *Miner) checkMemory() {
+	g := &m.memory
+	heap := g.readHeap()
+	if heap < g.limit/10*9 {
+		if heap < g.limit/4*3 && g.streamPaused.CompareAndSwap(true, false) {
+			m.logger.Printf("Memory use %d MB is back under --max-memory %d MB: resuming the result stream", heap>>20, g.limit>>20)
+		}
+		return
+	}
+	if k := g.topK.Load(); k > 1 {
+		perWorker := g.retained.Load() / int64(m.config.Workers)
+		if trimmed := max(1, min(k, perWorker)/2); trimmed < k {
+			g.topK.Store(trimmed)
+			m.trimTopResults(int(trimmed))
+			m.logger.Printf("Memory use %d MB is near --max-memory %d MB: keeping the best %d candidates per worker instead of %d",
+				heap>>20, g.limit>>20, trimmed, k)
+		}
+	}
+	if m.stream.Load() != nil && !g.streamPaused.Swap(true) {
+		m.logger.Printf("Memory use %d MB is near --max-memory %d MB: pausing the result stream", heap>>20, g.limit>>20)
+	}
+}
+
+// keptTopK returns how many candidates each --top-k heap may hold: --top-k, or less once
+// --max-memory has trimmed it
+func (m *Miner) keptTopK() int {
+	if m.memory.limit == 0 {
+		return m.config.TopK
+	}
+	return int(m.memory.topK.Load())
+}
+
+// trimTopResults drops merged --top-k results beyond the best k
+func (m *Miner) trimTopResults(k int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.topResults) > k {
+		m.topResults = append([]candidate(nil), m.topResults[:k]...)
+	}
+}
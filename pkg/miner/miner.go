@@ -1,12 +1,23 @@
 package miner
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/bits"
+	"net/http"
+	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/screa/erc2470-address-miner/internal/audit"
+	"github.com/screa/erc2470-address-miner/internal/checkpoint"
 	"github.com/screa/erc2470-address-miner/internal/config"
 	"github.com/screa/erc2470-address-miner/internal/crypto"
 	"github.com/screa/erc2470-address-miner/internal/logger"
@@ -20,12 +31,73 @@ type Miner struct {
 	logger          *logger.Logger
 	attempts        int64
 	bestResult      *types.Result
-	bestResultBytes [20]byte // for fast isBetter comparison
+	bestResultBytes [20]byte  // for fast isBetter comparison
+	bestScore       int       // score of the best result in scoring modes
+	words           []string  // hex words scored in --words mode, nil otherwise
+	closestTo       *[20]byte // target address in --closest-to mode, nil otherwise
+	results         []*types.Result
+	found           map[[20]byte]struct{} // distinct matched addresses, guarded by mu
+	start           time.Time
+	finished        time.Time // when the workers stopped, zero while mining; guarded by mu
 	mu              sync.RWMutex
 	done            chan bool
+	stopping        atomic.Bool // set by Stop; workers poll it instead of selecting on done
 	wg              sync.WaitGroup
 	once            sync.Once
+	seedWarning     sync.Once // logs the entropy fallback warning once per run
+	pinWarning      sync.Once // logs a --pin-cpus failure once per run
 	workerConfig    *types.WorkerConfig
+	bestLog         *json.Encoder // optional JSON-lines sink for best result improvements
+	resultOut       *json.Encoder // optional JSON-lines sink for bests, matches and the final result
+	saltStart       [32]byte      // first salt in sequential mode
+	resumeBase      *[32]byte     // parsed --resume-from salt, nil when starting at zero
+	audit           *audit.Log    // optional near-miss audit trail
+	rateCSV         *csv.Writer   // optional timestamp,attempts,rate trail written each progress tick
+	checkpointPath  string        // optional checkpoint file rewritten each progress tick
+	statePath       string        // optional random-mode state file rewritten each progress tick, see SetStateFile
+	outputDir       string        // optional directory receiving one JSON file per match
+	priorAttempts   int64         // attempts made by earlier runs resumed from a checkpoint
+	progress        []int64       // per-worker attempts in completed batches, for the resume point
+	topResults      []candidate   // merged --top-k candidates, best first, guarded by mu
+	now             func() time.Time
+	sleep           func(time.Duration) // --throttle pauses between batches; sleepUnlessStopped outside tests
+
+	entropyFile io.Closer // --entropy file source, closed when Mine returns
+	runStamp    uint64    // os-hybrid salts: nanosecond timestamp distinguishing this run
+
+	expvarServer *http.Server // optional /debug/vars server, see ServeExpvar
+
+	tierAttempts []int64 // attempts at which each prefix length was first reached, indexed by length, guarded by mu
+	tierReached  int     // longest prefix length reached so far, guarded by mu
+	bestChanges  int     // times the best result improved, guarded by mu
+
+	partial *types.Result // --best-effort: longest partial prefix match, guarded by mu
+	kept    [20]byte      // --keep-searching: address of the match held in results, guarded by mu
+
+	keyspace  uint64       // salts from saltStart through --salt-end, when bounded
+	bounded   bool         // sequential mode with a --salt-end that leaves at most 2^64 salts
+	exhausted atomic.Int32 // workers that ran out of keyspace
+
+	sampling progressSampling // see shouldLogProgress
+
+	sampler          *saltSampler // --debug-collisions salt window, nil when off
+	collisionWarning sync.Once    // logs the first collision
+
+	pause pauseState // see Pause
+
+	memory memoryGuard // --max-memory, see checkMemory
+
+	niceSet *crypto.NiceSet // family matched and scored in --nice-set mode, nil otherwise
+
+	merge *deterministicMerge // --deterministic-workers: matches held until the workers stop, nil otherwise
+
+	matcher         Matcher      // optional second-stage filter over matches, see SetMatcher
+	matcherErr      error        // why the matcher stopped the run, guarded by mu
+	matcherSeen     atomic.Int64 // matches passed to the matcher
+	matcherAccepted atomic.Int64 // matches the matcher accepted
+
+	stream        atomic.Pointer[resultStream] // optional subscriber to every candidate, see Stream
+	streamDropped int64                        // candidates dropped on a full stream buffer
 }
 
 // NewMiner creates a new miner instance
@@ -33,20 +105,50 @@ func NewMiner(cfg *config.Config, log *logger.Logger) *Miner {
 	if cfg.Workers <= 0 {
 		cfg.Workers = runtime.NumCPU()
 	}
+	if cfg.Count <= 0 {
+		cfg.Count = 1
+	}
 
 	// Pre-compute initcode and its hash for performance
-	initcode, err := cfg.GetBytecode()
-	if err != nil {
-		panic("bytecode not available: " + err.Error())
-	}
+	var initcode, initcodeHash []byte
+	var extraHashes [][]byte
+	var err error
+	if cfg.InitCodeHash != "" {
+		// Only the hash is known; there is no init code to keep
+		initcodeHash, err = cfg.GetInitCodeHash()
+		if err != nil {
+			panic("invalid init code hash: " + err.Error())
+		}
+	} else {
+		initcodes, err := cfg.GetBytecodes()
+		if err != nil {
+			panic("bytecode not available: " + err.Error())
+		}
 
-	initcodeHash := crypto.Keccak256(initcode)
+		initcode = initcodes[0]
+		initcodeHash = crypto.Keccak256(initcode)
 
-	// Pre-compute factory address bytes
-	factoryBytes, err := crypto.MustAddressBytes(crypto.FactoryAddress)
+		// Additional init codes must match under the same salt
+		for _, code := range initcodes[1:] {
+			extraHashes = append(extraHashes, crypto.Keccak256(code))
+		}
+	}
+
+	// Pre-compute factory address bytes; the first factory is primary, the rest are tried in turn
+	factories := cfg.GetFactories()
+	factoryBytes, err := crypto.MustAddressBytes(factories[0].Address)
 	if err != nil {
 		panic("invalid factory address: " + err.Error())
 	}
+	var extraFactories []types.FactoryTarget
+	for _, f := range factories[1:] {
+		b, err := crypto.MustAddressBytes(f.Address)
+		if err != nil {
+			panic("invalid factory address: " + err.Error())
+		}
+		prefix := crypto.Create2PrefixFor(b)
+		extraFactories = append(extraFactories, types.FactoryTarget{Address: f.Address, Create2Prefix: prefix[:], CreateX: f.CreateX})
+	}
 
 	// Pre-decode prefix/suffix for fast byte-level matching
 	var prefixBytes, suffixBytes []byte
@@ -56,54 +158,359 @@ func NewMiner(cfg *config.Config, log *logger.Logger) *Miner {
 			panic("invalid prefix: " + err.Error())
 		}
 	}
-	if cfg.Suffix != "" {
-		suffixBytes, err = crypto.HexToAddressBytes(cfg.Suffix)
+	suffixOdd := false
+	if suffix := cfg.GetSuffix(); suffix != "" {
+		// An odd-length suffix is padded with a leading nibble the worker ignores
+		if len(suffix)%2 != 0 {
+			suffix = "0" + suffix
+			suffixOdd = true
+		}
+		suffixBytes, err = crypto.HexToAddressBytes(suffix)
 		if err != nil {
 			panic("invalid suffix: " + err.Error())
 		}
 	}
+	// Resolve the keccak backend, benchmarking the candidates when asked to choose
+	// The workers hash from the prefix-absorbed sponge only with a single factory and init code
+	incremental := len(extraFactories) == 0 && len(extraHashes) == 0
+	if cfg.KeccakBackend == crypto.KeccakAuto {
+		cfg.KeccakBackend = crypto.FastestKeccakBackend(200*time.Millisecond, incremental)
+	}
+	newHasher, err := crypto.KeccakHasherFactory(cfg.KeccakBackend)
+	if err != nil {
+		panic("invalid keccak backend: " + err.Error())
+	}
+
+	var hdSalts *crypto.HDSaltDeriver
+	if cfg.SaltMode == config.SaltModeHD {
+		hdSalts, err = cfg.GetHDSaltDeriver()
+		if err != nil {
+			panic("invalid mnemonic: " + err.Error())
+		}
+	}
+
+	var templateMask, templateValue []byte
+	if cfg.Template != "" {
+		templateMask, templateValue, err = crypto.CompileTemplate(cfg.Template)
+		if err != nil {
+			panic("invalid template: " + err.Error())
+		}
+	}
+	var matchExpr *crypto.MatchExpr
+	if cfg.MatchExpr != "" {
+		matchExpr, err = crypto.ParseMatchExpr(cfg.MatchExpr)
+		if err != nil {
+			panic("invalid match expression: " + err.Error())
+		}
+	}
+	var numericProperty *crypto.MatchExpr
+	if cfg.NumericProperty != "" {
+		numericProperty, err = crypto.ParseNumericProperty(cfg.NumericProperty)
+		if err != nil {
+			panic("invalid numeric property: " + err.Error())
+		}
+	}
+	var niceSet *crypto.NiceSet
+	if cfg.NiceSet != "" {
+		var ok bool
+		if niceSet, ok = crypto.LookupNiceSet(cfg.NiceSet); !ok {
+			panic("unknown nice set: " + cfg.NiceSet)
+		}
+	}
+	var targetBytes []byte
+	if cfg.Target != "" {
+		targetBytes, err = crypto.MustAddressBytes(cfg.Target)
+		if err != nil {
+			panic("invalid target: " + err.Error())
+		}
+	}
+	targetSet, err := cfg.GetTargetSet()
+	if err != nil {
+		panic("invalid target file: " + err.Error())
+	}
 
-	prefix21 := crypto.Create2PrefixBytes()
+	var alignedPrefix []byte
+	if cfg.PrefixBytes != "" {
+		alignedPrefix, err = cfg.GetPrefixBytes()
+		if err != nil {
+			panic("invalid prefix bytes: " + err.Error())
+		}
+	}
+
+	var prefixBitsOf []byte
+	if cfg.PrefixBits > 0 {
+		prefixBitsOf, err = cfg.GetPrefixBitsPattern()
+		if err != nil {
+			panic("invalid prefix bits: " + err.Error())
+		}
+	}
+
+	prefix21 := crypto.Create2PrefixFor(factoryBytes)
 	workerConfig := &types.WorkerConfig{
 		Initcode:      initcode,
 		InitcodeHash:  initcodeHash,
 		FactoryBytes:  factoryBytes,
 		Prefix:        cfg.Prefix,
-		Suffix:        cfg.Suffix,
+		Suffix:        cfg.GetSuffix(),
 		Verbose:       cfg.Verbose,
 		PrefixBytes:   prefixBytes,
+		PrefixOffset:  cfg.PrefixOffset,
 		SuffixBytes:   suffixBytes,
+		SuffixOdd:     suffixOdd,
+		TargetBytes:   targetBytes,
+		TargetSet:     targetSet,
+		TemplateMask:  templateMask,
+		TemplateValue: templateValue,
+		Palindrome:    cfg.IsPalindrome(),
+		PalindromeCS:  cfg.PalindromeChecksum,
+		MinRun:        cfg.Repeating,
+		LowZeroBits:   cfg.LowZeroBits,
+		PrefixBits:    cfg.PrefixBits,
+		PrefixBitsOf:  prefixBitsOf,
+		AllSame:       cfg.AllSame,
+		NewHasher:     newHasher,
+		HDSalts:       hdSalts,
 		Create2Prefix: prefix21[:],
 		Create2Suffix: initcodeHash,
+		ExtraSuffixes: extraHashes,
+		MatchExpr:     matchExpr,
+		SaltLabel:     []byte(cfg.SaltLabel),
+		TrackTiers:    (cfg.Verbose || cfg.BestEffort) && len(prefixBytes) > 0 && !cfg.TracksBest(),
+	}
+	// The in-repo sponge gains from never re-absorbing the prefix; x-crypto's assembly
+	// permutation is faster still over the full preimage
+	workerConfig.AlignedPrefix = alignedPrefix
+	workerConfig.NumericProperty = numericProperty
+	workerConfig.NiceSet = niceSet
+	workerConfig.SaltBytes = cfg.SaltBytes
+	workerConfig.IncrementalKeccak = cfg.KeccakBackend == crypto.KeccakGeneric && incremental
+
+	if len(extraFactories) > 0 {
+		workerConfig.FactoryAddress = factories[0].Address
+		workerConfig.ExtraFactories = extraFactories
+	}
+
+	// CreateX derives the CREATE2 salt from the user salt via its _guard rules
+	if cfg.UsesCreateX() {
+		guard, err := crypto.ParseCreateXGuard(cfg.CreateXGuard)
+		if err != nil {
+			panic("invalid CreateX guard: " + err.Error())
+		}
+		workerConfig.UseCreateX = factories[0].CreateX
+		workerConfig.CreateXGuard = guard
+		workerConfig.ChainID = cfg.ChainID
+		if guard == crypto.GuardMsgSender {
+			workerConfig.CreateXSender, err = crypto.MustAddressBytes(cfg.CreateXSender)
+			if err != nil {
+				panic("invalid CreateX sender: " + err.Error())
+			}
+		}
 	}
 
-	return &Miner{
+	// Random salts are seeded from crypto/rand unless another entropy source is configured
+	var entropyFile *os.File
+	if cfg.Entropy == config.EntropyFile {
+		entropyFile, err = os.Open(cfg.EntropyFile)
+		if err != nil {
+			panic("invalid entropy file: " + err.Error())
+		}
+		workerConfig.Entropy = &lockedReader{r: entropyFile}
+	}
+
+	// Sequential mode starts just after the resume point, or at zero
+	var saltStart [32]byte
+	var resumeBase *[32]byte
+	if cfg.ResumeFrom != "" {
+		saltStart, err = cfg.ParseSaltInput(cfg.ResumeFrom)
+		if err != nil {
+			panic("invalid resume salt: " + err.Error())
+		}
+		base := saltStart
+		resumeBase = &base
+		crypto.AddToSalt(&saltStart, 1)
+	}
+	keyspace, bounded := sequentialKeyspace(cfg, saltStart)
+
+	var words []string
+	if cfg.Words {
+		words, err = cfg.GetWords()
+		if err != nil {
+			panic("invalid word list: " + err.Error())
+		}
+	}
+
+	var closestTo *[20]byte
+	if cfg.ClosestTo != "" {
+		addr, err := crypto.MustAddressBytes(cfg.ClosestTo)
+		if err != nil {
+			panic("invalid closest-to address: " + err.Error())
+		}
+		closestTo = (*[20]byte)(addr)
+	}
+
+	m := &Miner{
 		config:       cfg,
 		logger:       log,
+		found:        make(map[[20]byte]struct{}),
 		done:         make(chan bool),
 		workerConfig: workerConfig,
+		saltStart:    saltStart,
+		resumeBase:   resumeBase,
+		keyspace:     keyspace,
+		bounded:      bounded,
+		words:        words,
+		closestTo:    closestTo,
+		niceSet:      niceSet,
+		now:          time.Now,
+		runStamp:     uint64(time.Now().UnixNano()),
+	}
+	if entropyFile != nil {
+		m.entropyFile = entropyFile
+	}
+	if cfg.DebugCollisions {
+		m.sampler = newSaltSampler(CollisionSampleSize)
+	}
+	m.pause.cond = sync.NewCond(&m.pause.mu)
+	m.sleep = m.sleepUnlessStopped
+	m.memory.limit = uint64(cfg.MaxMemory) << 20
+	m.memory.readHeap = heapAlloc
+	m.memory.topK.Store(int64(cfg.TopK))
+	if cfg.DeterministicWorkers {
+		m.merge = newDeterministicMerge()
+	}
+	if n := ExpectedAttempts(cfg); n != nil && n.IsInt64() {
+		m.sampling.expected = n.Int64()
+	}
+	return m
+}
+
+// SetBestLog streams a JSON line to w each time the best result improves
+func (m *Miner) SetBestLog(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bestLog = json.NewEncoder(w)
+}
+
+// SetResultWriter writes machine-readable results to w, independent of the logger: one
+// types.OutputEvent JSON line per best improvement and accepted match, then a final event
+// when Mine returns.
+func (m *Miner) SetResultWriter(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resultOut = json.NewEncoder(w)
+}
+
+// emitResult writes an output event to the result writer. Caller must hold m.mu.
+func (m *Miner) emitResult(kind string, result *types.Result) {
+	if m.resultOut == nil {
+		return
+	}
+	event := types.OutputEvent{Type: kind, Timestamp: m.now(), Result: result}
+	if err := m.resultOut.Encode(event); err != nil {
+		m.logger.Printf("Failed to write result: %v", err)
+	}
+}
+
+// SetAuditLog records near-miss candidates (matching at least the configured number of
+// prefix nibbles) to w through a shared buffered writer. Flushed when Mine returns.
+func (m *Miner) SetAuditLog(w io.Writer) {
+	m.audit = audit.New(w)
+}
+
+// SetRateCSV appends a timestamp,attempts,rate row to w at each progress tick,
+// flushing after every row so a crash keeps the trail written so far.
+func (m *Miner) SetRateCSV(w io.Writer) {
+	m.rateCSV = csv.NewWriter(w)
+}
+
+// SetCheckpoint rewrites a checkpoint at path on each progress tick and when mining ends.
+// When resuming, prior carries the earlier runs' checkpoint so attempt totals accumulate.
+func (m *Miner) SetCheckpoint(path string, prior *checkpoint.Checkpoint) {
+	m.checkpointPath = path
+	if prior != nil {
+		m.priorAttempts = prior.Attempts
+	}
+}
+
+// Checkpoint returns a snapshot of the run's progress
+func (m *Miner) Checkpoint() *checkpoint.Checkpoint {
+	cp := &checkpoint.Checkpoint{
+		Version:   checkpoint.Version,
+		Target:    m.config.GetTargetDescription(),
+		SaltMode:  m.config.SaltMode,
+		Attempts:  m.priorAttempts + atomic.LoadInt64(&m.attempts),
+		UpdatedAt: m.now(),
+	}
+	if cp.SaltMode == "" {
+		cp.SaltMode = config.SaltModeRandom
+	}
+
+	m.mu.RLock()
+	cp.Best = m.bestResult
+	progress := m.progress
+	m.mu.RUnlock()
+
+	if m.config.SaltMode == config.SaltModeSequential && len(progress) > 0 {
+		// Worker i has tried start+i, start+i+N, ... so every salt below start+min*N is done
+		done := atomic.LoadInt64(&progress[0])
+		for i := range progress {
+			done = min(done, atomic.LoadInt64(&progress[i]))
+		}
+		if m.resumeBase != nil {
+			point := *m.resumeBase
+			crypto.AddToSalt(&point, uint64(done)*uint64(len(progress)))
+			cp.ResumeFrom = crypto.FormatSalt(point, crypto.SaltFormatHex)
+		} else if done > 0 {
+			var point [32]byte
+			crypto.AddToSalt(&point, uint64(done)*uint64(len(progress))-1)
+			cp.ResumeFrom = crypto.FormatSalt(point, crypto.SaltFormatHex)
+		}
+	}
+	return cp
+}
+
+// writeCheckpoint saves the current checkpoint, logging rather than failing the run on error
+func (m *Miner) writeCheckpoint() {
+	if err := checkpoint.Save(m.checkpointPath, m.Checkpoint()); err != nil {
+		m.logger.Printf("Failed to write checkpoint: %v", err)
 	}
 }
 
 // Mine starts the mining process
 func (m *Miner) Mine() *types.Result {
-	start := time.Now()
+	start := m.now()
+	m.mu.Lock()
+	m.start = start
+	m.mu.Unlock()
+
+	// Stop once the time budget is spent
+	if m.config.Timeout > 0 {
+		timer := time.AfterFunc(m.config.Timeout, m.Stop)
+		defer timer.Stop()
+	}
 
 	// Start workers
+	m.mu.Lock()
+	m.progress = make([]int64, m.config.Workers)
+	m.mu.Unlock()
 	for i := 0; i < m.config.Workers; i++ {
 		m.wg.Add(1)
 		go m.worker(i)
 	}
 
-	// Start periodic logging if verbose mode is enabled
+	// Start periodic logging if verbose mode, the rate CSV, checkpointing or a state file is enabled
 	var logTicker *time.Ticker
 	var logDone chan bool
-	if m.config.Verbose {
+	if m.config.Verbose || m.rateCSV != nil || m.checkpointPath != "" || m.statePath != "" {
 		interval := time.Duration(m.config.LogInterval) * time.Second
 		logTicker = time.NewTicker(interval)
 		logDone = make(chan bool)
-		go m.periodicLogger(logTicker, logDone, start)
-
+		go m.periodicLogger(logTicker.C, logDone, start)
+	}
+	if m.memory.limit > 0 {
+		go m.watchMemory()
+	}
+	if m.config.Verbose {
 		// Log initial start message
 		m.logger.Printf("Mining started with %d workers, logging every %d seconds...",
 			m.config.Workers, m.config.LogInterval)
@@ -111,6 +518,29 @@ func (m *Miner) Mine() *types.Result {
 
 	// Wait for completion
 	m.wg.Wait()
+	if m.merge != nil {
+		m.resolveDeterministic()
+	}
+	m.mu.Lock()
+	m.finished = m.now()
+	m.mu.Unlock()
+	m.closeStream()
+	if m.entropyFile != nil {
+		m.entropyFile.Close()
+	}
+	m.closeExpvar()
+	if m.matcher != nil {
+		m.logger.Printf("Matcher accepted %d of %d candidates", m.matcherAccepted.Load(), m.matcherSeen.Load())
+	}
+	if m.sampler != nil {
+		m.logger.Printf("Salt collisions: %d (sampling 1 in %d salts per worker)", m.Collisions(), CollisionSampleEvery)
+	}
+
+	if m.audit != nil {
+		if err := m.audit.Flush(); err != nil {
+			m.logger.Printf("Failed to flush audit log: %v", err)
+		}
+	}
 
 	// Stop periodic logging
 	if logTicker != nil {
@@ -118,8 +548,21 @@ func (m *Miner) Mine() *types.Result {
 		close(logDone)
 	}
 
+	m.mu.Lock()
+	if m.bestResult == nil && m.partial != nil {
+		// --best-effort: no match in time, so report the closest prefix instead
+		m.bestResult = m.partial
+	}
 	if m.bestResult != nil {
-		m.bestResult.Duration = time.Since(start)
+		m.bestResult.Duration = m.now().Sub(start)
+	}
+	m.emitResult(types.OutputFinal, m.bestResult)
+	m.mu.Unlock()
+	if m.checkpointPath != "" {
+		m.writeCheckpoint()
+	}
+	if m.statePath != "" {
+		m.writeState()
 	}
 
 	return m.bestResult
@@ -128,73 +571,394 @@ func (m *Miner) Mine() *types.Result {
 // worker runs the mining logic for a single worker
 func (m *Miner) worker(workerID int) {
 	defer m.wg.Done()
+	if m.config.PinCPUs {
+		if err := pinWorker(workerID); err != nil {
+			m.pinWarning.Do(func() {
+				m.logger.Printf("Warning: workers are not pinned to CPUs: %v", err)
+			})
+		}
+	}
 
 	batchSize := 1000 // Process in batches for better performance
 	w := worker.NewWorker(m.workerConfig, &m.attempts)
+	if w.SeedFallback() {
+		m.seedWarning.Do(func() {
+			m.logger.Printf("Warning: reading system entropy failed; seeding salts from a ChaCha20 fallback generator")
+		})
+	}
+	if m.config.SaltMode == config.SaltModeSequential {
+		// Interleave the keyspace: worker i tries start+i, start+i+N, ...
+		start := m.saltStart
+		crypto.AddToSalt(&start, uint64(workerID))
+		w.SetSaltCursor(start, uint64(m.config.Workers))
+	}
+	if m.workerConfig.HDSalts != nil {
+		// Interleave child indices the same way: worker i derives m/(start+i)', m/(start+i+N)', ...
+		w.SetHDCursor(m.config.DerivationIndex+uint32(workerID), uint32(m.config.Workers))
+	}
+	if m.config.Entropy == config.EntropyOSHybrid {
+		w.SetHybridSalts(m.runStamp, uint32(workerID))
+	}
+	var tried int64
+	var tier int                         // longest prefix length this worker has reported
+	var candidates []*types.WorkerResult // matches held for the matcher until the batch ends
+	budget := m.workerKeyspace(workerID)
 
-	for {
-		select {
-		case <-m.done:
+	var top *topK
+	if m.config.TopK > 0 {
+		top = m.newTopK()
+		defer m.mergeTopK(top)
+	}
+	throttle := newDutyCycle(m.config.Throttle, m.now, m.sleep)
+
+	for !m.stopped() {
+		// Park between batches while paused
+		if m.waitWhilePaused() {
+			continue
+		}
+
+		// Stop once the attempt budget is spent (checked per batch, so it may overshoot slightly)
+		if m.config.MaxAttempts > 0 && atomic.LoadInt64(&m.attempts) >= m.config.MaxAttempts {
+			m.Stop()
+			return
+		}
+
+		// With --deterministic-workers, leave once every salt before the held matches is tried
+		if m.merge != nil && m.merge.done(uint64(tried)*uint64(m.config.Workers)+uint64(workerID)) {
 			return
-		default:
-			// Process a batch of attempts; check done only once per batch
-			for i := 0; i < batchSize; i++ {
-				result := w.GenerateAddress()
-				if result == nil {
-					continue
+		}
+
+		// Leave once this worker's share of a bounded keyspace is done
+		n := batchSize
+		if m.bounded {
+			if budget == 0 {
+				m.exhausted.Add(1)
+				return
+			}
+			n = int(min(budget, uint64(batchSize)))
+			budget -= uint64(n)
+		}
+
+		// Process a batch of attempts; the stop flag is checked only between batches
+		if throttle != nil {
+			throttle.batchStart()
+		}
+		for i := 0; i < n; i++ {
+			tried++
+			result := w.GenerateAddress()
+			if result == nil {
+				continue
+			}
+			m.emitStream(result)
+			if m.sampler != nil && tried%CollisionSampleEvery == 0 && m.sampler.add(result.SaltBytes) {
+				m.collisionWarning.Do(func() {
+					m.logger.Printf("Warning: a sampled salt repeated; workers may share random generator state")
+				})
+			}
+
+			// In scoring modes (zero prefix, words), track the best address found for all addresses.
+			// Deterministic runs rank only the matches, once they are resolved.
+			if m.config.TracksBest() && m.merge == nil {
+				m.trackBest(result)
+				if top != nil {
+					top.offer(result)
 				}
+			}
 
-				// For zero prefix, track the best (lowest) address found for all addresses
-				if m.config.IsZeroPrefix() {
-					m.mu.Lock()
-					if m.bestResult == nil || m.isBetterBytes(result.AddressBytes, m.bestResultBytes) {
-						saltStr := result.Salt
-						if saltStr == "" {
-							saltStr = hex.EncodeToString(result.SaltBytes[:])
-						}
-						addrStr := result.Address
-						if addrStr == "" {
-							addrStr = crypto.AddressBytesToChecksumString(result.AddressBytes[:])
-						}
-						m.bestResult = &types.Result{
-							Salt:     saltStr,
-							Address:  addrStr,
-							Attempts: result.Attempts,
-						}
-						m.bestResultBytes = result.AddressBytes
-					}
-					m.mu.Unlock()
+			// Log the first candidate reaching each prefix length, and keep the longest for --best-effort
+			if result.PrefixNibbles > tier {
+				tier = result.PrefixNibbles
+				if m.config.Verbose {
+					m.reachTier(result)
 				}
+				if m.config.BestEffort {
+					m.trackPartial(result)
+				}
+			}
 
-				// Check if this matches our criteria
-				if result.IsMatch {
-					m.mu.Lock()
-					if m.bestResult == nil || m.isBetterBytes(result.AddressBytes, m.bestResultBytes) {
-						saltStr := result.Salt
-						if saltStr == "" {
-							saltStr = hex.EncodeToString(result.SaltBytes[:])
-						}
-						addrStr := result.Address
-						if addrStr == "" {
-							addrStr = crypto.AddressBytesToChecksumString(result.AddressBytes[:])
-						}
-						m.bestResult = &types.Result{
-							Salt:     saltStr,
-							Address:  addrStr,
-							Attempts: result.Attempts,
-						}
-						m.bestResultBytes = result.AddressBytes
-						m.once.Do(func() { close(m.done) })
-					}
-					m.mu.Unlock()
-					return
+			// Record near-misses for the audit trail
+			if m.audit != nil && !result.IsMatch {
+				m.auditNearMiss(result)
+			}
+
+			// Check if this matches our criteria. The final match stops the run; the batch
+			// still runs to its end so every worker drains and flushes before exiting.
+			if result.IsMatch {
+				if m.merge != nil {
+					m.merge.offer(uint64(tried-1)*uint64(m.config.Workers)+uint64(workerID), result, m.config.Count)
+				} else if m.matcher != nil {
+					candidates = append(candidates, result)
+				} else {
+					m.acceptMatch(result)
 				}
 			}
 		}
+		if len(candidates) > 0 {
+			m.filterMatches(candidates)
+			candidates = candidates[:0]
+		}
+		w.Flush()
+		atomic.StoreInt64(&m.progress[workerID], tried)
+		if throttle != nil {
+			throttle.batchDone()
+		}
+	}
+}
+
+// trackBest records the result as the best so far if it improves on the current best
+func (m *Miner) trackBest(result *types.WorkerResult) {
+	score := m.score(result.AddressBytes)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.bestResult == nil || m.isBetter(result.AddressBytes, score) {
+		m.setBest(m.toResult(result), result.AddressBytes, score)
+	}
+}
+
+// score rates an address in the active scoring mode; higher is better.
+// In --closest-to mode it is the negated bit length of the distance to the target.
+func (m *Miner) score(addr [20]byte) int {
+	if m.closestTo != nil {
+		d := crypto.AddressDistance(addr, *m.closestTo)
+		for i, b := range d {
+			if b != 0 {
+				return -((19-i)*8 + bits.Len8(b))
+			}
+		}
+		return 0
+	}
+	if m.niceSet != nil {
+		return m.niceSet.Score(addr[:])
+	}
+	if m.words != nil {
+		return crypto.WordScore(hex.EncodeToString(addr[:]), m.words)
+	}
+	if m.config.Ascending {
+		return crypto.LongestAscendingRunBytes(addr[:])
+	}
+	if m.config.Score == config.ScoreGas {
+		return crypto.GasScore(addr[:])
+	}
+	return crypto.LeadingZeroNibbles(addr[:])
+}
+
+// isBetter reports whether a candidate beats the current best. Caller must hold m.mu.
+func (m *Miner) isBetter(addr [20]byte, score int) bool {
+	if m.config.HigherScoreWins() {
+		return score > m.bestScore
+	}
+	if m.closestTo != nil {
+		return m.closerTo(addr, m.bestResultBytes)
+	}
+	return m.isBetterBytes(addr, m.bestResultBytes)
+}
+
+// closerTo reports whether a is strictly closer than b to the --closest-to target
+func (m *Miner) closerTo(a, b [20]byte) bool {
+	da := crypto.AddressDistance(a, *m.closestTo)
+	db := crypto.AddressDistance(b, *m.closestTo)
+	return bytes.Compare(da[:], db[:]) < 0
+}
+
+// setBest replaces the best result and emits an improvement event. Caller must hold m.mu,
+// which keeps events ordered and monotonic in score.
+func (m *Miner) setBest(best *types.Result, addr [20]byte, score int) {
+	best.Score = score
+	m.bestResult = best
+	m.bestResultBytes = addr
+	m.bestScore = score
+	m.bestChanges++
+
+	m.emitResult(types.OutputBest, best)
+
+	if m.bestLog != nil {
+		event := types.BestEvent{
+			Timestamp: m.now(),
+			Attempts:  atomic.LoadInt64(&m.attempts),
+			Salt:      best.Salt,
+			Address:   best.Address,
+			Score:     score,
+		}
+		if err := m.bestLog.Encode(event); err != nil {
+			m.logger.Printf("Failed to write best log: %v", err)
+		}
 	}
 }
 
-// isBetterBytes compares two 20-byte addresses; returns true if new is lexicographically smaller (lower address).
+// reachTier logs each prefix length the result is the first candidate to reach. A jump
+// of several lengths logs each of them at the same attempt count.
+func (m *Miner) reachTier(result *types.WorkerResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tierAttempts == nil {
+		m.tierAttempts = make([]int64, len(m.workerConfig.PrefixBytes)*2+1)
+	}
+	// Workers' counts differ by their unflushed attempts; keep the milestones in order
+	attempts := max(result.Attempts, m.tierAttempts[m.tierReached])
+	for ; m.tierReached < result.PrefixNibbles; m.tierReached++ {
+		m.tierAttempts[m.tierReached+1] = attempts
+		m.logger.Printf("First %d-char prefix match after %d attempts: %s",
+			m.tierReached+1, attempts, crypto.AddressBytesToChecksumString(result.AddressBytes[:]))
+	}
+}
+
+// trackPartial keeps the result if it matches more prefix characters than any before it.
+// Its Score is the number of prefix characters matched.
+func (m *Miner) trackPartial(result *types.WorkerResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.partial == nil || result.PrefixNibbles > m.partial.Score {
+		m.partial = m.toResult(result)
+		m.partial.Score = result.PrefixNibbles
+	}
+}
+
+// PrefixTiers returns, for each prefix length from 1, the attempts at which a candidate first
+// matched that many prefix characters (0 if none has yet). Tiers are tracked in verbose mode only.
+func (m *Miner) PrefixTiers() []int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.tierAttempts == nil {
+		return nil
+	}
+	return append([]int64(nil), m.tierAttempts[1:]...)
+}
+
+// auditNearMiss records a candidate that matches a shorter prefix than the target
+func (m *Miner) auditNearMiss(result *types.WorkerResult) {
+	addr := crypto.ShiftNibbles(result.AddressBytes[:], m.config.PrefixOffset)
+	matched := crypto.MatchingPrefixNibbles(addr[:], m.workerConfig.PrefixBytes)
+	if matched < m.config.GetAuditThreshold() {
+		return
+	}
+	r := m.toResult(result)
+	if err := m.audit.Record(r.Salt, r.Address, matched); err != nil {
+		m.logger.Printf("Failed to write audit log: %v", err)
+	}
+}
+
+// acceptMatch records a matching result, skipping addresses already found by any worker.
+// Returns true once the requested number of distinct matches has been reached.
+// With --keep-searching the run goes on and a better match replaces the one held.
+func (m *Miner) acceptMatch(result *types.WorkerResult) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.config.KeepSearching && len(m.results) > 0 {
+		return m.keepBetterMatch(result)
+	}
+	// Another worker may have completed the count while this one was hashing
+	if len(m.results) >= m.config.Count {
+		return true
+	}
+	if _, dup := m.found[result.AddressBytes]; dup {
+		return false
+	}
+	m.found[result.AddressBytes] = struct{}{}
+
+	match := m.toResult(result)
+	match.Duration = m.now().Sub(m.start)
+	m.results = append(m.results, match)
+	m.kept = result.AddressBytes
+	m.emitResult(types.OutputMatch, match)
+	m.writeMatchFile(match)
+
+	score := m.score(result.AddressBytes)
+	if m.bestResult == nil || m.isBetter(result.AddressBytes, score) {
+		best := *match
+		m.setBest(&best, result.AddressBytes, score)
+	}
+
+	if len(m.results) >= m.config.Count && !m.config.KeepSearching {
+		m.stopping.Store(true)
+		m.once.Do(func() { close(m.done) })
+		return true
+	}
+	return false
+}
+
+// keepBetterMatch replaces the match held for --keep-searching if result beats it. In scoring
+// modes trackBest has already seen result, so it is compared with the held match rather than
+// the best result. Caller must hold m.mu.
+func (m *Miner) keepBetterMatch(result *types.WorkerResult) bool {
+	addr := result.AddressBytes
+	var better bool
+	switch {
+	case m.config.HigherScoreWins():
+		better = m.score(addr) > m.score(m.kept)
+	case m.closestTo != nil:
+		better = m.closerTo(addr, m.kept)
+	default:
+		better = addr != m.kept && m.isBetterBytes(addr, m.kept)
+	}
+	if !better {
+		return false
+	}
+
+	match := m.toResult(result)
+	match.Duration = m.now().Sub(m.start)
+	m.results[0] = match
+	m.kept = addr
+	m.emitResult(types.OutputMatch, match)
+	m.writeMatchFile(match)
+
+	score := m.score(addr)
+	if m.isBetter(addr, score) {
+		best := *match
+		m.setBest(&best, addr, score)
+	}
+	return false
+}
+
+// toResult builds an output result from a worker result, encoding salt and address if needed
+func (m *Miner) toResult(result *types.WorkerResult) *types.Result {
+	saltStr := result.Salt
+	if saltStr == "" {
+		saltStr = hex.EncodeToString(result.SaltBytes[:])
+	}
+	addrStr := result.Address
+	if addrStr == "" {
+		addrStr = crypto.AddressBytesToChecksumString(result.AddressBytes[:])
+	}
+	out := &types.Result{
+		Salt:           saltStr,
+		Address:        addrStr,
+		AddressLower:   "0x" + hex.EncodeToString(result.AddressBytes[:]),
+		ExtraAddresses: result.ExtraAddresses,
+		Attempts:       result.Attempts,
+		Factory:        result.Factory,
+		SaltLabel:      m.config.SaltLabel,
+		RunID:          m.config.RunID,
+	}
+	if result.HDSalt {
+		index := result.DerivationIndex
+		out.DerivationIndex = &index
+	}
+	m.addReproduction(out, result)
+	return out
+}
+
+// ResultComparator returns a function reporting whether result a beats b under the scoring
+// mode of cfg, for comparing finished results such as those in merged checkpoints.
+func ResultComparator(cfg *config.Config) func(a, b *types.Result) bool {
+	return func(a, b *types.Result) bool {
+		if cfg.HigherScoreWins() {
+			return a.Score > b.Score
+		}
+		if cfg.ClosestTo != "" {
+			da, db := crypto.DistanceTo(a.Address, cfg.ClosestTo), crypto.DistanceTo(b.Address, cfg.ClosestTo)
+			return da != nil && (db == nil || da.Cmp(db) < 0)
+		}
+		c := strings.Compare(strings.ToLower(a.Address), strings.ToLower(b.Address))
+		if cfg.Best == config.BestHighest {
+			return c > 0
+		}
+		return c < 0
+	}
+}
+
+// isBetterBytes compares two 20-byte addresses; returns true if new is lexicographically smaller (lower address),
+// or larger when --best highest is set. Equal addresses are never better.
 // Zero oldAddr is treated as "no previous best" so any new address is better.
 func (m *Miner) isBetterBytes(newAddr, oldAddr [20]byte) bool {
 	// No previous best (all zeros): accept any
@@ -204,6 +968,9 @@ func (m *Miner) isBetterBytes(newAddr, oldAddr [20]byte) bool {
 	}
 	for i := 0; i < 20; i++ {
 		if newAddr[i] != oldAddr[i] {
+			if m.config.Best == config.BestHighest {
+				return newAddr[i] > oldAddr[i]
+			}
 			return newAddr[i] < oldAddr[i]
 		}
 	}
@@ -212,7 +979,31 @@ func (m *Miner) isBetterBytes(newAddr, oldAddr [20]byte) bool {
 
 // Stop stops the mining process
 func (m *Miner) Stop() {
+	m.stopping.Store(true)
 	m.once.Do(func() { close(m.done) })
+	m.wakePaused()
+}
+
+// Results returns the distinct matches found so far, in the order they were accepted
+func (m *Miner) Results() []*types.Result {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*types.Result(nil), m.results...)
+}
+
+// TargetLine returns the --target-file line listing addr, as parsed when the miner was
+// created, and false when addr is not a target or no target file is set
+func (m *Miner) TargetLine(addr [20]byte) (int, bool) {
+	if m.workerConfig == nil {
+		return 0, false
+	}
+	line, ok := m.workerConfig.TargetSet[addr]
+	return line, ok
+}
+
+// Attempts returns the total number of attempts made so far
+func (m *Miner) Attempts() int64 {
+	return atomic.LoadInt64(&m.attempts)
 }
 
 // GetBestResult returns the current best result
@@ -223,37 +1014,139 @@ func (m *Miner) GetBestResult() *types.Result {
 }
 
 // periodicLogger logs mining progress at regular intervals
-func (m *Miner) periodicLogger(ticker *time.Ticker, done chan bool, start time.Time) {
+func (m *Miner) periodicLogger(tick <-chan time.Time, done chan bool, start time.Time) {
 	for {
 		select {
-		case <-ticker.C:
-			attempts := atomic.LoadInt64(&m.attempts)
-			elapsed := time.Since(start)
-
-			// Calculate rate safely
-			rate := 0.0
-			if elapsed.Seconds() > 0 {
-				rate = float64(attempts) / elapsed.Seconds()
-			}
-
-			m.mu.RLock()
-			bestResult := m.bestResult
-			m.mu.RUnlock()
-
-			if bestResult != nil {
-				if m.config.IsZeroPrefix() {
-					m.logger.Printf("Progress: %d attempts, %.2f hashes/sec, Best so far: %s (salt: 0x%s)",
-						attempts, rate, bestResult.Address, bestResult.Salt)
-				} else {
-					m.logger.Printf("Progress: %d attempts, %.2f hashes/sec, Best: %s (salt: 0x%s)",
-						attempts, rate, bestResult.Address, bestResult.Salt)
-				}
-			} else {
-				m.logger.Printf("Progress: %d attempts, %.2f hashes/sec, No match yet",
-					attempts, rate)
-			}
+		case <-tick:
+			m.progressTick(start)
 		case <-done:
 			return
 		}
 	}
 }
+
+// progressTick runs the per-interval progress outputs
+func (m *Miner) progressTick(start time.Time) {
+	if m.config.Verbose && m.shouldLogProgress() {
+		m.logStatus(start)
+	}
+	if m.rateCSV != nil {
+		m.writeRate(start)
+	}
+	if m.checkpointPath != "" {
+		m.writeCheckpoint()
+	}
+	if m.statePath != "" {
+		m.writeState()
+	}
+}
+
+// Stats summarizes the work done by a run
+type Stats struct {
+	Attempts int64
+	Elapsed  time.Duration // time spent mining, excluding pauses
+	Rate     float64       // attempts per second over Elapsed
+}
+
+// Stats returns the attempts, mining time and average rate so far. Once Mine has
+// returned they are final, so an interrupted run can still report them.
+func (m *Miner) Stats() Stats {
+	m.mu.RLock()
+	start, finished := m.start, m.finished
+	m.mu.RUnlock()
+	if start.IsZero() {
+		return Stats{}
+	}
+	if finished.IsZero() {
+		finished = m.now()
+	}
+	return m.statsBetween(start, finished)
+}
+
+// rate returns the attempts so far and the average hash rate since start
+func (m *Miner) rate(start time.Time) (int64, float64) {
+	s := m.statsBetween(start, m.now())
+	return s.Attempts, s.Rate
+}
+
+// statsBetween measures the attempts against the unpaused time from start to end
+func (m *Miner) statsBetween(start, end time.Time) Stats {
+	s := Stats{
+		Attempts: atomic.LoadInt64(&m.attempts),
+		Elapsed:  end.Sub(start) - m.pausedFor(end),
+	}
+	// Calculate rate safely
+	if s.Elapsed.Seconds() > 0 {
+		s.Rate = float64(s.Attempts) / s.Elapsed.Seconds()
+	}
+	return s
+}
+
+// writeRate appends one row to the rate CSV and flushes it
+func (m *Miner) writeRate(start time.Time) {
+	attempts, rate := m.rate(start)
+	m.rateCSV.Write([]string{
+		m.now().UTC().Format(time.RFC3339),
+		strconv.FormatInt(attempts, 10),
+		strconv.FormatFloat(rate, 'f', 2, 64),
+	})
+	m.rateCSV.Flush()
+	if err := m.rateCSV.Error(); err != nil {
+		m.logger.Printf("Failed to write rate CSV: %v", err)
+	}
+}
+
+// LogStatus logs the current attempts, rate and best result immediately.
+// It is safe to call from any goroutine while Mine is running.
+func (m *Miner) LogStatus() {
+	m.mu.RLock()
+	start := m.start
+	m.mu.RUnlock()
+	if start.IsZero() {
+		m.logger.Printf("Progress: mining has not started yet")
+		return
+	}
+	m.logStatus(start)
+}
+
+// logStatus logs one progress line relative to the mining start time
+func (m *Miner) logStatus(start time.Time) {
+	attempts, rate := m.rate(start)
+
+	m.mu.RLock()
+	bestResult := m.bestResult
+	m.mu.RUnlock()
+
+	label := "Progress"
+	if m.Paused() {
+		label = "Progress (paused)"
+	}
+	if bestResult != nil {
+		if m.config.TracksBest() {
+			m.logger.Printf("%s: %d attempts, %.2f hashes/sec, Best so far: %s (salt: 0x%s)",
+				label, attempts, rate, bestResult.Address, bestResult.Salt)
+		} else {
+			m.logger.Printf("%s: %d attempts, %.2f hashes/sec, Best: %s (salt: 0x%s)",
+				label, attempts, rate, bestResult.Address, bestResult.Salt)
+		}
+	} else {
+		m.logger.Printf("%s: %d attempts, %.2f hashes/sec, No match yet%s",
+			label, attempts, rate, m.Outlook().describe())
+	}
+	if m.sampler != nil {
+		m.logger.Printf("Salt collisions: %d", m.Collisions())
+	}
+}
+
+// lockedReader serializes reads so workers seeding concurrently from one entropy file
+// each receive whole, distinct seeds
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return io.ReadFull(l.r, p)
+}
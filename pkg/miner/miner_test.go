@@ -1,10 +1,29 @@
 package miner
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/screa/erc2470-address-miner/internal/audit"
+	"github.com/screa/erc2470-address-miner/internal/checkpoint"
 	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
 	"github.com/screa/erc2470-address-miner/internal/logger"
+	"github.com/screa/erc2470-address-miner/pkg/types"
 )
 
 func TestNewMiner(t *testing.T) {
@@ -64,3 +83,2019 @@ func TestMinerIsBetter(t *testing.T) {
 		})
 	}
 }
+
+func TestMinerIsBetterHighest(t *testing.T) {
+	addr1 := [20]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	addr2 := [20]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}
+	tests := []struct {
+		name     string
+		newAddr  [20]byte
+		oldAddr  [20]byte
+		expected bool
+	}{
+		{
+			name:     "higher new address is better",
+			newAddr:  addr2,
+			oldAddr:  addr1,
+			expected: true,
+		},
+		{
+			name:     "lower new address is worse",
+			newAddr:  addr1,
+			oldAddr:  addr2,
+			expected: false,
+		},
+		{
+			name:     "addresses are equal",
+			newAddr:  addr2,
+			oldAddr:  addr2,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.Best = config.BestHighest
+			miner := NewMiner(cfg, logger.New())
+			result := miner.isBetterBytes(tt.newAddr, tt.oldAddr)
+			if result != tt.expected {
+				t.Errorf("isBetterBytes() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMinerCountDistinct(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00" // easy pattern so several workers hit matches quickly
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 4
+	cfg.Count = 5
+	miner := NewMiner(cfg, logger.New())
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+
+	results := miner.Results()
+	if len(results) != cfg.Count {
+		t.Fatalf("Results() returned %d matches, want %d", len(results), cfg.Count)
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.Address] {
+			t.Errorf("duplicate address %s in results", r.Address)
+		}
+		seen[r.Address] = true
+	}
+}
+
+func TestBestLog(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "0000"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	miner := NewMiner(cfg, logger.New())
+
+	var buf bytes.Buffer
+	miner.SetBestLog(&buf)
+
+	// Feed improving, non-improving and improving candidates
+	for _, first := range []byte{0xf0, 0x80, 0x90, 0x01, 0x00} {
+		var wr types.WorkerResult
+		wr.AddressBytes[0] = first
+		wr.AddressBytes[19] = 1
+		miner.trackBest(&wr)
+	}
+
+	var events []types.BestEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e types.BestEvent
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("invalid best log line: %v", err)
+		}
+		events = append(events, e)
+	}
+
+	if len(events) != 4 {
+		t.Fatalf("got %d best events, want 4", len(events))
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Score < events[i-1].Score {
+			t.Errorf("event %d score %d decreased from %d", i, events[i].Score, events[i-1].Score)
+		}
+		if events[i].Timestamp.Before(events[i-1].Timestamp) {
+			t.Errorf("event %d is out of order", i)
+		}
+	}
+	if events[3].Score != 39 || events[3].Address != miner.GetBestResult().Address {
+		t.Errorf("last event = %+v, want score 39 for the final best address", events[3])
+	}
+}
+
+func TestMinerMultipleInitCodes(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "a.hex"), filepath.Join(dir, "b.hex")}
+	os.WriteFile(files[0], []byte("0x6080604052"), 0600)
+	os.WriteFile(files[1], []byte("0x6080604053"), 0600)
+
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.BytecodeFiles = files
+	cfg.Workers = 4
+	miner := NewMiner(cfg, logger.New())
+
+	result := miner.Mine()
+	if result == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	if len(result.ExtraAddresses) != 1 {
+		t.Fatalf("ExtraAddresses = %v, want one address for the second init code", result.ExtraAddresses)
+	}
+
+	// Recompute both addresses with the shared salt
+	salt, _ := hex.DecodeString(result.Salt)
+	initcodes, _ := cfg.GetBytecodes()
+	want := []string{result.Address, result.ExtraAddresses[0]}
+	for i, initcode := range initcodes {
+		addr := crypto.CalculateCreate2Address(crypto.Keccak256(initcode), salt)
+		if addr != want[i] {
+			t.Errorf("init code %d address = %s, want %s", i, addr, want[i])
+		}
+		if !strings.HasPrefix(strings.ToLower(addr), "0xab") {
+			t.Errorf("init code %d address %s does not match prefix ab", i, addr)
+		}
+	}
+}
+
+func TestMinerWordsMode(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Words = true
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 20000
+	miner := NewMiner(cfg, logger.New())
+
+	best := miner.Mine()
+	if best == nil {
+		t.Fatal("Mine() returned nil in words mode")
+	}
+	if len(miner.Results()) != 0 {
+		t.Errorf("words mode reported %d matches, want none", len(miner.Results()))
+	}
+	if got := crypto.WordScore(best.Address, crypto.DefaultWords); got != best.Score || got == 0 {
+		t.Errorf("best score = %d, WordScore(%s) = %d, want equal and non-zero", best.Score, best.Address, got)
+	}
+}
+
+func TestMinerAscendingMode(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Ascending = true
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 20000
+	cfg.TopK = 5
+	miner := NewMiner(cfg, logger.New())
+
+	best := miner.Mine()
+	if best == nil {
+		t.Fatal("Mine() returned nil in ascending mode")
+	}
+	// 20000 candidates all but certainly include a run of 4
+	if got := crypto.LongestAscendingRun(best.Address); got != best.Score || got < 4 {
+		t.Errorf("best score = %d, LongestAscendingRun(%s) = %d, want equal and at least 4", best.Score, best.Address, got)
+	}
+	for _, r := range miner.TopResults() {
+		if r.Score > best.Score {
+			t.Errorf("top result %s scores %d, above the best %d", r.Address, r.Score, best.Score)
+		}
+	}
+}
+
+func TestMinerBestEffort(t *testing.T) {
+	const prefix = "deadbeefdeadbeef"
+	for _, bestEffort := range []bool{false, true} {
+		cfg := config.NewConfig()
+		cfg.Prefix = prefix
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		cfg.Timeout = 100 * time.Millisecond
+		cfg.BestEffort = bestEffort
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+
+		result := NewMiner(cfg, logger.New()).Mine()
+		if !bestEffort {
+			if result != nil {
+				t.Errorf("Mine() = %+v without --best-effort, want nil", result)
+			}
+			continue
+		}
+		if result == nil {
+			t.Fatal("Mine() returned nil with --best-effort")
+		}
+		// 100ms of candidates all but certainly match 3 characters
+		lower := strings.TrimPrefix(result.AddressLower, "0x")
+		if result.Score < 3 || !strings.HasPrefix(lower, prefix[:result.Score]) || strings.HasPrefix(lower, prefix[:result.Score+1]) {
+			t.Errorf("partial match %s reports %d prefix characters", result.Address, result.Score)
+		}
+		if _, ok := Reproduce(result); !ok {
+			t.Errorf("Reproduce() rejected the partial match %+v", result)
+		}
+	}
+}
+
+func TestMinerClosestToMode(t *testing.T) {
+	target := "0x1234567890123456789012345678901234567890"
+	cfg := config.NewConfig()
+	cfg.ClosestTo = target
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 20000
+	var events bytes.Buffer
+	miner := NewMiner(cfg, logger.New())
+	miner.SetBestLog(&events)
+
+	best := miner.Mine()
+	if best == nil {
+		t.Fatal("Mine() returned nil in closest-to mode")
+	}
+
+	// Every improvement must be strictly closer than the one before it
+	var prev *big.Int
+	dec := json.NewDecoder(&events)
+	for dec.More() {
+		var event types.BestEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("decode best event: %v", err)
+		}
+		d := crypto.DistanceTo(event.Address, target)
+		if prev != nil && d.Cmp(prev) >= 0 {
+			t.Errorf("best event %s distance %v did not improve on %v", event.Address, d, prev)
+		}
+		prev = d
+	}
+	if prev == nil || crypto.DistanceTo(best.Address, target).Cmp(prev) != 0 {
+		t.Errorf("final best %s is not the last improvement", best.Address)
+	}
+}
+
+func TestMinerInitCodeHashMatchesBytecode(t *testing.T) {
+	// Resume just before the known bytecode.txt salt so the first sequential salt is the match
+	newConfig := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.Prefix = "000000"
+		cfg.Workers = 1
+		cfg.SaltMode = config.SaltModeSequential
+		cfg.ResumeFrom = "0x011b828d"
+		return cfg
+	}
+
+	fromFile := newConfig()
+	fromFile.BytecodeFiles = []string{"../../bytecode.txt"}
+	fromHash := newConfig()
+	fromHash.InitCodeHash = "0x453b9684db78ed19be9b289f18e18b83dda389b1fbea527aba3ab03918de91d8"
+
+	want := "0x0000002DBE996066c3F322753B4AB7F245C13981"
+	for name, cfg := range map[string]*config.Config{"bytecode file": fromFile, "init code hash": fromHash} {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("%s: Validate() error = %v", name, err)
+		}
+		result := NewMiner(cfg, logger.New()).Mine()
+		if result == nil || result.Address != want {
+			t.Errorf("%s: Mine() = %+v, want address %s", name, result, want)
+		}
+	}
+}
+
+func TestMinerRecoverTinyKeyspace(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Target = "0x0000002dbe996066c3f322753b4ab7f245c13981"
+	cfg.BytecodeFiles = []string{"../../bytecode.txt"}
+	cfg.Workers = 2
+	cfg.SaltMode = config.SaltModeSequential
+	cfg.ResumeFrom = "0x011b8200"
+	cfg.MaxAttempts = 10000
+
+	result := NewMiner(cfg, logger.New()).Mine()
+	if result == nil {
+		t.Fatal("Mine() did not recover the salt")
+	}
+	if want := "00000000000000000000000000000000000000000000000000000000011b828e"; result.Salt != want {
+		t.Errorf("recovered salt = %s, want %s", result.Salt, want)
+	}
+}
+
+func TestMinerKeyspaceExhausted(t *testing.T) {
+	tests := []struct {
+		name       string
+		resumeFrom string
+		saltEnd    string
+		saltBytes  int
+		keyspace   int64
+	}{
+		{"one byte", "", "0xff", 0, 256},
+		{"resumed range", "0x0f", "0x1f", 0, 16},
+		{"fewer salts than workers", "", "0x01", 0, 2},
+		{"salt bytes", "", "", 1, 256},
+		{"salt bytes resumed", "0x0100", "", 2, 65535 - 256},
+		{"salt end inside salt bytes", "", "0x3f", 2, 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Target = "0x0000000000000000000000000000000000000000"
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.Workers = 3
+			cfg.SaltMode = config.SaltModeSequential
+			cfg.ResumeFrom = tt.resumeFrom
+			cfg.SaltEnd = tt.saltEnd
+			cfg.SaltBytes = tt.saltBytes
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			miner := NewMiner(cfg, logger.New())
+			if keyspace, bounded := miner.Keyspace(); !bounded || int64(keyspace) != tt.keyspace {
+				t.Errorf("Keyspace() = %d, %v, want %d, true", keyspace, bounded, tt.keyspace)
+			}
+			if result := miner.Mine(); result != nil {
+				t.Fatalf("Mine() = %+v, want no match", result)
+			}
+			if !miner.Exhausted() {
+				t.Error("Exhausted() = false after searching the whole range")
+			}
+			if got := miner.Attempts(); got != tt.keyspace {
+				t.Errorf("Attempts() = %d, want each of the %d salts once", got, tt.keyspace)
+			}
+		})
+	}
+}
+
+func TestMinerKeyspaceMatch(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Target = "0x0000002dbe996066c3f322753b4ab7f245c13981"
+	cfg.BytecodeFiles = []string{"../../bytecode.txt"}
+	cfg.Workers = 2
+	cfg.SaltMode = config.SaltModeSequential
+	cfg.ResumeFrom = "0x011b8200"
+	cfg.SaltEnd = "0x011b82ff"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	miner := NewMiner(cfg, logger.New())
+	if result := miner.Mine(); result == nil {
+		t.Fatal("Mine() did not find the salt inside the range")
+	}
+	if miner.Exhausted() {
+		t.Error("Exhausted() = true after a match stopped the run")
+	}
+}
+
+func TestMinerTargetAnyCasing(t *testing.T) {
+	for _, target := range []string{
+		"0x0000002dbe996066c3f322753b4ab7f245c13981",
+		"0X0000002DBE996066C3F322753B4AB7F245C13981",
+		"0x0000002DBE996066c3F322753B4AB7F245C13981",
+	} {
+		cfg := config.NewConfig()
+		cfg.Target = target
+		cfg.BytecodeFiles = []string{"../../bytecode.txt"}
+		cfg.Workers = 2
+		cfg.SaltMode = config.SaltModeSequential
+		cfg.ResumeFrom = "0x011b8200"
+		cfg.SaltEnd = "0x011b82ff"
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("%s: Validate() error = %v", target, err)
+		}
+
+		result := NewMiner(cfg, logger.New()).Mine()
+		if result == nil || result.Address != "0x0000002DBE996066c3F322753B4AB7F245C13981" {
+			t.Errorf("%s: Mine() = %+v, want the checksummed target", target, result)
+		}
+	}
+}
+
+func TestMinerAuditLogNearMisses(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcdef"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 20000
+	cfg.AuditLog = "audit.jsonl"
+	cfg.AuditThreshold = 2
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	miner := NewMiner(cfg, logger.New())
+	miner.SetAuditLog(&buf)
+	miner.Mine()
+
+	var entries []audit.Entry
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e audit.Entry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("invalid audit line: %v", err)
+		}
+		entries = append(entries, e)
+	}
+
+	// ~1/256 of 20000 attempts share the first two characters
+	if len(entries) == 0 {
+		t.Fatal("no near-misses recorded")
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(strings.ToLower(e.Address), "0xab") || e.Matched < 2 {
+			t.Errorf("entry %+v is not a near-miss of prefix abcdef", e)
+		}
+	}
+}
+
+func TestRateCSVFakeClock(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	var out bytes.Buffer
+	miner := NewMiner(cfg, logger.New())
+	miner.SetRateCSV(&out)
+
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	clock := start
+	miner.now = func() time.Time { return clock }
+
+	// Each tick advances the clock 10s and adds 1000 attempts, so the rate holds at 100/s
+	for i := 1; i <= 3; i++ {
+		clock = start.Add(time.Duration(i) * 10 * time.Second)
+		atomic.AddInt64(&miner.attempts, 1000)
+		miner.progressTick(start)
+	}
+
+	want := "2024-01-15T10:30:10Z,1000,100.00\n" +
+		"2024-01-15T10:30:20Z,2000,100.00\n" +
+		"2024-01-15T10:30:30Z,3000,100.00\n"
+	if got := out.String(); got != want {
+		t.Errorf("rate CSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMatchTimesFakeClock(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.KeepSearching = true
+	var bestLog bytes.Buffer
+	miner := NewMiner(cfg, logger.New())
+	miner.SetBestLog(&bestLog)
+
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	clock := start
+	miner.now = func() time.Time { return clock }
+	miner.start = start
+
+	// The first match is accepted after 10s, and a lower one replaces it after 40s
+	for _, tt := range []struct {
+		elapsed time.Duration
+		addr    byte
+	}{{10 * time.Second, 0x05}, {40 * time.Second, 0x01}} {
+		clock = start.Add(tt.elapsed)
+		miner.acceptMatch(&types.WorkerResult{IsMatch: true, AddressBytes: [20]byte{0x00, tt.addr}})
+		if got := miner.Results()[0].Duration; got != tt.elapsed {
+			t.Errorf("match %x Duration = %v, want %v", tt.addr, got, tt.elapsed)
+		}
+	}
+
+	dec := json.NewDecoder(&bestLog)
+	for _, want := range []time.Time{start.Add(10 * time.Second), start.Add(40 * time.Second)} {
+		var event types.BestEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("best log: %v", err)
+		}
+		if !event.Timestamp.Equal(want) {
+			t.Errorf("best event Timestamp = %v, want %v", event.Timestamp, want)
+		}
+	}
+}
+
+func TestCheckpointResumePoint(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcdef"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 1
+	cfg.SaltMode = config.SaltModeSequential
+	cfg.MaxAttempts = 5000
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	miner := NewMiner(cfg, logger.New())
+	miner.SetCheckpoint(path, &checkpoint.Checkpoint{Attempts: 700})
+	miner.Mine()
+
+	cp, err := checkpoint.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := 700 + miner.Attempts(); cp.Attempts != want {
+		t.Errorf("checkpoint attempts = %d, want %d", cp.Attempts, want)
+	}
+	salt, err := crypto.ParseHexSalt(cp.ResumeFrom)
+	if err != nil {
+		t.Fatalf("invalid resume point %q: %v", cp.ResumeFrom, err)
+	}
+	if got, want := new(big.Int).SetBytes(salt[:]).Int64(), miner.Attempts()-1; got != want {
+		t.Errorf("resume point = %d, want the last salt tried %d", got, want)
+	}
+}
+
+func TestStateFileCarriesBest(t *testing.T) {
+	newConfig := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.Prefix = "0000000000" // zero-prefix scoring; no match within the budget
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		cfg.MaxAttempts = 5000
+		return cfg
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := NewMiner(newConfig(), logger.New())
+	first.SetStateFile(path, nil)
+	best := first.Mine()
+	saved, err := checkpoint.LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if saved.Attempts != first.Attempts() || saved.Best == nil || saved.Best.Address != best.Address {
+		t.Fatalf("state = %d attempts, best %+v; want %d attempts, best %s", saved.Attempts, saved.Best, first.Attempts(), best.Address)
+	}
+
+	// A restart adds to the attempts and never reports worse than the saved best
+	second := NewMiner(newConfig(), logger.New())
+	second.SetStateFile(path, saved)
+	got := second.Mine()
+	if got == nil || strings.ToLower(got.Address) > strings.ToLower(best.Address) {
+		t.Errorf("restarted best = %+v, want at least as low as %s", got, best.Address)
+	}
+	resumed, err := checkpoint.LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if want := saved.Attempts + second.Attempts(); resumed.Attempts != want {
+		t.Errorf("state attempts after restart = %d, want %d", resumed.Attempts, want)
+	}
+}
+
+func TestCheckpointResumePointInterleaved(t *testing.T) {
+	// Worker i tries start+i, start+i+N, ...; only complete rounds count toward the resume point
+	tests := []struct {
+		name       string
+		resumeFrom string
+		progress   []int64
+		expected   string
+	}{
+		{"no progress", "", []int64{0, 1000}, ""},
+		{"from zero", "", []int64{3000, 2000}, "0x0000000000000000000000000000000000000000000000000000000000000f9f"},
+		{"after resume point", "0x10", []int64{3000, 2000}, "0x0000000000000000000000000000000000000000000000000000000000000fb0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Prefix = "ab"
+			cfg.Bytecode = "6080"
+			cfg.Workers = len(tt.progress)
+			cfg.SaltMode = config.SaltModeSequential
+			cfg.ResumeFrom = tt.resumeFrom
+			miner := NewMiner(cfg, logger.New())
+			miner.progress = tt.progress
+			if got := miner.Checkpoint().ResumeFrom; got != tt.expected {
+				t.Errorf("ResumeFrom = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResultComparatorMerge(t *testing.T) {
+	a := &checkpoint.Checkpoint{Version: checkpoint.Version, Target: "most hex words", Attempts: 10,
+		Best: &types.Result{Address: "0x0000000000000000000000000000000000000001", Score: 2}}
+	b := &checkpoint.Checkpoint{Version: checkpoint.Version, Target: "most hex words", Attempts: 20,
+		Best: &types.Result{Address: "0xdeadbeef00000000000000000000000000000000", Score: 5}}
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want *types.Result
+	}{
+		{"lowest address", &config.Config{Best: config.BestLowest}, a.Best},
+		{"highest address", &config.Config{Best: config.BestHighest}, b.Best},
+		{"words score", &config.Config{Words: true}, b.Best},
+		{"closest to", &config.Config{ClosestTo: "0xdeadbeef00000000000000000000000000000001"}, b.Best},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := checkpoint.Merge([]*checkpoint.Checkpoint{a, b}, ResultComparator(tt.cfg))
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if merged.Best != tt.want || merged.Attempts != 30 {
+				t.Errorf("merged best = %s (%d attempts), want %s (30 attempts)", merged.Best.Address, merged.Attempts, tt.want.Address)
+			}
+		})
+	}
+}
+
+func TestResultWriter(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	var out, logs bytes.Buffer
+	miner := NewMiner(cfg, logger.NewWriter(&logs))
+	miner.SetResultWriter(&out)
+
+	best := miner.Mine()
+	if best == nil {
+		t.Fatal("Mine() returned nil")
+	}
+
+	var events []types.OutputEvent
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var event types.OutputEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatalf("decode output event: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	want := []string{types.OutputMatch, types.OutputBest, types.OutputFinal}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, event.Type, want[i])
+		}
+		if event.Result == nil || event.Result.Address != best.Address || event.Result.Salt != best.Salt {
+			t.Errorf("event %d result = %+v, want %s", i, event.Result, best.Address)
+		}
+	}
+	if strings.Contains(logs.String(), best.Salt) {
+		t.Error("result JSON leaked into the log output")
+	}
+}
+
+func TestExpectedAttempts(t *testing.T) {
+	pow16 := func(n int) *big.Int { return new(big.Int).Lsh(big.NewInt(1), uint(4*n)) }
+	tests := []struct {
+		name  string
+		setup func(c *config.Config)
+		want  *big.Int
+	}{
+		{"prefix", func(c *config.Config) { c.Prefix = "dead" }, pow16(4)},
+		{"prefix casing", func(c *config.Config) { c.Prefix = "DeAd" }, pow16(4)},
+		{"prefix and suffix", func(c *config.Config) { c.Prefix = "dead"; c.Suffix = "beef" }, pow16(8)},
+		{"prefix and suffix overlap", func(c *config.Config) { c.Prefix = strings.Repeat("a", 30); c.Suffix = strings.Repeat("a", 20) }, pow16(40)},
+		{"checksummed target", func(c *config.Config) {
+			c.Target = "0x0000002DBE996066c3F322753B4AB7F245C13981"
+			c.CaseSensitive = true
+		}, pow16(40)},
+		{"zero prefix and suffix", func(c *config.Config) { c.Prefix = "0000"; c.Suffix = "00" }, pow16(6)},
+		{"prefix with low zero bits", func(c *config.Config) { c.Prefix = "ab"; c.LowZeroBits = 5 }, new(big.Int).Lsh(big.NewInt(1), 13)},
+		{"two init codes", func(c *config.Config) { c.Prefix = "dead"; c.BytecodeFiles = []string{"a", "b"} }, pow16(8)},
+		{"two factories", func(c *config.Config) { c.Suffix = "beef"; c.Factories = []string{"erc2470", "arachnid"} }, pow16(4).Rsh(pow16(4), 1)},
+		{"scoring mode", func(c *config.Config) { c.Words = true }, nil},
+	}
+	for _, tt := range tests {
+		cfg := config.NewConfig()
+		tt.setup(cfg)
+		got := ExpectedAttempts(cfg)
+		if (got == nil) != (tt.want == nil) || (got != nil && got.Cmp(tt.want) != 0) {
+			t.Errorf("%s: ExpectedAttempts() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateBoundedVsUnbounded(t *testing.T) {
+	near := func(got, want float64) bool { return math.Abs(got-want) <= 1e-6*math.Max(1, math.Abs(want)) }
+
+	// Random salts are memoryless: 900 fruitless attempts change nothing
+	fresh, after := estimate(1000, 0, 0, false), estimate(1000, 900, 0, false)
+	if fresh != after || after.Attempts != 1000 || after.Chance != 1 {
+		t.Errorf("unbounded estimate = %+v after 900 attempts, %+v fresh; want 1000 attempts at certainty", after, fresh)
+	}
+
+	// A keyspace far larger than the expected attempts behaves like the random model
+	if o := estimate(1000, 0, 1e9, true); !near(o.Attempts, 1000) || !near(o.Chance, 1) {
+		t.Errorf("estimate over a huge keyspace = %+v, want ~1000 attempts at certainty", o)
+	}
+
+	// A keyspace the size of the expected attempts: searching most of it shrinks the chance
+	// that a match is left and the attempts to it
+	start, late := estimate(1000, 0, 1000, true), estimate(1000, 900, 1000, true)
+	if !near(start.Chance, 1-math.Pow(0.999, 1000)) || !near(late.Chance, 1-math.Pow(0.999, 100)) {
+		t.Errorf("chances = %v then %v, want 1-(1-p)^r over 1000 then 100 salts", start.Chance, late.Chance)
+	}
+	if late.Remaining != 100 || late.Attempts >= 100 || late.Attempts >= start.Attempts || start.Attempts >= after.Attempts {
+		t.Errorf("bounded estimates %+v then %+v should fall below the unbounded %v and within the 100 salts left", start, late, after.Attempts)
+	}
+
+	// Recovering a full target: a match in the range is equally likely at any salt left
+	target := math.Ldexp(1, 160)
+	if o := estimate(target, 56, 256, true); o.Remaining != 200 || !near(o.Attempts, 100.5) || o.Chance <= 0 {
+		t.Errorf("target estimate = %+v, want 100.5 attempts over the 200 salts left", o)
+	}
+
+	// Nothing left, or nothing to estimate
+	if o := estimate(1000, 1000, 1000, true); o.Remaining != 0 || o.Attempts != 0 || o.Chance != 0 {
+		t.Errorf("exhausted estimate = %+v, want zero", o)
+	}
+	if o := estimate(0, 10, 0, false); o.Attempts != 0 || o.describe() != "" {
+		t.Errorf("scoring mode estimate = %+v, want none", o)
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.Count = 3
+	dir := t.TempDir()
+	var logs bytes.Buffer
+	miner := NewMiner(cfg, logger.NewWriter(&logs))
+	miner.SetOutputDir(dir)
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != cfg.Count {
+		t.Fatalf("output dir holds %d files, want %d", len(entries), cfg.Count)
+	}
+	hash, _ := cfg.GetInitCodeHash()
+	for _, r := range miner.Results() {
+		data, err := os.ReadFile(filepath.Join(dir, r.Address+".json"))
+		if err != nil {
+			t.Fatalf("no file for match %s: %v", r.Address, err)
+		}
+		var got matchFile
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Address != r.Address || got.Salt != r.Salt || got.InitCodeHash != hex.EncodeToString(hash) ||
+			got.Factory != crypto.FactoryAddress || got.Timestamp.IsZero() {
+			t.Errorf("%s.json = %+v, want the match's salt, init code hash, factory and a timestamp", r.Address, got)
+		}
+	}
+
+	// The same address again leaves the existing file alone
+	first := *miner.Results()[0]
+	path := filepath.Join(dir, first.Address+".json")
+	before, _ := os.ReadFile(path)
+	first.Salt = strings.Repeat("0", 64)
+	miner.writeMatchFile(&first)
+	if after, _ := os.ReadFile(path); !bytes.Equal(before, after) {
+		t.Error("a repeated address overwrote its match file")
+	}
+	if !strings.Contains(logs.String(), "Skipping") {
+		t.Error("skipping a repeated address was not logged")
+	}
+}
+
+func TestMinerHDSaltsDeterministic(t *testing.T) {
+	mnemonic := filepath.Join(t.TempDir(), "mnemonic.txt")
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about\n"
+	if err := os.WriteFile(mnemonic, []byte(words), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	newConfig := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.Prefix = "ab"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 1
+		cfg.SaltMode = config.SaltModeHD
+		cfg.MnemonicFile = mnemonic
+		cfg.DerivationIndex = 100
+		return cfg
+	}
+
+	first := NewMiner(newConfig(), logger.New()).Mine()
+	second := NewMiner(newConfig(), logger.New()).Mine()
+	if first == nil || second == nil {
+		t.Fatal("Mine() returned nil in hd mode")
+	}
+	if first.Salt != second.Salt || first.Address != second.Address {
+		t.Errorf("hd runs differ: %s/%s vs %s/%s", first.Salt, first.Address, second.Salt, second.Address)
+	}
+	if first.DerivationIndex == nil || *first.DerivationIndex < 100 {
+		t.Fatalf("derivation index = %v, want at least 100", first.DerivationIndex)
+	}
+
+	// The reported index regenerates the salt from the mnemonic alone
+	hd, err := crypto.NewHDSaltDeriver(crypto.MnemonicToSeed(words, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var salt [32]byte
+	hd.SaltAt(*first.DerivationIndex, &salt)
+	if got := hex.EncodeToString(salt[:]); got != first.Salt {
+		t.Errorf("salt at m/%d' = %s, want %s", *first.DerivationIndex, got, first.Salt)
+	}
+}
+
+func TestTopKKeepsBestCandidates(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.TopK = 5
+	m := &Miner{config: cfg}
+
+	// Feed addresses 0..199 in a scrambled order; only the five lowest may survive
+	top := m.newTopK()
+	for i := 0; i < 200; i++ {
+		var addr [20]byte
+		addr[19] = byte((i * 37) % 200)
+		top.offer(&types.WorkerResult{AddressBytes: addr})
+	}
+	if top.Len() != cfg.TopK {
+		t.Fatalf("heap holds %d candidates, want %d", top.Len(), cfg.TopK)
+	}
+
+	// A second worker's heap with a better candidate is merged in
+	other := m.newTopK()
+	other.offer(&types.WorkerResult{AddressBytes: [20]byte{}})
+	m.mergeTopK(top)
+	m.mergeTopK(other)
+
+	results := m.TopResults()
+	if len(results) != cfg.TopK {
+		t.Fatalf("TopResults() returned %d results, want %d", len(results), cfg.TopK)
+	}
+	for i, want := range []byte{0, 0, 1, 2, 3} {
+		var addr [20]byte
+		addr[19] = want
+		if got := crypto.AddressBytesToChecksumString(addr[:]); results[i].Address != got {
+			t.Errorf("result %d = %s, want %s", i, results[i].Address, got)
+		}
+	}
+}
+
+func TestMaxMemoryTrimsTopK(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "6080"
+	cfg.Workers = 1
+	cfg.TopK = 4000
+	cfg.MaxMemory = 1
+	m := NewMiner(cfg, logger.New())
+
+	// Charge 1KB per retained candidate, so keeping all of them would reach 4MB
+	m.memory.readHeap = func() uint64 { return uint64(m.memory.retained.Load()) << 10 }
+	top := m.newTopK()
+	var peak uint64
+	best := [20]byte{0xff}
+	for i := 1; i <= 20000; i++ {
+		var addr [20]byte
+		binary.BigEndian.PutUint32(addr[16:], uint32(i)*2654435761)
+		top.offer(&types.WorkerResult{AddressBytes: addr})
+		if bytes.Compare(addr[:], best[:]) < 0 {
+			best = addr
+		}
+		if i%50 == 49 {
+			m.checkMemory()
+		}
+		peak = max(peak, m.memory.readHeap())
+	}
+	if peak > m.memory.limit {
+		t.Errorf("retained %d bytes at peak, want at most the %d byte limit", peak, m.memory.limit)
+	}
+	if top.Len() == 0 || top.Len() >= cfg.TopK {
+		t.Fatalf("heap holds %d candidates, want it trimmed below %d", top.Len(), cfg.TopK)
+	}
+
+	// Trimming drops the worst candidates, so the best one survives
+	m.mergeTopK(top)
+	results := m.TopResults()
+	if len(results) != top.Len() {
+		t.Fatalf("TopResults() returned %d results, want %d", len(results), top.Len())
+	}
+	if want := crypto.AddressBytesToChecksumString(best[:]); results[0].Address != want {
+		t.Errorf("best result = %s, want %s", results[0].Address, want)
+	}
+}
+
+func TestTopKWords(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Words = true
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 5000
+	cfg.TopK = 3
+	m := NewMiner(cfg, logger.New())
+
+	best := m.Mine()
+	results := m.TopResults()
+	if len(results) != cfg.TopK {
+		t.Fatalf("TopResults() returned %d results, want %d", len(results), cfg.TopK)
+	}
+	if results[0].Score != best.Score {
+		t.Errorf("top result score %d, want best score %d", results[0].Score, best.Score)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("results not sorted best first: %d before %d", results[i-1].Score, results[i].Score)
+		}
+	}
+}
+
+func TestStreamConsumeAndCancel(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "0000000000000000" // never matches within the test
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	miner := NewMiner(cfg, logger.New())
+	initCodeHash, err := cfg.GetInitCodeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := miner.Stream(ctx)
+	done := make(chan *types.Result)
+	go func() { done <- miner.Mine() }()
+
+	const want = 100
+	for i := 0; i < want; i++ {
+		candidate, ok := <-stream
+		if !ok {
+			t.Fatalf("stream closed after %d candidates", i)
+		}
+		got := crypto.CalculateCreate2Address(initCodeHash, candidate.SaltBytes[:])
+		if addr := crypto.AddressBytesToChecksumString(candidate.AddressBytes[:]); addr != got {
+			t.Fatalf("candidate %d address %s, want %s for its salt", i, addr, got)
+		}
+	}
+
+	// Cancelling closes the stream while mining carries on; buffered candidates may still drain
+	cancel()
+	for range stream {
+	}
+	miner.Stop()
+	<-done
+	if miner.Attempts() < want {
+		t.Errorf("Attempts() = %d, want at least %d", miner.Attempts(), want)
+	}
+}
+
+func TestMinerRepeating(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Repeating = 4
+	cfg.Prefix = "aa"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	miner := NewMiner(cfg, logger.New())
+
+	result := miner.Mine()
+	if result == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	// Both criteria must hold under the default all-criteria matching
+	if crypto.LongestRun(result.Address) < cfg.Repeating {
+		t.Errorf("address %s has no run of %d", result.Address, cfg.Repeating)
+	}
+	if !strings.HasPrefix(strings.ToLower(result.Address), "0xaa") {
+		t.Errorf("address %s does not start with the prefix", result.Address)
+	}
+}
+
+func TestMinerSolidityNew(t *testing.T) {
+	// EIP-1014 example 6: a contract at 0x...deadbeef running new{salt: 0xcafebabe} with 44
+	// bytes of init code, split here into creation code and constructor arguments
+	cfg := config.NewConfig()
+	cfg.Mode = config.ModeSolidityNew
+	cfg.Factories = []string{"0x00000000000000000000000000000000deadbeef"}
+	cfg.Bytecode = strings.Repeat("deadbeef", 9)
+	cfg.ConstructorArgs = "0x" + strings.Repeat("deadbeef", 2)
+	cfg.Target = "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"
+	cfg.SaltMode = config.SaltModeSequential
+	cfg.ResumeFrom = "0xcafebabd"
+	cfg.SaltEnd = "0xcafebaff"
+	cfg.Workers = 2
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result := NewMiner(cfg, logger.New()).Mine()
+	if result == nil {
+		t.Fatal("Mine() did not find the known new{salt} deployment")
+	}
+	if want := strings.Repeat("0", 56) + "cafebabe"; result.Salt != want {
+		t.Errorf("salt = %s, want %s", result.Salt, want)
+	}
+	if result.Address != cfg.Target {
+		t.Errorf("address = %s, want %s", result.Address, cfg.Target)
+	}
+}
+
+func TestMinerSaltLabel(t *testing.T) {
+	for _, mode := range []string{config.SaltModeRandom, config.SaltModeSequential} {
+		cfg := config.NewConfig()
+		cfg.Prefix = "ab"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.SaltMode = mode
+		cfg.SaltLabel = "TREASURY"
+		cfg.Workers = 2
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		result := NewMiner(cfg, logger.New()).Mine()
+		if result == nil {
+			t.Fatalf("%s: Mine() returned nil", mode)
+		}
+		salt, _ := hex.DecodeString(result.Salt)
+		if string(salt[:8]) != "TREASURY" || result.SaltLabel != "TREASURY" {
+			t.Errorf("%s: salt %s with label %q does not start with TREASURY", mode, result.Salt, result.SaltLabel)
+		}
+		if _, ok := Reproduce(result); !ok {
+			t.Errorf("%s: Reproduce() rejected labelled salt %s", mode, result.Salt)
+		}
+	}
+}
+
+func TestMinerTargetFile(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	hash, _ := cfg.GetInitCodeHash()
+
+	// Three addresses the range produces, listed among decoys it does not; want holds their lines
+	want := map[string]int{}
+	lines := []string{"# recovered from the deploy logs", "0x" + strings.Repeat("11", 20)}
+	for _, n := range []byte{0x03, 0x40, 0xc8} {
+		var salt [32]byte
+		salt[30], salt[31] = 0x10, n
+		addr := crypto.CalculateCreate2Address(hash, salt[:])
+		want[addr] = len(lines) + 1
+		lines = append(lines, strings.ToLower(addr), "0x"+strings.Repeat("ee", 20))
+	}
+	cfg.TargetFile = filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(cfg.TargetFile, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SaltMode = config.SaltModeSequential
+	cfg.ResumeFrom = "0x1000"
+	cfg.SaltEnd = "0x10ff"
+	cfg.Count = len(want)
+	cfg.Workers = 2
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// The targets are read once: removing the file after NewMiner changes nothing
+	miner := NewMiner(cfg, logger.New())
+	if err := os.Remove(cfg.TargetFile); err != nil {
+		t.Fatal(err)
+	}
+	if miner.Mine() == nil {
+		t.Fatal("Mine() found none of the listed targets")
+	}
+	results := miner.Results()
+	if len(results) != len(want) {
+		t.Fatalf("Results() returned %d matches, want %d", len(results), len(want))
+	}
+	for _, r := range results {
+		wantLine, ok := want[r.Address]
+		if !ok {
+			t.Errorf("matched %s, which is not a listed target", r.Address)
+			continue
+		}
+		addr, _ := crypto.MustAddressBytes(r.Address)
+		if line, ok := miner.TargetLine([20]byte(addr)); !ok || line != wantLine {
+			t.Errorf("TargetLine(%s) = %d, %v, want %d", r.Address, line, ok, wantLine)
+		}
+	}
+}
+
+// tailMatcher accepts addresses ending in tail, or fails every batch when err is set
+type tailMatcher struct {
+	tail string
+	err  error
+}
+
+func (f *tailMatcher) Filter(addresses []string) ([]bool, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	accepted := make([]bool, len(addresses))
+	for i, a := range addresses {
+		accepted[i] = strings.HasSuffix(strings.ToLower(a), f.tail)
+	}
+	return accepted, nil
+}
+
+func TestMinerMatcher(t *testing.T) {
+	newMiner := func(f Matcher) *Miner {
+		cfg := config.NewConfig()
+		cfg.Prefix = "ab"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		cfg.Count = 3
+		m := NewMiner(cfg, logger.New())
+		m.SetMatcher(f)
+		return m
+	}
+
+	m := newMiner(&tailMatcher{tail: "7"})
+	if m.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	for _, r := range m.Results() {
+		if !strings.HasPrefix(r.AddressLower, "0xab") || !strings.HasSuffix(r.AddressLower, "7") {
+			t.Errorf("match %s passed the matcher without both prefix and tail", r.Address)
+		}
+	}
+
+	failing := errors.New("matcher exited")
+	m = newMiner(&tailMatcher{err: failing})
+	if result := m.Mine(); result != nil {
+		t.Errorf("Mine() = %+v with a failing matcher, want nil", result)
+	}
+	if !errors.Is(m.MatcherErr(), failing) {
+		t.Errorf("MatcherErr() = %v, want %v", m.MatcherErr(), failing)
+	}
+}
+
+func TestMinerKeepSearching(t *testing.T) {
+	// One sequential worker is deterministic, so both runs see the same candidates
+	run := func(keep bool) (*Miner, *types.Result) {
+		cfg := config.NewConfig()
+		cfg.Prefix = "ab"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.SaltMode = config.SaltModeSequential
+		cfg.Workers = 1
+		cfg.MaxAttempts = 20000
+		cfg.KeepSearching = keep
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		m := NewMiner(cfg, logger.New())
+		return m, m.Mine()
+	}
+
+	_, first := run(false)
+	m, best := run(true)
+	if first == nil || best == nil {
+		t.Fatalf("Mine() = %v, %v, want a match from both runs", first, best)
+	}
+	if m.Attempts() < 20000 {
+		t.Errorf("--keep-searching stopped after %d attempts, want the whole budget of 20000", m.Attempts())
+	}
+	// ~80 candidates start with ab; the first of them is all but certainly not the lowest
+	if best.AddressLower >= first.AddressLower {
+		t.Errorf("best match %s does not improve on the first match %s", best.AddressLower, first.AddressLower)
+	}
+	if !strings.HasPrefix(best.AddressLower, "0xab") {
+		t.Errorf("best match %s does not start with the prefix", best.AddressLower)
+	}
+	if results := m.Results(); len(results) != 1 || results[0].Address != best.Address {
+		t.Errorf("Results() = %v, want only the best match %s", results, best.Address)
+	}
+}
+
+func TestMinerLowZeroBits(t *testing.T) {
+	for _, n := range []int{1, 5, 12} {
+		cfg := config.NewConfig()
+		cfg.LowZeroBits = n
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		result := NewMiner(cfg, logger.New()).Mine()
+		if result == nil {
+			t.Fatalf("Mine() returned nil for %d bits", n)
+		}
+		addr, _ := hex.DecodeString(result.Address[2:])
+		if got := crypto.TrailingZeroBits(addr); got < n {
+			t.Errorf("address %s has %d low zero bits, want at least %d", result.Address, got, n)
+		}
+	}
+}
+
+func TestMinerPrefixBits(t *testing.T) {
+	// 6 and 13 bits stop inside a nibble
+	for _, n := range []int{6, 13} {
+		cfg := config.NewConfig()
+		cfg.PrefixBits = n
+		cfg.PrefixBitsPattern = "b7"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		if n > 8 {
+			cfg.PrefixBitsPattern = "b7f8"
+		}
+		result := NewMiner(cfg, logger.New()).Mine()
+		if result == nil {
+			t.Fatalf("Mine() returned nil for %d bits", n)
+		}
+		addr, _ := hex.DecodeString(result.Address[2:])
+		pattern, _ := hex.DecodeString(cfg.PrefixBitsPattern)
+		if !crypto.MatchPrefixBits(addr, pattern, n) {
+			t.Errorf("address %s does not start with the top %d bits of %s", result.Address, n, cfg.PrefixBitsPattern)
+		}
+	}
+}
+
+func TestPickWorkersStubbedBenchmark(t *testing.T) {
+	// Hyperthreaded box: twice the cores beats one worker per core
+	stub := map[int]float64{4: 1e6, 8: 1.8e6, 16: 2.1e6}
+	var measured []int
+	best, rates := PickWorkers(WorkerCandidates(8), func(n int) float64 {
+		measured = append(measured, n)
+		return stub[n]
+	})
+	if best != 16 {
+		t.Errorf("PickWorkers() = %d, want 16", best)
+	}
+	if len(rates) != 3 || len(measured) != 3 {
+		t.Fatalf("measured %v, want each of 4, 8 and 16 once", measured)
+	}
+	for _, r := range rates {
+		if r.Rate != stub[r.Workers] {
+			t.Errorf("rate for %d workers = %v, want %v", r.Workers, r.Rate, stub[r.Workers])
+		}
+	}
+
+	// Ties keep the smaller count, and a single CPU is not tried with zero workers
+	if best, _ := PickWorkers(WorkerCandidates(1), func(int) float64 { return 1 }); best != 1 {
+		t.Errorf("PickWorkers() on one CPU = %d, want 1", best)
+	}
+	if got := WorkerCandidates(1); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("WorkerCandidates(1) = %v, want [1 2]", got)
+	}
+}
+
+func TestAutoTuneWorkers(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.AutoWorkers = true
+	miner := NewMiner(cfg, logger.New())
+
+	rates, err := miner.AutoTuneWorkers(context.Background(), 2, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("AutoTuneWorkers() error = %v", err)
+	}
+	if len(rates) != 3 {
+		t.Fatalf("AutoTuneWorkers() measured %d counts, want 3", len(rates))
+	}
+	if cfg.Workers != 1 && cfg.Workers != 2 && cfg.Workers != 4 {
+		t.Errorf("Workers = %d, want one of the candidates", cfg.Workers)
+	}
+	if miner.Attempts() != 0 {
+		t.Errorf("benchmark attempts leaked into the run: %d", miner.Attempts())
+	}
+}
+
+func TestAutoTuneWorkersCancelled(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 3
+	cfg.AutoWorkers = true
+	miner := NewMiner(cfg, logger.New())
+
+	// Cancel a benchmark that would otherwise take half a minute
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := miner.AutoTuneWorkers(ctx, 2, 10*time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AutoTuneWorkers() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("cancelled benchmark took %v", elapsed)
+	}
+	if cfg.Workers != 3 {
+		t.Errorf("Workers = %d after cancelling, want it unchanged", cfg.Workers)
+	}
+}
+
+func TestDrainAttemptTotals(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 4
+	cfg.Count = 3
+	miner := NewMiner(cfg, logger.New())
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+
+	// Every worker drained its batch and flushed, so the per-worker totals add up exactly
+	var sum int64
+	for i := range miner.progress {
+		sum += atomic.LoadInt64(&miner.progress[i])
+	}
+	total := miner.Attempts()
+	if sum != total {
+		t.Errorf("per-worker attempts sum to %d, total is %d", sum, total)
+	}
+	if total%1000 != 0 {
+		t.Errorf("total %d is not a whole number of batches", total)
+	}
+	for _, r := range miner.Results() {
+		if r.Attempts > total {
+			t.Errorf("match reports %d attempts, more than the final total %d", r.Attempts, total)
+		}
+	}
+}
+
+func TestMinerMultipleFactories(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Factories = []string{config.FactoryKindERC2470, config.FactoryArachnid}
+	cfg.Workers = 2
+	cfg.Count = 16
+	miner := NewMiner(cfg, logger.New())
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	initcode, _ := hex.DecodeString(cfg.Bytecode)
+	initcodeHash := crypto.Keccak256(initcode)
+	factories := map[string]bool{}
+	for _, r := range miner.Results() {
+		factoryBytes, err := crypto.MustAddressBytes(r.Factory)
+		if err != nil {
+			t.Fatalf("result factory %q: %v", r.Factory, err)
+		}
+		if r.Factory != crypto.FactoryAddress && r.Factory != crypto.ArachnidFactoryAddress {
+			t.Errorf("result factory %s is not one of the configured factories", r.Factory)
+		}
+		factories[r.Factory] = true
+		salt, _ := hex.DecodeString(r.Salt)
+		prefix := crypto.Create2PrefixFor(factoryBytes)
+		input := append(append(prefix[:], salt...), initcodeHash...)
+		want := crypto.AddressBytesToChecksumString(crypto.Keccak256(input)[12:])
+		if r.Address != want {
+			t.Errorf("address %s does not match CREATE2 under %s (%s)", r.Address, r.Factory, want)
+		}
+	}
+	// Each factory matches about as often, so both win among 16 matches
+	if len(factories) != 2 {
+		t.Errorf("matches came from %d factories, want both", len(factories))
+	}
+}
+
+func TestServeExpvar(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "00000000"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	miner := NewMiner(cfg, logger.New())
+
+	addr, err := miner.ServeExpvar("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ServeExpvar() error = %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		miner.Mine()
+		close(done)
+	}()
+
+	var vars struct {
+		Attempts    int64   `json:"attempts"`
+		Rate        float64 `json:"rate"`
+		BestAddress string  `json:"bestAddress"`
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for vars.Attempts == 0 || vars.BestAddress == "" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expvar never reported progress: %+v", vars)
+		}
+		time.Sleep(10 * time.Millisecond)
+		resp, err := http.Get("http://" + addr + "/debug/vars")
+		if err != nil {
+			t.Fatalf("GET /debug/vars: %v", err)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&vars)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("decoding /debug/vars: %v", err)
+		}
+	}
+	if vars.Rate <= 0 {
+		t.Errorf("rate = %v, want positive", vars.Rate)
+	}
+
+	// The server stops with the miner
+	miner.Stop()
+	<-done
+	if resp, err := http.Get("http://" + addr + "/debug/vars"); err == nil {
+		resp.Body.Close()
+		t.Error("expvar server still serving after Mine returned")
+	}
+}
+
+func TestMinerMatchExpr(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MatchExpr = "(prefix a or prefix b) and not suffix 0"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.Count = 5
+	miner := NewMiner(cfg, logger.New())
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	for _, r := range miner.Results() {
+		addr := strings.ToLower(r.Address[2:])
+		if (addr[0] != 'a' && addr[0] != 'b') || addr[39] == '0' {
+			t.Errorf("address %s does not satisfy %q", r.Address, cfg.MatchExpr)
+		}
+	}
+}
+
+func TestPrefixTiers(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcd"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.Verbose = true
+	miner := NewMiner(cfg, logger.New())
+
+	if miner.Mine() == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	tiers := miner.PrefixTiers()
+	if len(tiers) != 4 {
+		t.Fatalf("PrefixTiers() = %v, want one entry per prefix character", tiers)
+	}
+	// A 1-char near-miss is all but certain long before a 4-char match
+	if tiers[0] == 0 {
+		t.Errorf("tier 1 never reached: %v", tiers)
+	}
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i] != 0 && tiers[i] < tiers[i-1] {
+			t.Errorf("tier %d reached at %d attempts, before tier %d at %d", i+1, tiers[i], i, tiers[i-1])
+		}
+	}
+	if total := miner.Attempts(); tiers[0] > total {
+		t.Errorf("tier 1 reached at %d attempts, more than the total %d", tiers[0], total)
+	}
+}
+
+func TestSaltSamplerCountsCollisions(t *testing.T) {
+	s := newSaltSampler(2)
+	a, b, c := [32]byte{1}, [32]byte{2}, [32]byte{3}
+	if s.add(a) || s.add(b) {
+		t.Fatal("distinct salts counted as collisions")
+	}
+	if !s.add(a) {
+		t.Error("repeated salt not counted")
+	}
+	// The window holds two salts, so adding a third forgets the oldest
+	s.add(c)
+	if s.add(a) {
+		t.Error("evicted salt still counted as a collision")
+	}
+	if s.collisions != 1 {
+		t.Errorf("collisions = %d, want 1", s.collisions)
+	}
+}
+
+func TestDebugCollisionsSharedSeed(t *testing.T) {
+	// Every worker seeds from the same zero bytes, so their salt streams are identical
+	seedFile := filepath.Join(t.TempDir(), "zeros")
+	if err := os.WriteFile(seedFile, make([]byte, 1024), 0600); err != nil {
+		t.Fatal(err)
+	}
+	newMiner := func(entropy, file string) *Miner {
+		cfg := config.NewConfig()
+		cfg.Prefix = "deadbeefdeadbeef"
+		cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+		cfg.Workers = 2
+		cfg.MaxAttempts = 100000
+		cfg.Entropy = entropy
+		cfg.EntropyFile = file
+		cfg.DebugCollisions = true
+		return NewMiner(cfg, logger.New())
+	}
+
+	shared := newMiner(config.EntropyFile, seedFile)
+	shared.Mine()
+	if shared.Collisions() == 0 {
+		t.Error("workers with a shared seed produced no sampled collisions")
+	}
+	independent := newMiner(config.EntropyCrypto, "")
+	independent.Mine()
+	if n := independent.Collisions(); n != 0 {
+		t.Errorf("independently seeded workers produced %d collisions", n)
+	}
+}
+
+func TestPauseExcludedFromRate(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	miner := NewMiner(cfg, logger.New())
+
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	clock := start
+	miner.now = func() time.Time { return clock }
+	atomic.StoreInt64(&miner.attempts, 1000)
+
+	// 10s mining, 30s paused, 10s mining: the rate covers only the 20s of mining
+	clock = start.Add(10 * time.Second)
+	miner.Pause()
+	clock = start.Add(25 * time.Second)
+	if !miner.Paused() {
+		t.Fatal("Paused() = false after Pause")
+	}
+	if _, got := miner.rate(start); got != 100 {
+		t.Errorf("rate while paused = %.2f, want 100", got)
+	}
+	clock = start.Add(40 * time.Second)
+	miner.Resume()
+	clock = start.Add(50 * time.Second)
+	if _, got := miner.rate(start); got != 50 {
+		t.Errorf("rate after resume = %.2f, want 50", got)
+	}
+	if miner.Paused() {
+		t.Error("Paused() = true after Resume")
+	}
+}
+
+func TestThrottleScalesRate(t *testing.T) {
+	// Each batch takes 1ms of fake time; the rate over 10s follows the duty cycle
+	const batch, wall = time.Millisecond, 10 * time.Second
+	rate := func(percent int) float64 {
+		var clock time.Time
+		now := func() time.Time { return clock }
+		sleep := func(d time.Duration) { clock = clock.Add(d) }
+		throttle := newDutyCycle(percent, now, sleep)
+		batches := 0
+		for clock.Sub(time.Time{}) < wall {
+			if throttle != nil {
+				throttle.batchStart()
+			}
+			clock = clock.Add(batch)
+			batches++
+			if throttle != nil {
+				throttle.batchDone()
+			}
+		}
+		return float64(batches) / clock.Sub(time.Time{}).Seconds()
+	}
+
+	full := rate(0)
+	if got := rate(100); got != full {
+		t.Errorf("rate at 100%% = %.0f batches/sec, want full speed %.0f", got, full)
+	}
+	for _, percent := range []int{75, 50, 25, 10} {
+		got := rate(percent) / full
+		want := float64(percent) / 100
+		if math.Abs(got-want) > 0.05*want {
+			t.Errorf("rate at %d%% = %.3f of full speed, want about %.2f", percent, got, want)
+		}
+	}
+}
+
+func TestPauseParksWorkers(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcdefabcdef"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	miner := NewMiner(cfg, logger.New())
+
+	done := make(chan struct{})
+	go func() {
+		miner.Mine()
+		close(done)
+	}()
+	waitFor := func(cond func() bool) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor(func() bool { return miner.Attempts() > 0 })
+
+	// Let in-flight batches finish, then the count must hold still
+	miner.Pause()
+	time.Sleep(50 * time.Millisecond)
+	parked := miner.Attempts()
+	time.Sleep(50 * time.Millisecond)
+	if got := miner.Attempts(); got != parked {
+		t.Errorf("attempts rose from %d to %d while paused", parked, got)
+	}
+
+	miner.Resume()
+	waitFor(func() bool { return miner.Attempts() > parked })
+
+	// Stop wakes parked workers, so Mine returns while paused
+	miner.Pause()
+	miner.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Mine() did not return after Stop while paused")
+	}
+}
+
+func TestResultAddressForms(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	result := NewMiner(cfg, logger.New()).Mine()
+	if result == nil {
+		t.Fatal("Mine() returned nil")
+	}
+
+	checksummed, err := hex.DecodeString(result.Address[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, err := hex.DecodeString(strings.TrimPrefix(result.AddressLower, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(checksummed, lower) || len(lower) != 20 {
+		t.Errorf("Address %s and AddressLower %s differ", result.Address, result.AddressLower)
+	}
+	if result.AddressLower != strings.ToLower(result.AddressLower) {
+		t.Errorf("AddressLower = %s, want lowercase", result.AddressLower)
+	}
+	if crypto.AddressBytesToChecksumString(lower) != result.Address {
+		t.Errorf("Address = %s, want the EIP-55 form of %s", result.Address, result.AddressLower)
+	}
+}
+
+func TestProgressSampling(t *testing.T) {
+	improve := func(m *Miner) {
+		m.mu.Lock()
+		m.bestChanges++
+		m.mu.Unlock()
+	}
+	passExpected := func(m *Miner) { atomic.AddInt64(&m.attempts, 1<<16) }
+	tests := []struct {
+		name      string
+		every     int
+		onImprove bool
+		steps     map[int]func(m *Miner) // applied before the numbered tick
+		logged    string                 // one character per tick, x when a line is logged
+	}{
+		{"every tick", 0, false, nil, "xxxxxx"},
+		{"every third tick", 3, false, nil, "..x..x"},
+		{"on improve", 0, true, map[int]func(*Miner){2: improve, 5: passExpected}, ".x..x."},
+		{"on improve with heartbeat", 4, true, map[int]func(*Miner){2: improve}, ".x.x.."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Prefix = "abcd"
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.Verbose = true
+			cfg.ProgressEvery = tt.every
+			cfg.ProgressOnImprove = tt.onImprove
+			var logs bytes.Buffer
+			miner := NewMiner(cfg, logger.NewWriter(&logs))
+			start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+			miner.now = func() time.Time { return start.Add(time.Minute) }
+
+			var got strings.Builder
+			for i := 1; i <= len(tt.logged); i++ {
+				if step := tt.steps[i]; step != nil {
+					step(miner)
+				}
+				before := logs.Len()
+				miner.progressTick(start)
+				if strings.Contains(logs.String()[before:], "Progress:") {
+					got.WriteByte('x')
+				} else {
+					got.WriteByte('.')
+				}
+			}
+			if got.String() != tt.logged {
+				t.Errorf("logged ticks = %s, want %s", got.String(), tt.logged)
+			}
+		})
+	}
+}
+
+func TestStatsAfterStop(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcdefabcdef"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	miner := NewMiner(cfg, logger.New())
+	if s := miner.Stats(); s != (Stats{}) {
+		t.Errorf("Stats() before Mine = %+v, want zero", s)
+	}
+
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	var offset atomic.Int64
+	miner.now = func() time.Time { return start.Add(time.Duration(offset.Load())) }
+
+	done := make(chan struct{})
+	go func() {
+		miner.Mine()
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for miner.Attempts() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	offset.Store(int64(10 * time.Second))
+	miner.Stop()
+	<-done
+
+	// Time passing after the run does not change its stats
+	offset.Store(int64(time.Hour))
+	s := miner.Stats()
+	if s.Attempts == 0 || s.Attempts != miner.Attempts() {
+		t.Errorf("Stats().Attempts = %d, want the final count %d", s.Attempts, miner.Attempts())
+	}
+	if s.Elapsed != 10*time.Second {
+		t.Errorf("Stats().Elapsed = %v, want 10s", s.Elapsed)
+	}
+	if want := float64(s.Attempts) / 10; s.Rate != want {
+		t.Errorf("Stats().Rate = %.2f, want %.2f", s.Rate, want)
+	}
+}
+
+func TestStopLatency(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Prefix = "abcdefabcdef"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 4
+	miner := NewMiner(cfg, logger.New())
+
+	done := make(chan struct{})
+	go func() {
+		miner.Mine()
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Workers finish their current batch of 1000 attempts, a few milliseconds at most
+	stopped := time.Now()
+	miner.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Mine() did not return within a second of Stop")
+	}
+	if latency := time.Since(stopped); latency > 250*time.Millisecond {
+		t.Errorf("Mine() returned %v after Stop, want well under 250ms", latency)
+	}
+}
+
+// BenchmarkStopCheck compares the per-batch stop check against the select on done it replaced
+func BenchmarkStopCheck(b *testing.B) {
+	b.Run("select", func(b *testing.B) {
+		done := make(chan bool)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		})
+	})
+	b.Run("atomic", func(b *testing.B) {
+		var stopping atomic.Bool
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if stopping.Load() {
+					return
+				}
+			}
+		})
+	})
+}
+
+func TestMinerPinCPUs(t *testing.T) {
+	// Pinned on Linux, a logged no-op elsewhere; either way the run completes
+	cfg := config.NewConfig()
+	cfg.Prefix = "ab"
+	cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+	cfg.Workers = 2
+	cfg.PinCPUs = true
+	if result := NewMiner(cfg, logger.New()).Mine(); result == nil || !strings.HasPrefix(result.AddressLower, "0xab") {
+		t.Errorf("Mine() = %+v with --pin-cpus, want a match", result)
+	}
+}
+
+// BenchmarkPinCPUs compares throughput with workers pinned to CPUs against unpinned workers,
+// one worker per CPU
+func BenchmarkPinCPUs(b *testing.B) {
+	for _, pin := range []bool{false, true} {
+		name := "unpinned"
+		if pin {
+			name = "pinned"
+		}
+		b.Run(name, func(b *testing.B) {
+			cfg := config.NewConfig()
+			cfg.Prefix = "1234567890abcdef"
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.MaxAttempts = int64(b.N)
+			cfg.PinCPUs = pin
+			m := NewMiner(cfg, logger.New())
+			b.ResetTimer()
+			m.Mine()
+			b.ReportMetric(m.Stats().Rate, "hashes/s")
+		})
+	}
+}
+
+func TestReproduceSaltModes(t *testing.T) {
+	mnemonic := filepath.Join(t.TempDir(), "mnemonic.txt")
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about\n"
+	if err := os.WriteFile(mnemonic, []byte(words), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sender := "0x1234567890123456789012345678901234567890"
+
+	tests := []struct {
+		name    string
+		setup   func(c *config.Config)
+		createX bool
+	}{
+		{"random", func(c *config.Config) {}, false},
+		{"os-hybrid entropy", func(c *config.Config) { c.Entropy = config.EntropyOSHybrid }, false},
+		{"sequential", func(c *config.Config) { c.SaltMode = config.SaltModeSequential }, false},
+		{"hd", func(c *config.Config) { c.SaltMode = config.SaltModeHD; c.MnemonicFile = mnemonic }, false},
+		{"init code hash only", func(c *config.Config) {
+			c.Bytecode = ""
+			c.InitCodeHash = hex.EncodeToString(crypto.Keccak256([]byte{0x60, 0x80}))
+		}, false},
+		{"several factories", func(c *config.Config) {
+			c.Factories = []string{config.FactoryKindERC2470, config.FactoryArachnid}
+		}, false},
+		{"createx", func(c *config.Config) { c.FactoryKind = config.FactoryKindCreateX }, true},
+		{"createx msgsender guard", func(c *config.Config) {
+			c.FactoryKind = config.FactoryKindCreateX
+			c.CreateXGuard = "msgsender"
+			c.CreateXSender = sender
+		}, true},
+		{"createx crosschain guard", func(c *config.Config) {
+			c.FactoryKind = config.FactoryKindCreateX
+			c.CreateXGuard = "crosschain"
+			c.ChainID = 10
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Prefix = "ab"
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.Workers = 2
+			tt.setup(cfg)
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			result := NewMiner(cfg, logger.New()).Mine()
+			if result == nil {
+				t.Fatal("Mine() returned nil")
+			}
+			if (result.Create2Salt != "") != tt.createX {
+				t.Errorf("Create2Salt = %q, want it set only for CreateX", result.Create2Salt)
+			}
+
+			address, ok := Reproduce(result)
+			if !ok || address != result.Address {
+				t.Fatalf("Reproduce() = %s, %v, want %s from factory %s, salt %s, init code hash %s",
+					address, ok, result.Address, result.Factory, result.Salt, result.InitCodeHash)
+			}
+
+			// The recomputation depends on every field
+			tampered := *result
+			tampered.InitCodeHash = strings.Repeat("00", 32)
+			if _, ok := Reproduce(&tampered); ok {
+				t.Error("Reproduce() accepted a different init code hash")
+			}
+			tampered = *result
+			tampered.Factory = ""
+			if _, ok := Reproduce(&tampered); ok {
+				t.Error("Reproduce() accepted a result without a factory")
+			}
+		})
+	}
+}
+
+func TestMatchTailOf(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Bytecode = "6080"
+	cfg.MatchTailOf = "0xce0042B868300000d44A59004Da54A005ffdcf9f"
+	cfg.TailNibbles = 3
+	cfg.Workers = 2
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	result := NewMiner(cfg, logger.New()).Mine()
+	if result == nil {
+		t.Fatal("Mine() returned nil")
+	}
+	if !strings.HasSuffix(strings.ToLower(result.Address), "f9f") {
+		t.Errorf("Address = %s, want it to end in f9f", result.Address)
+	}
+}
+
+func TestNiceSetTracksBest(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Bytecode = "6080"
+	cfg.NiceSet = "bookends"
+	cfg.Workers = 2
+	cfg.MaxAttempts = 20000
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	best := NewMiner(cfg, logger.New()).Mine()
+	if best == nil {
+		t.Fatal("Mine() kept no best result")
+	}
+	addr, _ := crypto.MustAddressBytes(best.Address)
+	set, _ := crypto.LookupNiceSet("bookends")
+	if got := set.Score(addr); best.Score != got || got < 2 {
+		t.Errorf("best %s has score %d, want its bookends score %d of at least 2", best.Address, best.Score, got)
+	}
+}
+
+func TestDeterministicWorkers(t *testing.T) {
+	run := func(workers int) []string {
+		cfg := config.NewConfig()
+		cfg.Bytecode = "6080"
+		cfg.Prefix = "ab"
+		cfg.SaltMode = config.SaltModeSequential
+		cfg.DeterministicWorkers = true
+		cfg.Count = 3
+		cfg.Workers = workers
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		miner := NewMiner(cfg, logger.New())
+		if miner.Mine() == nil {
+			t.Fatal("Mine() found no match")
+		}
+		var salts []string
+		for _, r := range miner.Results() {
+			salts = append(salts, r.Salt)
+		}
+		return salts
+	}
+
+	// One worker tries the salts in order, so its matches are the earliest ones
+	want := run(1)
+	if len(want) != 3 {
+		t.Fatalf("single worker found %d matches, want 3", len(want))
+	}
+	for i := 0; i < 5; i++ {
+		if got := run(4); !slices.Equal(got, want) {
+			t.Fatalf("run %d with 4 workers found %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestSmokeTest(t *testing.T) {
+	for _, factory := range []string{config.FactoryKindERC2470, config.FactoryKindCreateX} {
+		t.Run(factory, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Bytecode = "608060405234801561001057600080fd5b50600436106100365760003560e01c8063"
+			cfg.Prefix = "deadbeefdeadbeef" // far too hard; the smoke test ignores it
+			cfg.Factories = []string{factory}
+			cfg.SmokeTest = true
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			result, err := SmokeTest(cfg, logger.New())
+			if err != nil {
+				t.Fatalf("SmokeTest() error = %v", err)
+			}
+			if !strings.HasPrefix(result.AddressLower, "0x"+SmokeTestPrefix) {
+				t.Errorf("SmokeTest() found %s, want prefix %s", result.Address, SmokeTestPrefix)
+			}
+			if factory == config.FactoryKindCreateX && result.Create2Salt == "" {
+				t.Error("SmokeTest() did not mine through the CreateX guard")
+			}
+			if _, ok := Reproduce(result); !ok {
+				t.Errorf("Reproduce() rejected the smoke test result %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package miner
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// matchFile is the JSON written for each match under --output-dir
+type matchFile struct {
+	Address      string    `json:"address"`
+	Salt         string    `json:"salt"`
+	InitCodeHash string    `json:"initcode_hash"`
+	Factory      string    `json:"factory"`
+	Create2Salt  string    `json:"create2_salt,omitempty"` // see types.Result
+	Timestamp    time.Time `json:"timestamp"`
+	RunID        string    `json:"run_id,omitempty"` // see types.Result
+}
+
+// SetOutputDir writes each accepted match to dir as <address>.json, named by its checksummed
+// address. An address that already has a file, from this run or an earlier one, is skipped.
+func (m *Miner) SetOutputDir(dir string) {
+	m.outputDir = dir
+}
+
+// writeMatchFile writes match into the output directory. Caller must hold m.mu.
+func (m *Miner) writeMatchFile(match *types.Result) {
+	if m.outputDir == "" {
+		return
+	}
+	path := filepath.Join(m.outputDir, match.Address+".json")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if errors.Is(err, fs.ErrExist) {
+		m.logger.Printf("Skipping %s: already written", path)
+		return
+	}
+	if err != nil {
+		m.logger.Printf("Failed to write match file: %v", err)
+		return
+	}
+	defer file.Close()
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(matchFile{
+		Address:      match.Address,
+		Salt:         match.Salt,
+		InitCodeHash: match.InitCodeHash,
+		Factory:      match.Factory,
+		Create2Salt:  match.Create2Salt,
+		Timestamp:    m.now().UTC(),
+		RunID:        match.RunID,
+	})
+	if err != nil {
+		m.logger.Printf("Failed to write match file: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package miner
+
+import (
+	"sync"
+	"time"
+)
+
+// pauseState parks workers between batches while mining is paused
+type pauseState struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	paused bool
+	since  time.Time     // start of the current pause
+	total  time.Duration // length of completed pauses
+}
+
+// Pause parks the workers after their current batch, freeing the CPU until Resume.
+// Progress is kept, and paused time is excluded from the reported rate.
+func (m *Miner) Pause() {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	if m.pause.paused {
+		return
+	}
+	m.pause.paused = true
+	m.pause.since = m.now()
+	m.logger.Printf("Mining paused")
+}
+
+// Resume wakes workers parked by Pause
+func (m *Miner) Resume() {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	if !m.pause.paused {
+		return
+	}
+	m.pause.paused = false
+	m.pause.total += m.now().Sub(m.pause.since)
+	m.pause.cond.Broadcast()
+	m.logger.Printf("Mining resumed")
+}
+
+// Paused returns true between Pause and Resume
+func (m *Miner) Paused() bool {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	return m.pause.paused
+}
+
+// pausedFor returns the total time spent paused up to now
+func (m *Miner) pausedFor(now time.Time) time.Duration {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	total := m.pause.total
+	if m.pause.paused {
+		total += now.Sub(m.pause.since)
+	}
+	return total
+}
+
+// waitWhilePaused blocks while mining is paused and not stopped. It returns true if it
+// waited, so the caller re-checks for a stop before starting another batch.
+func (m *Miner) waitWhilePaused() bool {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	waited := false
+	for m.pause.paused && !m.stopped() {
+		m.pause.cond.Wait()
+		waited = true
+	}
+	return waited
+}
+
+// wakePaused releases parked workers so they notice a stop
+func (m *Miner) wakePaused() {
+	m.pause.mu.Lock()
+	defer m.pause.mu.Unlock()
+	m.pause.cond.Broadcast()
+}
+
+// stopped reports whether Stop has been called. Workers check it once per batch; an atomic
+// load is cheaper than a select on done and never contends with other workers.
+func (m *Miner) stopped() bool {
+	return m.stopping.Load()
+}
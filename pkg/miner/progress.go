@@ -0,0 +1,37 @@
+package miner
+
+// progressSampling thins verbose progress lines for --progress-every and
+// --progress-on-improve. It is only touched by the periodic logger goroutine.
+type progressSampling struct {
+	ticks     int64 // progress ticks so far
+	expected  int64 // expected attempts per match, 0 in scoring modes or when it overflows
+	changes   int   // bestChanges at the last logged line
+	tier      int   // tierReached at the last logged line
+	milestone int64 // whole multiples of expected passed at the last logged line
+}
+
+// shouldLogProgress reports whether this progress tick logs a status line. With
+// --progress-on-improve a line is logged when the best result improved, a longer prefix
+// was reached or another multiple of the expected attempts passed since the last line;
+// --progress-every N then adds a heartbeat every Nth tick. Alone, --progress-every N
+// logs every Nth tick.
+func (m *Miner) shouldLogProgress() bool {
+	s := &m.sampling
+	s.ticks++
+	every := int64(m.config.ProgressEvery)
+	periodic := every <= 1 || s.ticks%every == 0
+	if !m.config.ProgressOnImprove {
+		return periodic
+	}
+
+	m.mu.RLock()
+	changes, tier := m.bestChanges, m.tierReached
+	m.mu.RUnlock()
+	milestone := int64(0)
+	if s.expected > 0 {
+		milestone = m.Attempts() / s.expected
+	}
+	improved := changes != s.changes || tier != s.tier || milestone != s.milestone
+	s.changes, s.tier, s.milestone = changes, tier, milestone
+	return improved || every > 0 && s.ticks%every == 0
+}
@@ -0,0 +1,61 @@
+package miner
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// addReproduction fills in the factory, init code hash and, for CreateX, the guarded salt,
+// so the result can be checked by Reproduce whatever salt mode produced it
+func (m *Miner) addReproduction(out *types.Result, result *types.WorkerResult) {
+	wc := m.workerConfig
+	if wc == nil {
+		return
+	}
+	createX := wc.UseCreateX
+	if out.Factory == "" {
+		out.Factory = crypto.AddressBytesToChecksumString(wc.FactoryBytes)
+	} else if out.Factory != wc.FactoryAddress {
+		for _, f := range wc.ExtraFactories {
+			if f.Address == out.Factory {
+				createX = f.CreateX
+			}
+		}
+	}
+	out.InitCodeHash = hex.EncodeToString(wc.Create2Suffix)
+	if createX {
+		salt := result.SaltBytes
+		var guarded [32]byte
+		crypto.CreateXGuardedSaltInto(wc.NewHasher(), wc.CreateXGuard, wc.CreateXSender, wc.ChainID, &salt, &guarded)
+		out.Create2Salt = hex.EncodeToString(guarded[:])
+	}
+}
+
+// Reproduce recomputes a result's address from its factory, salt (Create2Salt when set) and
+// init code hash alone, without trusting the salt generator. It returns the recomputed
+// checksummed address and whether it equals result.Address; ok is also false when the
+// reproduction data is missing or malformed. A CreateX guarded salt is taken as given.
+func Reproduce(result *types.Result) (string, bool) {
+	factory, err := crypto.MustAddressBytes(result.Factory)
+	if err != nil {
+		return "", false
+	}
+	saltHex := result.Salt
+	if result.Create2Salt != "" {
+		saltHex = result.Create2Salt
+	}
+	salt, err := hex.DecodeString(strings.TrimPrefix(saltHex, "0x"))
+	if err != nil || len(salt) != 32 {
+		return "", false
+	}
+	initCodeHash, err := hex.DecodeString(strings.TrimPrefix(result.InitCodeHash, "0x"))
+	if err != nil || len(initCodeHash) != 32 {
+		return "", false
+	}
+
+	address := crypto.CalculateCreate2AddressFor(factory, initCodeHash, salt)
+	return address, strings.EqualFold(address, result.Address)
+}
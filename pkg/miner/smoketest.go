@@ -0,0 +1,69 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/logger"
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// SmokeTestPrefix is the pattern a smoke test mines: one byte, about 256 attempts
+const SmokeTestPrefix = "00"
+
+// smokeTestAttempts caps a smoke test. Missing a 1-in-256 pattern for this long means the
+// pipeline is broken, not unlucky.
+const smokeTestAttempts = 100000
+
+var ErrSmokeTest = errors.New("smoke test failed")
+
+// SmokeTestConfig returns a config mining SmokeTestPrefix with the init code, factories, CreateX
+// guard and salt generation of cfg, and none of its patterns, outputs or deployment
+func SmokeTestConfig(cfg *config.Config) *config.Config {
+	smoke := config.NewConfig()
+	smoke.Prefix = SmokeTestPrefix
+	smoke.Workers = 1
+	smoke.MaxAttempts = smokeTestAttempts
+	smoke.LogInterval = cfg.LogInterval
+	smoke.KeccakBackend = cfg.KeccakBackend
+
+	smoke.Bytecode = cfg.Bytecode
+	smoke.BytecodeFiles = cfg.BytecodeFiles
+	smoke.ConstructorArgs = cfg.ConstructorArgs
+	smoke.InitCodeHash = cfg.InitCodeHash
+	smoke.Mode = cfg.Mode
+	smoke.Factories = cfg.Factories
+	smoke.FactoryKind = cfg.FactoryKind
+	smoke.CreateXGuard = cfg.CreateXGuard
+	smoke.CreateXSender = cfg.CreateXSender
+	smoke.ChainID = cfg.ChainID
+
+	smoke.SaltMode = cfg.SaltMode
+	smoke.ResumeFrom = cfg.ResumeFrom
+	smoke.SaltLabel = cfg.SaltLabel
+	smoke.Entropy = cfg.Entropy
+	smoke.EntropyFile = cfg.EntropyFile
+	smoke.MnemonicFile = cfg.MnemonicFile
+	smoke.DerivationIndex = cfg.DerivationIndex
+	return smoke
+}
+
+// SmokeTest mines SmokeTestPrefix through the same pipeline as cfg and checks the match with
+// Reproduce, so a wrong bytecode, factory or guard shows up in seconds rather than after a
+// long run. The returned error wraps ErrSmokeTest when the pipeline is at fault.
+func SmokeTest(cfg *config.Config, log *logger.Logger) (*types.Result, error) {
+	smoke := SmokeTestConfig(cfg)
+	if err := smoke.Validate(); err != nil {
+		return nil, err
+	}
+	m := NewMiner(smoke, log)
+	result := m.Mine()
+	if result == nil {
+		return nil, fmt.Errorf("%w: no %q prefix in %d attempts", ErrSmokeTest, SmokeTestPrefix, smokeTestAttempts)
+	}
+	if address, ok := Reproduce(result); !ok {
+		return result, fmt.Errorf("%w: salt 0x%s gives %s on the reference path, not %s", ErrSmokeTest, result.Salt, address, result.Address)
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package miner
+
+import (
+	"sync/atomic"
+
+	"github.com/screa/erc2470-address-miner/internal/checkpoint"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+)
+
+// SetStateFile rewrites a random-mode state file at path on each progress tick and when
+// mining ends. When restarting, prior carries the earlier runs' state: attempt totals
+// accumulate and, in scoring modes, its best result is the one to beat.
+func (m *Miner) SetStateFile(path string, prior *checkpoint.State) {
+	m.statePath = path
+	if prior == nil {
+		return
+	}
+	m.priorAttempts = prior.Attempts
+	if prior.Best == nil || !m.config.TracksBest() {
+		return
+	}
+	addr, err := crypto.MustAddressBytes(prior.Best.Address)
+	if err != nil {
+		m.logger.Printf("Warning: ignoring the best result in %s: %v", path, err)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bestResult = prior.Best
+	m.bestResultBytes = [20]byte(addr)
+	m.bestScore = m.score(m.bestResultBytes)
+}
+
+// State returns the attempt total and best result for --state-file
+func (m *Miner) State() *checkpoint.State {
+	st := &checkpoint.State{
+		Version:   checkpoint.StateVersion,
+		Target:    m.config.GetTargetDescription(),
+		Attempts:  m.priorAttempts + atomic.LoadInt64(&m.attempts),
+		UpdatedAt: m.now(),
+	}
+	m.mu.RLock()
+	st.Best = m.bestResult
+	m.mu.RUnlock()
+	return st
+}
+
+// writeState saves the current state, logging rather than failing the run on error
+func (m *Miner) writeState() {
+	if err := checkpoint.SaveState(m.statePath, m.State()); err != nil {
+		m.logger.Printf("Failed to write state file: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package miner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// StreamBuffer is the capacity of the channel returned by Stream
+const StreamBuffer = 4096
+
+// resultStream is the subscriber side of Stream; mu keeps workers from sending on a closed channel
+type resultStream struct {
+	mu     sync.RWMutex
+	ch     chan types.WorkerResult
+	closed bool
+}
+
+// Stream emits every candidate the workers generate, matching or not, until ctx is cancelled
+// or mining ends; the channel is closed then. Call it before Mine to see the run from its
+// first attempt.
+//
+// Workers never block on the stream: when the buffer of StreamBuffer candidates is full the
+// candidate is dropped and counted in StreamDropped, so a slow consumer sees a sample of the
+// run rather than slowing it down. Candidates are also dropped while --max-memory has paused
+// the stream. Non-matching candidates carry only SaltBytes and
+// AddressBytes; Salt and Address are set for matches.
+func (m *Miner) Stream(ctx context.Context) <-chan types.WorkerResult {
+	s := &resultStream{ch: make(chan types.WorkerResult, StreamBuffer)}
+	if old := m.stream.Swap(s); old != nil {
+		old.close()
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-m.done:
+		}
+		m.stream.CompareAndSwap(s, nil)
+		s.close()
+	}()
+	return s.ch
+}
+
+// StreamDropped returns how many candidates were dropped because the stream buffer was full
+func (m *Miner) StreamDropped() int64 {
+	return atomic.LoadInt64(&m.streamDropped)
+}
+
+// emitStream offers a candidate to the stream subscriber, if any, without blocking
+func (m *Miner) emitStream(result *types.WorkerResult) {
+	s := m.stream.Load()
+	if s == nil {
+		return
+	}
+	if m.memory.streamPaused.Load() {
+		atomic.AddInt64(&m.streamDropped, 1)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- *result:
+	default:
+		atomic.AddInt64(&m.streamDropped, 1)
+	}
+}
+
+// closeStream ends the stream once the workers have exited
+func (m *Miner) closeStream() {
+	if s := m.stream.Swap(nil); s != nil {
+		s.close()
+	}
+}
+
+func (s *resultStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
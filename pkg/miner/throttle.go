@@ -0,0 +1,52 @@
+package miner
+
+import "time"
+
+// throttleSlice is how much busy time a throttled worker accumulates before it sleeps. Short
+// enough that the machine stays responsive, long enough that timer overhead is negligible.
+const throttleSlice = 50 * time.Millisecond
+
+// dutyCycle holds a worker to roughly percent of one CPU: once a slice of batches has run,
+// it sleeps long enough that the busy time makes up percent of the wall clock
+type dutyCycle struct {
+	percent int
+	now     func() time.Time
+	sleep   func(time.Duration)
+
+	busy  time.Duration // batch time since the last sleep
+	start time.Time     // start of the current batch
+}
+
+// newDutyCycle returns a duty cycle for --throttle, or nil when percent leaves the worker at
+// full speed
+func newDutyCycle(percent int, now func() time.Time, sleep func(time.Duration)) *dutyCycle {
+	if percent <= 0 || percent >= 100 {
+		return nil
+	}
+	return &dutyCycle{percent: percent, now: now, sleep: sleep}
+}
+
+// batchStart marks the start of a batch. Time outside batches, such as a pause, is not counted.
+func (d *dutyCycle) batchStart() {
+	d.start = d.now()
+}
+
+// batchDone records the batch that just ended and sleeps once a slice has accumulated
+func (d *dutyCycle) batchDone() {
+	d.busy += d.now().Sub(d.start)
+	if d.busy < throttleSlice {
+		return
+	}
+	d.sleep(d.busy * time.Duration(100-d.percent) / time.Duration(d.percent))
+	d.busy = 0
+}
+
+// sleepUnlessStopped sleeps for d, returning early when the miner stops
+func (m *Miner) sleepUnlessStopped(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-m.done:
+	}
+}
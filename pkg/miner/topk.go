@@ -0,0 +1,103 @@
+package miner
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// candidate is a scored address kept in a top-K heap
+type candidate struct {
+	result *types.WorkerResult
+	score  int
+}
+
+// topK is a bounded min-heap holding a worker's K best candidates; the root is the worst kept
+type topK struct {
+	m     *Miner
+	k     int
+	items []candidate
+}
+
+func (m *Miner) newTopK() *topK {
+	return &topK{m: m, k: m.config.TopK, items: make([]candidate, 0, m.config.TopK)}
+}
+
+func (t *topK) Len() int           { return len(t.items) }
+func (t *topK) Less(i, j int) bool { return t.m.beats(t.items[j], t.items[i]) }
+func (t *topK) Swap(i, j int)      { t.items[i], t.items[j] = t.items[j], t.items[i] }
+func (t *topK) Push(x any)         { t.items = append(t.items, x.(candidate)) }
+func (t *topK) Pop() any {
+	last := t.items[len(t.items)-1]
+	t.items = t.items[:len(t.items)-1]
+	return last
+}
+
+// offer keeps the result if the heap has room or it beats the worst candidate kept
+func (t *topK) offer(result *types.WorkerResult) {
+	if k := t.m.keptTopK(); k < t.k {
+		t.shrink(k)
+	}
+	c := candidate{result: result, score: t.m.score(result.AddressBytes)}
+	if len(t.items) < t.k {
+		heap.Push(t, c)
+		if t.m.memory.limit > 0 {
+			t.m.memory.retained.Add(1)
+		}
+		return
+	}
+	if t.m.beats(c, t.items[0]) {
+		t.items[0] = c
+		heap.Fix(t, 0)
+	}
+}
+
+// shrink drops the worst candidates until at most k remain, for --max-memory, and moves the
+// rest to a smaller backing array so the old one can be collected
+func (t *topK) shrink(k int) {
+	dropped := 0
+	for len(t.items) > k {
+		heap.Pop(t)
+		dropped++
+	}
+	t.m.memory.retained.Add(int64(-dropped))
+	t.items = append(make([]candidate, 0, k), t.items...)
+	t.k = k
+}
+
+// beats reports whether candidate a ranks above b in the active scoring mode
+func (m *Miner) beats(a, b candidate) bool {
+	if m.config.HigherScoreWins() {
+		return a.score > b.score
+	}
+	if m.closestTo != nil {
+		return m.closerTo(a.result.AddressBytes, b.result.AddressBytes)
+	}
+	return m.isBetterBytes(a.result.AddressBytes, b.result.AddressBytes)
+}
+
+// mergeTopK folds a finished worker's heap into the run's top results, keeping the best K
+func (m *Miner) mergeTopK(t *topK) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	merged := append(m.topResults, t.items...)
+	sort.SliceStable(merged, func(i, j int) bool { return m.beats(merged[i], merged[j]) })
+	if k := m.keptTopK(); len(merged) > k {
+		merged = merged[:k]
+	}
+	m.topResults = merged
+}
+
+// TopResults returns up to --top-k best results of the run, best first. Complete once Mine returns.
+// Fewer are kept if --max-memory had to trim them.
+func (m *Miner) TopResults() []*types.Result {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := make([]*types.Result, len(m.topResults))
+	for i, c := range m.topResults {
+		results[i] = m.toResult(c.result)
+		results[i].Score = c.score
+	}
+	return results
+}
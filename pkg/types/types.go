@@ -1,13 +1,57 @@
 package types
 
-import "time"
+import (
+	"hash"
+	"io"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+)
 
 // Result represents a mining result
 type Result struct {
-	Salt     string
-	Address  string
-	Attempts int64
-	Duration time.Duration
+	Salt           string        `json:"salt"`
+	Address        string        `json:"address"`                   // EIP-55 checksummed
+	AddressLower   string        `json:"address_lower"`             // the same address in lowercase hex
+	ExtraAddresses []string      `json:"extra_addresses,omitempty"` // addresses under additional init codes
+	Attempts       int64         `json:"attempts"`
+	Duration       time.Duration `json:"duration"`
+	Score          int           `json:"score"`                // score in the active scoring mode (leading zero nibbles by default)
+	SaltLabel      string        `json:"salt_label,omitempty"` // ASCII label spelled by the leading salt bytes
+	RunID          string        `json:"run_id,omitempty"`     // the run that found it, see --run-id
+
+	// DerivationIndex is the hardened child index m/i' the salt was derived at in hd salt mode
+	DerivationIndex *uint32 `json:"derivation_index,omitempty"`
+
+	// Reproduction data, independent of how the salt was generated: Address is
+	// keccak256(0xff ++ Factory ++ salt ++ InitCodeHash)[12:], where salt is Create2Salt when
+	// set and Salt otherwise. See miner.Reproduce.
+	Factory      string `json:"factory,omitempty"`       // the deployer the address is computed for
+	InitCodeHash string `json:"initcode_hash,omitempty"` // keccak256 of the init code, hex
+	Create2Salt  string `json:"create2_salt,omitempty"`  // the guarded salt CreateX passes to CREATE2, hex
+}
+
+// BestEvent records an improvement of the best result during a run
+type BestEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Attempts  int64     `json:"attempts"`
+	Salt      string    `json:"salt"`
+	Address   string    `json:"address"`
+	Score     int       `json:"score"` // see Result.Score
+}
+
+// Result output event types
+const (
+	OutputBest  = "best"  // the best result improved
+	OutputMatch = "match" // a distinct match was accepted
+	OutputFinal = "final" // mining ended; Result is the reported best or nil
+)
+
+// OutputEvent is one JSON line written to a miner's result writer
+type OutputEvent struct {
+	Type      string    `json:"type"` // best, match or final
+	Timestamp time.Time `json:"timestamp"`
+	Result    *Result   `json:"result"`
 }
 
 // WorkerConfig contains configuration for individual workers
@@ -20,18 +64,97 @@ type WorkerConfig struct {
 	Verbose      bool
 
 	// Pre-decoded for fast byte-level matching (hot path). Nil if not set.
-	PrefixBytes   []byte // first N bytes of address must match
-	SuffixBytes   []byte // last N bytes of address must match
-	Create2Prefix []byte // 21 bytes: 0xff + factory, constant per run
-	Create2Suffix []byte // 32 bytes: initcode hash, constant per run
+	PrefixBytes   []byte   // first N bytes of address must match
+	PrefixOffset  int      // hex characters skipped before PrefixBytes is compared
+	SuffixBytes   []byte   // last N bytes of address must match
+	SuffixOdd     bool     // suffix has an odd nibble count; only the low nibble of SuffixBytes[0] is compared
+	TargetBytes   []byte   // all 20 bytes of address must match
+	TemplateMask  []byte   // fixed template nibbles; addr[i]&mask[i] must equal TemplateValue[i]
+	TemplateValue []byte   // template nibble values under TemplateMask
+	Palindrome    bool     // hex nibbles must read the same both ways
+	PalindromeCS  bool     // the EIP-55 checksummed string must also mirror its casing
+	MinRun        int      // longest run of identical nibbles must be at least this long (0 = off)
+	LowZeroBits   int      // low bits of the address integer that must be zero (0 = off)
+	PrefixBits    int      // top bits of the address that must equal PrefixBitsOf (0 = off)
+	PrefixBitsOf  []byte   // left-aligned pattern holding at least PrefixBits bits
+	AllSame       bool     // every nibble must be the same
+	TrackTiers    bool     // report PrefixNibbles on non-matching candidates for near-miss milestones
+	Create2Prefix []byte   // 21 bytes: 0xff + factory, constant per run
+	Create2Suffix []byte   // 32 bytes: initcode hash, constant per run
+	ExtraSuffixes [][]byte // init code hashes that must also match under the same salt
+
+	// Factories tried in turn when the primary factory (Create2Prefix, UseCreateX) does not
+	// match. FactoryAddress names the primary in results; both are unset for a single factory.
+	ExtraFactories []FactoryTarget
+	FactoryAddress string
+
+	// MatchExpr is the compiled --match-expr, ANDed with the other criteria; nil if not set
+	MatchExpr *crypto.MatchExpr
+
+	// SaltBytes, when nonzero, zeroes all but this many trailing bytes of every generated salt
+	SaltBytes int
+
+	// NumericProperty is the compiled --numeric-property, ANDed the same way; nil if not set
+	NumericProperty *crypto.MatchExpr
+
+	// NiceSet is the --nice-set family: addresses scoring at least its threshold match; nil if not set
+	NiceSet *crypto.NiceSet
+
+	// CreateX salt guarding. Applied to the primary when UseCreateX is set, and to extra
+	// factories marked CreateX.
+	UseCreateX    bool
+	CreateXGuard  crypto.CreateXGuard
+	CreateXSender []byte // 20 bytes, required for GuardMsgSender
+	ChainID       uint64 // required for GuardCrossChain
+
+	// NewHasher builds each worker's keccak hasher; nil uses the default backend
+	NewHasher func() hash.Hash
+
+	// IncrementalKeccak hashes the primary CREATE2 preimage from a cloned state that has
+	// already absorbed the prefix (see crypto.Create2Sponge) instead of with NewHasher. It is
+	// ignored with ExtraFactories or ExtraSuffixes, which rehash the full preimage.
+	IncrementalKeccak bool
+
+	// Entropy seeds each worker's salt PRNG; nil uses crypto/rand. After SeedRetries consecutive
+	// read failures (0 = worker.DefaultSeedRetries) the worker falls back to a ChaCha20 generator.
+	Entropy     io.Reader
+	SeedRetries int
+
+	// HDSalts derives salts from a mnemonic in hd salt mode; each worker clones it
+	HDSalts *crypto.HDSaltDeriver
+
+	// SaltLabel is written over the leading bytes of every salt, whatever the salt mode
+	SaltLabel []byte
+
+	// AlignedPrefix holds the --prefix-bytes the address must start with, compared a whole
+	// byte at a time from the first byte. Nil if not set.
+	AlignedPrefix []byte
+
+	// TargetSet holds the --target-file addresses, mapped to their line numbers; the address
+	// must equal one of them. Nil if not set.
+	TargetSet map[[20]byte]int
+}
+
+// FactoryTarget is an additional factory a worker computes the address under
+type FactoryTarget struct {
+	Address       string // EIP-55 checksummed, reported on a match
+	Create2Prefix []byte // 21 bytes: 0xff + factory
+	CreateX       bool   // the CREATE2 salt is the CreateX guarded salt
 }
 
 // WorkerResult represents a result from a single worker
 type WorkerResult struct {
-	Salt         string    // hex-encoded, only set when needed for output
-	SaltBytes    [32]byte  // raw salt for building Salt when updating best
-	Address      string    // EIP-55 checksummed, only set when needed for output
-	AddressBytes [20]byte  // raw 20-byte address for comparison
-	Attempts     int64
-	IsMatch      bool
+	Salt           string   // hex-encoded, only set when needed for output
+	SaltBytes      [32]byte // raw salt for building Salt when updating best
+	Address        string   // EIP-55 checksummed, only set when needed for output
+	AddressBytes   [20]byte // raw 20-byte address for comparison
+	ExtraAddresses []string // checksummed addresses under ExtraSuffixes, only set on match
+	Attempts       int64
+	IsMatch        bool
+	PrefixNibbles  int // leading prefix characters matched, only set with TrackTiers
+
+	HDSalt          bool   // salt was derived in hd salt mode
+	DerivationIndex uint32 // child index of the salt when HDSalt is set
+
+	Factory string // matching factory when several are mined, only set on match
 }
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/screa/erc2470-address-miner/internal/crypto"
+	"golang.org/x/crypto/chacha20"
+)
+
+// DefaultSeedRetries is how many consecutive entropy read failures a worker tolerates
+// before seeding from the userspace fallback generator
+const DefaultSeedRetries = 3
+
+// fallbackRNG is a process-wide ChaCha20 keystream used when the system entropy source
+// fails. It is shared so every worker still receives a distinct seed.
+var fallbackRNG struct {
+	mu     sync.Mutex
+	cipher *chacha20.Cipher
+}
+
+// readSeed reads an 8-byte PRNG seed from r, retrying up to retries times. If every read
+// fails it draws the seed from the ChaCha20 fallback and reports fallback=true.
+func readSeed(r io.Reader, retries int) (seed uint64, fallback bool) {
+	if retries <= 0 {
+		retries = DefaultSeedRetries
+	}
+	var buf [8]byte
+	for i := 0; i < retries; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err == nil {
+			return binary.LittleEndian.Uint64(buf[:]), false
+		}
+	}
+	return fallbackSeed(), true
+}
+
+// fallbackSeed returns the next 8 bytes of the fallback keystream, keying it on first use
+// from whatever entropy is at hand: the clock, the process ID and any bytes rand can spare
+func fallbackSeed() uint64 {
+	fallbackRNG.mu.Lock()
+	defer fallbackRNG.mu.Unlock()
+
+	if fallbackRNG.cipher == nil {
+		var material [8 + 8 + 16]byte
+		binary.LittleEndian.PutUint64(material[0:], uint64(time.Now().UnixNano()))
+		binary.LittleEndian.PutUint64(material[8:], uint64(os.Getpid()))
+		_, _ = rand.Read(material[16:]) // best effort; may be all zero
+		key := crypto.Keccak256(material[:])
+		c, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+		if err != nil {
+			panic("chacha20: " + err.Error()) // key and nonce sizes are fixed
+		}
+		fallbackRNG.cipher = c
+	}
+
+	var buf [8]byte
+	fallbackRNG.cipher.XORKeyStream(buf[:], buf[:])
+	return binary.LittleEndian.Uint64(buf[:])
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/screa/erc2470-address-miner/pkg/types"
+)
+
+// failingReader simulates a broken entropy source, counting the reads attempted
+type failingReader struct {
+	reads int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return 0, errors.New("entropy unavailable")
+}
+
+func TestSeedFallbackOnFailingReader(t *testing.T) {
+	reader := &failingReader{}
+	config := &types.WorkerConfig{
+		Create2Prefix: make([]byte, 21),
+		Create2Suffix: make([]byte, 32),
+		Entropy:       reader,
+		SeedRetries:   5,
+	}
+
+	attempts := int64(0)
+	w1 := NewWorker(config, &attempts)
+	w2 := NewWorker(config, &attempts)
+	if reader.reads != 10 {
+		t.Errorf("entropy reads = %d, want 5 retries per worker", reader.reads)
+	}
+	if !w1.SeedFallback() || !w2.SeedFallback() {
+		t.Fatal("workers with a failing entropy source did not use the fallback")
+	}
+
+	// The fallback must still give each worker its own salt stream
+	r1 := w1.GenerateAddress()
+	r2 := w2.GenerateAddress()
+	if r1.SaltBytes == r2.SaltBytes {
+		t.Errorf("fallback-seeded workers produced the same salt %x", r1.SaltBytes)
+	}
+	w1.Flush()
+	w2.Flush()
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestSeedWithoutFallback(t *testing.T) {
+	attempts := int64(0)
+	w := NewWorker(&types.WorkerConfig{}, &attempts)
+	if w.SeedFallback() {
+		t.Error("worker used the fallback with a working entropy source")
+	}
+}
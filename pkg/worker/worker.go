@@ -2,6 +2,7 @@ package worker
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"hash"
 	"sync/atomic"
@@ -18,35 +19,145 @@ type Worker struct {
 
 	// Per-worker hasher and buffers (zero allocations in hot path)
 	hasher   hash.Hash
+	sponge   *crypto.Create2Sponge // set with IncrementalKeccak
 	inputBuf [crypto.Create2InputLen]byte
 	hashBuf  [32]byte
 	addrBuf  [20]byte
 	saltBuf  [32]byte
+	guardBuf [32]byte // CreateX guarded salt
 	hexBuf   [64]byte
 
+	// Salt and address under the extra factory being tried
+	factorySalt [32]byte
+	factoryAddr [20]byte
+
 	// Fast PRNG state (wyrand-like) for salt generation without syscalls
 	prngState uint64
+
+	// pending counts attempts not yet added to the shared counter; see Flush
+	pending int64
+
+	// Sequential salt cursor; when stride is non-zero salts are cursor, cursor+stride, ...
+	cursor [32]byte
+	stride uint64
+
+	// HD salt derivation; when hd is set salts are the keys at m/hdIndex', advancing by hdStride
+	hd        *crypto.HDSaltDeriver
+	hdIndex   uint32
+	hdStride  uint32
+	lastIndex uint32 // index of the salt in saltBuf
+
+	// Hybrid salts stamp the run, the worker ID and a counter after the random bytes
+	hybrid      bool
+	hybridStamp uint64
+	hybridID    uint32
+	hybridCount uint64
+
+	// suffixOnly selects the tail-only match path when a suffix is the sole criterion
+	suffixOnly bool
+
+	// seedFallback is set when the entropy source failed and the PRNG was seeded from ChaCha20
+	seedFallback bool
 }
 
 // NewWorker creates a new worker instance
 func NewWorker(config *types.WorkerConfig, attempts *int64) *Worker {
+	newHasher := config.NewHasher
+	if newHasher == nil {
+		newHasher = sha3.NewLegacyKeccak256
+	}
 	w := &Worker{
 		config:   config,
 		attempts: attempts,
-		hasher:   sha3.NewLegacyKeccak256(),
+		hasher:   newHasher(),
+		suffixOnly: len(config.SuffixBytes) > 0 && len(config.PrefixBytes) == 0 && len(config.AlignedPrefix) == 0 &&
+			len(config.TemplateMask) == 0 && len(config.TargetBytes) == 0 && config.TargetSet == nil &&
+			!config.Palindrome && config.MinRun == 0 && config.LowZeroBits == 0 && config.PrefixBits == 0 && !config.AllSame &&
+			config.MatchExpr == nil && config.NumericProperty == nil && config.NiceSet == nil,
+	}
+	// The extra factory and init code checks hash the preimage left in inputBuf, which the
+	// sponge never writes
+	if config.IncrementalKeccak && len(config.ExtraFactories) == 0 && len(config.ExtraSuffixes) == 0 {
+		w.sponge = crypto.NewCreate2Sponge(config.Create2Prefix)
 	}
-	// Seed PRNG with crypto randomness once
-	var seed [8]byte
-	if _, err := rand.Read(seed[:]); err == nil {
-		w.prngState = uint64(seed[0]) | uint64(seed[1])<<8 | uint64(seed[2])<<16 | uint64(seed[3])<<24 |
-			uint64(seed[4])<<32 | uint64(seed[5])<<40 | uint64(seed[6])<<48 | uint64(seed[7])<<56
+	// Seed PRNG with crypto randomness once, falling back to ChaCha20 if the source fails
+	entropy := config.Entropy
+	if entropy == nil {
+		entropy = rand.Reader
 	}
+	w.prngState, w.seedFallback = readSeed(entropy, config.SeedRetries)
 	if w.prngState == 0 {
 		w.prngState = 1
 	}
 	return w
 }
 
+// SeedFallback reports whether the worker's PRNG was seeded from the fallback generator
+// because the entropy source kept failing
+func (w *Worker) SeedFallback() bool {
+	return w.seedFallback
+}
+
+// SetSaltCursor switches the worker to sequential salts starting at start and advancing by stride.
+func (w *Worker) SetSaltCursor(start [32]byte, stride uint64) {
+	w.cursor = start
+	w.stride = stride
+}
+
+// SetHDCursor switches the worker to HD-derived salts starting at child index start and
+// advancing by stride. Requires config.HDSalts.
+func (w *Worker) SetHDCursor(start, stride uint32) {
+	w.hd = w.config.HDSalts.Clone()
+	w.hdIndex = start
+	w.hdStride = stride
+}
+
+// SetHybridSalts switches random salts to the os-hybrid layout: 12 random bytes, then the
+// run stamp (8 bytes), the worker ID (4 bytes) and a per-worker counter (8 bytes), all
+// big-endian. Salts are unique across workers and across runs with distinct stamps, even
+// if the PRNG seed repeats.
+func (w *Worker) SetHybridSalts(runStamp uint64, workerID uint32) {
+	w.hybrid = true
+	w.hybridStamp = runStamp
+	w.hybridID = workerID
+	w.hybridCount = 0
+}
+
+// hybridSaltBytes fills w.saltBuf with the next os-hybrid salt
+func (w *Worker) hybridSaltBytes() {
+	u, v := w.fastRandUint64(), w.fastRandUint64()
+	binary.LittleEndian.PutUint64(w.saltBuf[0:8], u)
+	binary.LittleEndian.PutUint32(w.saltBuf[8:12], uint32(v))
+	binary.BigEndian.PutUint64(w.saltBuf[12:20], w.hybridStamp)
+	binary.BigEndian.PutUint32(w.saltBuf[20:24], w.hybridID)
+	binary.BigEndian.PutUint64(w.saltBuf[24:32], w.hybridCount)
+	w.hybridCount++
+}
+
+// nextSalt fills w.saltBuf with the next salt to try
+func (w *Worker) nextSalt() {
+	if w.hd != nil {
+		// Skip the rare indices BIP-32 declares invalid
+		for {
+			w.lastIndex = w.hdIndex
+			w.hdIndex += w.hdStride
+			if w.hd.SaltAt(w.lastIndex, &w.saltBuf) {
+				return
+			}
+		}
+	}
+	if w.stride == 0 {
+		if w.hybrid {
+			w.hybridSaltBytes()
+			return
+		}
+		w.fastSaltBytes()
+		return
+	}
+	w.saltBuf = w.cursor
+	crypto.AddToSalt(&w.cursor, w.stride)
+}
+
 // fastRandUint64 returns a random uint64 from the worker's PRNG (non-crypto, for salt exploration)
 func (w *Worker) fastRandUint64() uint64 {
 	w.prngState += 0x60bee2b3d4d4a6c5 // wyrand constant
@@ -73,36 +184,136 @@ func (w *Worker) saltHexString() string {
 	return string(w.hexBuf[:64])
 }
 
+// Flush adds the attempts made since the last flush to the shared counter. Callers flush
+// after each batch, so workers do not contend on the counter for every attempt.
+func (w *Worker) Flush() {
+	if w.pending > 0 {
+		atomic.AddInt64(w.attempts, w.pending)
+		w.pending = 0
+	}
+}
+
 // GenerateAddress generates a single address and checks if it matches criteria (fast path).
 func (w *Worker) GenerateAddress() *types.WorkerResult {
-	w.fastSaltBytes()
-	// Build CREATE2 input: prefix(21) + salt(32) + suffix(32)
-	copy(w.inputBuf[0:crypto.Create2PrefixLen], w.config.Create2Prefix)
-	copy(w.inputBuf[crypto.Create2PrefixLen:crypto.Create2PrefixLen+32], w.saltBuf[:])
-	copy(w.inputBuf[crypto.Create2PrefixLen+32:], w.config.Create2Suffix)
-
-	crypto.Create2AddressInto(w.hasher, w.inputBuf[:], w.hashBuf[:], w.addrBuf[:])
+	w.nextSalt()
+	if n := w.config.SaltBytes; n > 0 {
+		clear(w.saltBuf[:32-n])
+	}
+	if len(w.config.SaltLabel) > 0 {
+		copy(w.saltBuf[:], w.config.SaltLabel)
+	}
+	create2Salt := &w.saltBuf
+	if w.config.UseCreateX {
+		// The reported salt carries the guard flags; CREATE2 sees the guarded salt
+		crypto.ApplyCreateXSaltFlags(w.config.CreateXGuard, w.config.CreateXSender, &w.saltBuf)
+		crypto.CreateXGuardedSaltInto(w.hasher, w.config.CreateXGuard, w.config.CreateXSender, w.config.ChainID, &w.saltBuf, &w.guardBuf)
+		create2Salt = &w.guardBuf
+	}
+	if w.sponge != nil {
+		w.sponge.AddressInto(create2Salt, w.config.Create2Suffix, w.addrBuf[:])
+	} else {
+		// Build CREATE2 input: prefix(21) + salt(32) + suffix(32)
+		copy(w.inputBuf[0:crypto.Create2PrefixLen], w.config.Create2Prefix)
+		copy(w.inputBuf[crypto.Create2PrefixLen:crypto.Create2PrefixLen+32], create2Salt[:])
+		copy(w.inputBuf[crypto.Create2PrefixLen+32:], w.config.Create2Suffix)
+		crypto.Create2AddressInto(w.hasher, w.inputBuf[:], w.hashBuf[:], w.addrBuf[:])
+	}
 
-	// Batched atomic: add 1 to global every attempt (keep exact count for simplicity; could batch later)
-	atomic.AddInt64(w.attempts, 1)
+	// Counted locally; the shared counter is updated once per batch by Flush
+	w.pending++
 
-	isMatch := w.matchesBytes(w.addrBuf[:])
+	isMatch := w.matchAddress(w.addrBuf[:])
+	var extraAddrs []string
+	if isMatch && len(w.config.ExtraSuffixes) > 0 {
+		extraAddrs, isMatch = w.matchExtraSuffixes()
+	}
+	factory := w.config.FactoryAddress
+	if !isMatch && len(w.config.ExtraFactories) > 0 {
+		primarySalt, primaryAddr := w.saltBuf, w.addrBuf
+		factory, extraAddrs, isMatch = w.matchExtraFactories()
+		if isMatch {
+			w.saltBuf, w.addrBuf = w.factorySalt, w.factoryAddr
+		} else {
+			w.saltBuf, w.addrBuf = primarySalt, primaryAddr
+		}
+	}
 	if !isMatch {
 		return &types.WorkerResult{
-			SaltBytes:    w.saltBuf,
-			AddressBytes: w.addrBuf,
-			Attempts:     atomic.LoadInt64(w.attempts),
-			IsMatch:      false,
+			SaltBytes:       w.saltBuf,
+			AddressBytes:    w.addrBuf,
+			Attempts:        atomic.LoadInt64(w.attempts) + w.pending,
+			IsMatch:         false,
+			PrefixNibbles:   w.prefixNibbles(),
+			HDSalt:          w.hd != nil,
+			DerivationIndex: w.lastIndex,
 		}
 	}
 	return &types.WorkerResult{
-		Salt:         w.saltHexString(),
-		SaltBytes:    w.saltBuf,
-		Address:      crypto.AddressBytesToChecksumString(w.addrBuf[:]),
-		AddressBytes: w.addrBuf,
-		Attempts:     atomic.LoadInt64(w.attempts),
-		IsMatch:      true,
+		Salt:            w.saltHexString(),
+		SaltBytes:       w.saltBuf,
+		Address:         crypto.AddressBytesToChecksumString(w.addrBuf[:]),
+		AddressBytes:    w.addrBuf,
+		ExtraAddresses:  extraAddrs,
+		Attempts:        atomic.LoadInt64(w.attempts) + w.pending,
+		IsMatch:         true,
+		HDSalt:          w.hd != nil,
+		DerivationIndex: w.lastIndex,
+		Factory:         factory,
+	}
+}
+
+// matchAddress checks an address against the criteria, taking the suffix fast path when it applies
+func (w *Worker) matchAddress(addr []byte) bool {
+	if w.suffixOnly {
+		return w.matchSuffix(addr)
 	}
+	return w.matchesBytes(addr)
+}
+
+// matchExtraFactories computes the address under each extra factory with the current salt and
+// returns the first factory that matches, with the addresses under any extra init codes. The
+// match is left in factoryAddr and the salt to deploy it with (CreateX flags applied) in factorySalt.
+func (w *Worker) matchExtraFactories() (string, []string, bool) {
+	for _, f := range w.config.ExtraFactories {
+		w.factorySalt = w.saltBuf
+		create2Salt := &w.factorySalt
+		if f.CreateX {
+			crypto.ApplyCreateXSaltFlags(w.config.CreateXGuard, w.config.CreateXSender, &w.factorySalt)
+			crypto.CreateXGuardedSaltInto(w.hasher, w.config.CreateXGuard, w.config.CreateXSender, w.config.ChainID, &w.factorySalt, &w.guardBuf)
+			create2Salt = &w.guardBuf
+		}
+		copy(w.inputBuf[0:crypto.Create2PrefixLen], f.Create2Prefix)
+		copy(w.inputBuf[crypto.Create2PrefixLen:crypto.Create2PrefixLen+32], create2Salt[:])
+		crypto.Create2AddressInto(w.hasher, w.inputBuf[:], w.hashBuf[:], w.factoryAddr[:])
+		if !w.matchAddress(w.factoryAddr[:]) {
+			continue
+		}
+		if len(w.config.ExtraSuffixes) == 0 {
+			return f.Address, nil, true
+		}
+		if extraAddrs, ok := w.matchExtraSuffixes(); ok {
+			return f.Address, extraAddrs, true
+		}
+	}
+	return "", nil, false
+}
+
+// matchExtraSuffixes recomputes the address under each additional init code hash with the current
+// salt (already in inputBuf) and reports whether all of them match. The input buffer is restored.
+func (w *Worker) matchExtraSuffixes() ([]string, bool) {
+	var addr [20]byte
+	addrs := make([]string, 0, len(w.config.ExtraSuffixes))
+	defer copy(w.inputBuf[crypto.Create2PrefixLen+32:], w.config.Create2Suffix)
+
+	for _, suffix := range w.config.ExtraSuffixes {
+		copy(w.inputBuf[crypto.Create2PrefixLen+32:], suffix)
+		crypto.Create2AddressInto(w.hasher, w.inputBuf[:], w.hashBuf[:], addr[:])
+		if !w.matchesBytes(addr[:]) {
+			return nil, false
+		}
+		addrs = append(addrs, crypto.AddressBytesToChecksumString(addr[:]))
+	}
+	return addrs, true
 }
 
 // matchesBytes performs pattern matching on raw 20-byte address (no string allocation)
@@ -110,27 +321,152 @@ func (w *Worker) matchesBytes(addr []byte) bool {
 	if len(addr) != 20 {
 		return false
 	}
+	// Without any criteria (pure scoring modes) nothing is a match
+	hasCriteria := false
 	if len(w.config.PrefixBytes) > 0 {
-		n := len(w.config.PrefixBytes)
-		if n > 20 {
-			n = 20
+		hasCriteria = true
+		if !w.matchPrefix(addr) {
+			return false
 		}
-		if !equalBytes(addr[:n], w.config.PrefixBytes) {
+	}
+	if n := len(w.config.AlignedPrefix); n > 0 {
+		hasCriteria = true
+		if !equalBytes(addr[:n], w.config.AlignedPrefix) {
 			return false
 		}
 	}
 	if len(w.config.SuffixBytes) > 0 {
-		n := len(w.config.SuffixBytes)
-		if n > 20 {
-			n = 20
+		hasCriteria = true
+		if !w.matchSuffix(addr) {
+			return false
+		}
+	}
+	if len(w.config.TemplateMask) > 0 {
+		hasCriteria = true
+		for i, m := range w.config.TemplateMask {
+			if addr[i]&m != w.config.TemplateValue[i] {
+				return false
+			}
+		}
+	}
+	if len(w.config.TargetBytes) > 0 {
+		hasCriteria = true
+		if !equalBytes(addr, w.config.TargetBytes) {
+			return false
+		}
+	}
+	if w.config.TargetSet != nil {
+		hasCriteria = true
+		if _, ok := w.config.TargetSet[[20]byte(addr)]; !ok {
+			return false
+		}
+	}
+	if w.config.MinRun > 0 {
+		hasCriteria = true
+		if crypto.LongestRunBytes(addr) < w.config.MinRun {
+			return false
 		}
-		if !equalBytes(addr[20-n:], w.config.SuffixBytes) {
+	}
+	if w.config.LowZeroBits > 0 {
+		hasCriteria = true
+		if crypto.TrailingZeroBits(addr) < w.config.LowZeroBits {
+			return false
+		}
+	}
+	if w.config.PrefixBits > 0 {
+		hasCriteria = true
+		if !crypto.MatchPrefixBits(addr, w.config.PrefixBitsOf, w.config.PrefixBits) {
+			return false
+		}
+	}
+	if w.config.AllSame {
+		hasCriteria = true
+		if !crypto.IsAllSameNibbleBytes(addr) {
+			return false
+		}
+	}
+	if w.config.Palindrome {
+		hasCriteria = true
+		if !crypto.IsPalindromeBytes(addr) {
+			return false
+		}
+		// Only nibble palindromes reach the checksum, so the string is rarely built
+		if w.config.PalindromeCS && !crypto.IsPalindrome(crypto.AddressBytesToChecksumString(addr)) {
+			return false
+		}
+	}
+	if w.config.MatchExpr != nil {
+		hasCriteria = true
+		if !w.config.MatchExpr.Match(addr) {
+			return false
+		}
+	}
+	if w.config.NumericProperty != nil {
+		hasCriteria = true
+		if !w.config.NumericProperty.Match(addr) {
+			return false
+		}
+	}
+	if set := w.config.NiceSet; set != nil {
+		hasCriteria = true
+		if set.Score(addr) < set.Threshold {
+			return false
+		}
+	}
+	return hasCriteria
+}
+
+// prefixNibbles counts the leading prefix characters the current address matches, for tier tracking
+func (w *Worker) prefixNibbles() int {
+	if !w.config.TrackTiers {
+		return 0
+	}
+	addr := w.addrBuf
+	if w.config.PrefixOffset > 0 {
+		addr = crypto.ShiftNibbles(addr[:], w.config.PrefixOffset)
+	}
+	return crypto.MatchingPrefixNibbles(addr[:], w.config.PrefixBytes)
+}
+
+// matchPrefix compares the prefix against the address starting PrefixOffset nibbles in.
+// An odd offset straddles bytes, so each prefix byte is rebuilt from two address nibbles.
+func (w *Worker) matchPrefix(addr []byte) bool {
+	prefix := w.config.PrefixBytes
+	off := w.config.PrefixOffset
+	start := off / 2
+	n := min(len(prefix), (40-off)/2)
+	if off%2 == 0 {
+		return equalBytes(addr[start:start+n], prefix[:n])
+	}
+	for i := 0; i < n; i++ {
+		if addr[start+i]<<4|addr[start+i+1]>>4 != prefix[i] {
 			return false
 		}
 	}
 	return true
 }
 
+// matchSuffix compares the last nibbles of a raw 20-byte address against the suffix,
+// starting from the final byte so most candidates are rejected after a single compare.
+func (w *Worker) matchSuffix(addr []byte) bool {
+	suffix := w.config.SuffixBytes
+	n := len(suffix)
+	if n > 20 {
+		suffix = suffix[n-20:]
+		n = 20
+	}
+	tail := addr[20-n:]
+	for i := n - 1; i > 0; i-- {
+		if tail[i] != suffix[i] {
+			return false
+		}
+	}
+	if w.config.SuffixOdd {
+		return tail[0]&0x0f == suffix[0]&0x0f
+	}
+	return tail[0] == suffix[0]
+}
+
 func equalBytes(a, b []byte) bool {
 	if len(a) != len(b) {
 		return false
@@ -145,6 +481,7 @@ func equalBytes(a, b []byte) bool {
 
 // ProcessBatch processes a batch of address generations (legacy; miner uses GenerateAddress in loop)
 func (w *Worker) ProcessBatch(batchSize int) *types.WorkerResult {
+	defer w.Flush()
 	for i := 0; i < batchSize; i++ {
 		r := w.GenerateAddress()
 		if r.IsMatch {
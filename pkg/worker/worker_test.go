@@ -1,8 +1,13 @@
 package worker
 
 import (
+	"bytes"
+	"encoding/hex"
+	"strings"
 	"testing"
 
+	"github.com/screa/erc2470-address-miner/internal/config"
+	"github.com/screa/erc2470-address-miner/internal/crypto"
 	"github.com/screa/erc2470-address-miner/pkg/types"
 )
 
@@ -83,3 +88,488 @@ func TestWorkerMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateAddressCreateX(t *testing.T) {
+	sender, _ := crypto.MustAddressBytes("0x000000000000000000000000000000000000dEaD")
+	factory, _ := crypto.MustAddressBytes(crypto.CreateXAddress)
+	prefix := crypto.Create2PrefixFor(factory)
+	initcodeHash := crypto.Keccak256([]byte{0x60, 0x80})
+
+	config := &types.WorkerConfig{
+		Create2Prefix: prefix[:],
+		Create2Suffix: initcodeHash,
+		UseCreateX:    true,
+		CreateXGuard:  crypto.GuardMsgSender,
+		CreateXSender: sender,
+	}
+	attempts := int64(0)
+	result := NewWorker(config, &attempts).GenerateAddress()
+
+	if !bytes.Equal(result.SaltBytes[0:20], sender) || result.SaltBytes[20] != 0x00 {
+		t.Fatalf("salt %x does not carry the msgsender guard flags", result.SaltBytes)
+	}
+
+	// Recompute: guarded = keccak256(pad32(sender) || salt), then CREATE2 from CreateX
+	word := make([]byte, 32)
+	copy(word[12:], sender)
+	guarded := crypto.Keccak256(append(word, result.SaltBytes[:]...))
+	preimage := append(append(prefix[:], guarded...), initcodeHash...)
+	want := crypto.Keccak256(preimage)[12:]
+	if !bytes.Equal(result.AddressBytes[:], want) {
+		t.Errorf("address = %x, want %x", result.AddressBytes, want)
+	}
+}
+
+func TestGenerateAddressIncremental(t *testing.T) {
+	prefix := crypto.Create2PrefixBytes()
+	initcodeHash := crypto.Keccak256([]byte{0x60, 0x80})
+	config := &types.WorkerConfig{
+		Create2Prefix:     prefix[:],
+		Create2Suffix:     initcodeHash,
+		IncrementalKeccak: true,
+	}
+	attempts := int64(0)
+	w := NewWorker(config, &attempts)
+	for i := 0; i < 16; i++ {
+		result := w.GenerateAddress()
+		want := crypto.CalculateCreate2AddressFor(prefix[1:], initcodeHash, result.SaltBytes[:])
+		if got := crypto.AddressBytesToChecksumString(result.AddressBytes[:]); got != want {
+			t.Fatalf("salt %x gives %s, want %s", result.SaltBytes, got, want)
+		}
+	}
+}
+
+func TestGenerateAddressIncrementalExtras(t *testing.T) {
+	prefix := crypto.Create2PrefixBytes()
+	initcodeHash := crypto.Keccak256([]byte{0x60, 0x80})
+	secondHash := crypto.Keccak256([]byte{0x60, 0x81})
+	arachnid, _ := crypto.MustAddressBytes(crypto.ArachnidFactoryAddress)
+	arachnidPrefix := crypto.Create2PrefixFor(arachnid)
+
+	// The extra checks rehash the full preimage, so must see the same salt and init code as
+	// the sponge did
+	for _, tt := range []struct {
+		name   string
+		config types.WorkerConfig
+	}{
+		{"extra factory", types.WorkerConfig{
+			ExtraFactories: []types.FactoryTarget{{Address: crypto.ArachnidFactoryAddress, Create2Prefix: arachnidPrefix[:]}},
+			FactoryAddress: crypto.FactoryAddress,
+		}},
+		{"extra init code", types.WorkerConfig{
+			ExtraSuffixes: [][]byte{secondHash},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Create2Prefix = prefix[:]
+			config.Create2Suffix = initcodeHash
+			config.PrefixBytes = []byte{0xde}
+			config.IncrementalKeccak = true
+			attempts := int64(0)
+			w := NewWorker(&config, &attempts)
+			for i := 0; i < 1<<21; i++ {
+				result := w.GenerateAddress()
+				// Only a match under the extra factory exercises its check
+				if !result.IsMatch || len(config.ExtraFactories) > 0 && result.Factory != crypto.ArachnidFactoryAddress {
+					continue
+				}
+				factory := prefix[1:]
+				if len(config.ExtraFactories) > 0 {
+					factory = arachnid
+				}
+				if want := crypto.CalculateCreate2AddressFor(factory, initcodeHash, result.SaltBytes[:]); result.Address != want {
+					t.Fatalf("salt %x under %s gives %s, want %s", result.SaltBytes, result.Factory, result.Address, want)
+				}
+				for j, hash := range config.ExtraSuffixes {
+					if want := crypto.CalculateCreate2AddressFor(factory, hash, result.SaltBytes[:]); result.ExtraAddresses[j] != want {
+						t.Fatalf("salt %x under init code %d gives %s, want %s", result.SaltBytes, j+1, result.ExtraAddresses[j], want)
+					}
+				}
+				return
+			}
+			t.Fatal("no match")
+		})
+	}
+}
+
+func TestSequentialSaltsAfterResumePoint(t *testing.T) {
+	config := &types.WorkerConfig{
+		Create2Prefix: make([]byte, 21),
+		Create2Suffix: make([]byte, 32),
+	}
+	resume, err := crypto.ParseHexSalt("0x01ff")
+	if err != nil {
+		t.Fatalf("ParseHexSalt() error = %v", err)
+	}
+
+	// Two workers interleave the keyspace starting just after the resume point
+	const workers = 2
+	attempts := int64(0)
+	seen := make(map[[32]byte]bool)
+	for id := 0; id < workers; id++ {
+		start := resume
+		crypto.AddToSalt(&start, uint64(id)+1)
+		w := NewWorker(config, &attempts)
+		w.SetSaltCursor(start, workers)
+
+		for i := 0; i < 3; i++ {
+			salt := w.GenerateAddress().SaltBytes
+			if bytes.Compare(salt[:], resume[:]) <= 0 {
+				t.Errorf("worker %d salt %x is not greater than resume point %x", id, salt, resume)
+			}
+			seen[salt] = true
+		}
+	}
+
+	// Salts 0x0200..0x0205 are each generated exactly once
+	for n := uint64(1); n <= 6; n++ {
+		want := resume
+		crypto.AddToSalt(&want, n)
+		if !seen[want] {
+			t.Errorf("salt %x was not generated", want)
+		}
+	}
+}
+
+func TestMatchSuffixNibbles(t *testing.T) {
+	addr20 := []byte{0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78}
+	tests := []struct {
+		name     string
+		suffix   []byte
+		odd      bool
+		expected bool
+	}{
+		{"even suffix", []byte{0x56, 0x78}, false, true},
+		{"odd suffix", []byte{0x06, 0x78}, true, true},
+		{"odd suffix ignores padding nibble", []byte{0xf6, 0x78}, true, true},
+		{"odd suffix mismatch", []byte{0x07, 0x78}, true, false},
+		{"last byte mismatch", []byte{0x56, 0x79}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := int64(0)
+			w := NewWorker(&types.WorkerConfig{SuffixBytes: tt.suffix, SuffixOdd: tt.odd}, &attempts)
+			if !w.suffixOnly {
+				t.Fatal("suffix-only config did not select the suffix fast path")
+			}
+			if got := w.matchSuffix(addr20); got != tt.expected {
+				t.Errorf("matchSuffix() = %v, want %v", got, tt.expected)
+			}
+			if got := w.matchesBytes(addr20); got != tt.expected {
+				t.Errorf("matchesBytes() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchPrefixOffset(t *testing.T) {
+	addr20 := []byte{0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78}
+	tests := []struct {
+		name     string
+		prefix   []byte
+		offset   int
+		expected bool
+	}{
+		{"offset 0", []byte{0x12, 0x34}, 0, true},
+		{"offset 0 mismatch", []byte{0x34, 0x56}, 0, false},
+		{"offset 2", []byte{0x34, 0x56}, 2, true},
+		{"offset 2 ignores first byte only", []byte{0x12, 0x34}, 2, false},
+		{"odd offset straddles bytes", []byte{0x23, 0x45}, 1, true},
+		{"odd offset mismatch", []byte{0x34, 0x56}, 1, false},
+		{"prefix ending at the last nibble", []byte{0x56, 0x78}, 36, true},
+		{"odd offset ending at the last nibble", []byte{0x45, 0x67}, 35, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := int64(0)
+			w := NewWorker(&types.WorkerConfig{PrefixBytes: tt.prefix, PrefixOffset: tt.offset}, &attempts)
+			if got := w.matchesBytes(addr20); got != tt.expected {
+				t.Errorf("matchesBytes() with prefix %x at offset %d = %v, want %v", tt.prefix, tt.offset, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHybridSaltsUnique(t *testing.T) {
+	// Every worker gets the same seed, as after a restart with a repeated RNG seed
+	newWorker := func(stamp uint64, id uint32) *Worker {
+		attempts := int64(0)
+		w := NewWorker(&types.WorkerConfig{Entropy: bytes.NewReader(make([]byte, 8))}, &attempts)
+		w.SetHybridSalts(stamp, id)
+		return w
+	}
+	workers := []*Worker{
+		newWorker(1000, 0), newWorker(1000, 1), // two workers in one run
+		newWorker(2000, 0), newWorker(2000, 1), // the same workers after a restart
+	}
+
+	seen := make(map[[32]byte]bool)
+	for _, w := range workers {
+		for i := 0; i < 1000; i++ {
+			w.nextSalt()
+			if seen[w.saltBuf] {
+				t.Fatalf("duplicate salt %x", w.saltBuf)
+			}
+			seen[w.saltBuf] = true
+		}
+	}
+
+	// The layout carries the stamp, worker ID and counter after 12 random bytes
+	w := newWorker(0x0102030405060708, 7)
+	w.nextSalt()
+	w.nextSalt()
+	if got := hex.EncodeToString(w.saltBuf[12:]); got != "0102030405060708"+"00000007"+"0000000000000001" {
+		t.Errorf("salt tail = %s, want stamp, worker ID and counter 1", got)
+	}
+}
+
+func TestSaltLabel(t *testing.T) {
+	label := []byte("TREASURY")
+	config := &types.WorkerConfig{
+		Create2Prefix: make([]byte, 21),
+		Create2Suffix: make([]byte, 32),
+		SaltLabel:     label,
+	}
+	attempts := int64(0)
+	random := NewWorker(config, &attempts)
+	sequential := NewWorker(config, &attempts)
+	sequential.SetSaltCursor([32]byte{31: 0x10}, 1)
+
+	for _, w := range []*Worker{random, sequential} {
+		seen := make(map[[32]byte]bool)
+		for i := 0; i < 100; i++ {
+			result := w.GenerateAddress()
+			if !bytes.Equal(result.SaltBytes[:len(label)], label) {
+				t.Fatalf("salt %x does not start with the label", result.SaltBytes)
+			}
+			// The address is computed from the labelled salt
+			preimage := append(append(make([]byte, 21), result.SaltBytes[:]...), make([]byte, 32)...)
+			if !bytes.Equal(result.AddressBytes[:], crypto.Keccak256(preimage)[12:]) {
+				t.Fatalf("address %x is not derived from salt %x", result.AddressBytes, result.SaltBytes)
+			}
+			seen[result.SaltBytes] = true
+		}
+		if len(seen) != 100 {
+			t.Errorf("%d distinct salts in 100, want the bytes after the label to vary", len(seen))
+		}
+	}
+}
+
+func TestSaltBytes(t *testing.T) {
+	const n = 3
+	config := &types.WorkerConfig{
+		Create2Prefix: make([]byte, 21),
+		Create2Suffix: make([]byte, 32),
+		SaltBytes:     n,
+	}
+	attempts := int64(0)
+	random := NewWorker(config, &attempts)
+	sequential := NewWorker(config, &attempts)
+	sequential.SetSaltCursor([32]byte{31: 0x10}, 1)
+
+	for _, w := range []*Worker{random, sequential} {
+		seen := make(map[[32]byte]bool)
+		var varied [32]byte
+		for i := 0; i < 1000; i++ {
+			result := w.GenerateAddress()
+			if !bytes.Equal(result.SaltBytes[:32-n], make([]byte, 32-n)) {
+				t.Fatalf("salt %x varies outside its last %d bytes", result.SaltBytes, n)
+			}
+			// The address is computed from the zero-padded salt
+			preimage := append(append(make([]byte, 21), result.SaltBytes[:]...), make([]byte, 32)...)
+			if !bytes.Equal(result.AddressBytes[:], crypto.Keccak256(preimage)[12:]) {
+				t.Fatalf("address %x is not derived from salt %x", result.AddressBytes, result.SaltBytes)
+			}
+			for j, b := range result.SaltBytes {
+				varied[j] |= b
+			}
+			seen[result.SaltBytes] = true
+		}
+		if len(seen) < 990 {
+			t.Errorf("%d distinct salts in 1000, want the last %d bytes to vary", len(seen), n)
+		}
+		if w == random && (varied[32-n] == 0 || varied[31] == 0) {
+			t.Errorf("random salts never set bits in some of their last %d bytes: %x", n, varied)
+		}
+	}
+}
+
+func TestMatchPrefixBytes(t *testing.T) {
+	// 0x0dead0...: "dead" starts one nibble in, straddling the first three bytes
+	addr := make([]byte, 20)
+	copy(addr, []byte{0x0d, 0xea, 0xd0})
+	attempts := int64(0)
+
+	nibbles := NewWorker(&types.WorkerConfig{PrefixBytes: []byte{0xde, 0xad}, PrefixOffset: 1}, &attempts)
+	if !nibbles.matchesBytes(addr) {
+		t.Error("--prefix dead --prefix-offset 1 should match 0x0dead across byte boundaries")
+	}
+	for _, tt := range []struct {
+		prefix []byte
+		want   bool
+	}{
+		{[]byte{0xde, 0xad}, false},
+		{[]byte{0x0d, 0xea}, true},
+		{[]byte{0x0d, 0xea, 0xd0}, true},
+		{[]byte{0x0d, 0xea, 0xd1}, false},
+	} {
+		w := NewWorker(&types.WorkerConfig{AlignedPrefix: tt.prefix}, &attempts)
+		if got := w.matchesBytes(addr); got != tt.want {
+			t.Errorf("--prefix-bytes %x on %x = %v, want %v", tt.prefix, addr[:3], got, tt.want)
+		}
+	}
+}
+
+func TestPrefixNibbles(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     [20]byte
+		offset   int
+		expected int
+	}{
+		{"whole bytes", [20]byte{0xde, 0xad, 0x00}, 0, 4},
+		{"half byte", [20]byte{0xde, 0xa0}, 0, 3},
+		{"first nibble differs", [20]byte{0x0e, 0xad}, 0, 0},
+		{"odd offset", [20]byte{0x0d, 0xea, 0xd0}, 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := int64(0)
+			w := NewWorker(&types.WorkerConfig{PrefixBytes: []byte{0xde, 0xad}, PrefixOffset: tt.offset, TrackTiers: true}, &attempts)
+			w.addrBuf = tt.addr
+			if got := w.prefixNibbles(); got != tt.expected {
+				t.Errorf("prefixNibbles() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchAllSame(t *testing.T) {
+	attempts := int64(0)
+	w := NewWorker(&types.WorkerConfig{AllSame: true}, &attempts)
+	if w.suffixOnly {
+		t.Fatal("all-same config selected the suffix fast path")
+	}
+	if !w.matchesBytes(bytes.Repeat([]byte{0x77}, 20)) {
+		t.Error("matchesBytes() = false for an all-7 address")
+	}
+	if w.matchesBytes(bytes.Repeat([]byte{0x78}, 20)) {
+		t.Error("matchesBytes() = true for an address alternating 7 and 8")
+	}
+}
+
+// FuzzMatches drives the byte-level matcher with any prefix, suffix and offset the config
+// accepts, checking it against a plain string comparison of the hex address. Run with:
+// go test -fuzz=FuzzMatches ./pkg/worker
+func FuzzMatches(f *testing.F) {
+	f.Add(bytes.Repeat([]byte{0xab}, 20), "abab", "", 0)
+	f.Add(bytes.Repeat([]byte{0xab}, 20), "0xBABA", "bab", 1)
+	f.Add(bytes.Repeat([]byte{0x00}, 20), "00", "0", 38)
+	f.Add(bytes.Repeat([]byte{0xff}, 20), "", "f", 0)
+	f.Add(bytes.Repeat([]byte{0x12}, 20), "1212121212121212121212121212121212121212", "12", 0)
+	f.Add([]byte{0x12, 0x34}, "12", "34", 0)
+	f.Add(bytes.Repeat([]byte{0x01}, 21), "0x", "0x", -1)
+	f.Add(bytes.Repeat([]byte{0x30}, 20), "1", "b", 1)
+	f.Add(bytes.Repeat([]byte{0xab}, 20), "0XAB", "0Xb", 0)
+
+	f.Fuzz(func(t *testing.T, addr []byte, prefix, suffix string, offset int) {
+		cfg := config.NewConfig()
+		cfg.Prefix = prefix
+		cfg.Suffix = suffix
+		cfg.PrefixOffset = offset
+		cfg.Bytecode = "6080"
+		if cfg.Prefix == "" && cfg.Suffix == "" || cfg.Validate() != nil {
+			return
+		}
+		// Decode the way the miner does; Validate guarantees these succeed
+		wc := &types.WorkerConfig{PrefixOffset: offset}
+		var err error
+		if cfg.Prefix != "" {
+			if wc.PrefixBytes, err = crypto.HexToAddressBytes(cfg.Prefix); err != nil {
+				t.Fatalf("valid prefix %q does not decode: %v", cfg.Prefix, err)
+			}
+		}
+		if s := strings.TrimPrefix(cfg.Suffix, "0x"); s != "" {
+			if len(s)%2 != 0 {
+				s = "0" + s
+				wc.SuffixOdd = true
+			}
+			if wc.SuffixBytes, err = crypto.HexToAddressBytes(s); err != nil {
+				t.Fatalf("valid suffix %q does not decode: %v", cfg.Suffix, err)
+			}
+		}
+		p := strings.TrimPrefix(strings.ToLower(cfg.Prefix), "0x")
+		s := strings.TrimPrefix(strings.ToLower(cfg.Suffix), "0x")
+
+		attempts := int64(0)
+		w := NewWorker(wc, &attempts)
+		got := w.matchesBytes(addr)
+		if len(addr) != 20 {
+			if got {
+				t.Fatalf("matchesBytes(%x) = true for a %d-byte address", addr, len(addr))
+			}
+			return
+		}
+		h := hex.EncodeToString(addr)
+		want := strings.HasPrefix(h[offset:], p) && strings.HasSuffix(h, s)
+		if got != want {
+			t.Fatalf("matchesBytes(%s) = %v with prefix %q at %d and suffix %q, want %v", h, got, p, offset, s, want)
+		}
+	})
+}
+
+func BenchmarkSuffixMatch(b *testing.B) {
+	attempts := int64(0)
+	w := NewWorker(&types.WorkerConfig{SuffixBytes: []byte{0xbe, 0xef}}, &attempts)
+	addr := make([]byte, 20)
+
+	b.Run("general", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			addr[19] = byte(i)
+			w.matchesBytes(addr)
+		}
+	})
+	b.Run("suffix-only", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			addr[19] = byte(i)
+			w.matchSuffix(addr)
+		}
+	})
+}
+
+// BenchmarkGenerateAddress compares hashing the full CREATE2 preimage with each backend
+// against cloning a state that has already absorbed the prefix
+func BenchmarkGenerateAddress(b *testing.B) {
+	prefix := crypto.Create2PrefixBytes()
+	initcodeHash := crypto.Keccak256([]byte{0x60, 0x80})
+	for _, backend := range crypto.KeccakBackends() {
+		for _, incremental := range []bool{false, true} {
+			name := backend
+			if incremental {
+				if backend != crypto.KeccakGeneric {
+					continue
+				}
+				name += "-incremental"
+			}
+			newHasher, _ := crypto.KeccakHasherFactory(backend)
+			config := &types.WorkerConfig{
+				Create2Prefix:     prefix[:],
+				Create2Suffix:     initcodeHash,
+				NewHasher:         newHasher,
+				IncrementalKeccak: incremental,
+			}
+			b.Run(name, func(b *testing.B) {
+				attempts := int64(0)
+				w := NewWorker(config, &attempts)
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					w.GenerateAddress()
+				}
+			})
+		}
+	}
+}